@@ -0,0 +1,81 @@
+package pathlib
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// AccessThresholds decides how ClassifyByAccess buckets files by when they were last used, which is the later of their access and modification times.
+type AccessThresholds struct {
+	// Hot files were used within this long.
+	Hot time.Duration
+
+	// Warm files were used within this long, but not within Hot.  Files used longer ago are cold.
+	Warm time.Duration
+}
+
+// AccessClass is a set of files with the same access class, and their total size.
+type AccessClass struct {
+	Files []Path
+	Size  int64
+}
+
+// AccessClasses holds the files of a tree bucketed by ClassifyByAccess.
+type AccessClasses struct {
+	Hot  AccessClass
+	Warm AccessClass
+	Cold AccessClass
+}
+
+// ClassifyByAccess walks the directory Path and buckets its regular files into hot, warm, and cold by how recently they were last used, to inform tiering and cleanup.  Symbolic links are not followed.  Access times are only as good as the filesystem keeps them: with relatime (the Linux default) they are updated at most once a day, with noatime never, so on such filesystems classification mostly reflects modification times.
+func (p Path) ClassifyByAccess(thresholds AccessThresholds) (AccessClasses, error) {
+	var classes AccessClasses
+
+	if thresholds.Hot < 0 || thresholds.Warm < thresholds.Hot {
+		return classes, fmt.Errorf("The warm threshold (%s) cannot be shorter than the hot threshold (%s)", thresholds.Warm, thresholds.Hot)
+	}
+
+	if !p.IsDir() {
+		return classes, fmt.Errorf("ClassifyByAccess only works on directories: %s", p)
+	}
+
+	now := time.Now()
+
+	err := filepath.WalkDir(string(p), func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+
+		if err != nil {
+			return err
+		}
+
+		used := accessTime(info)
+
+		if info.ModTime().After(used) {
+			used = info.ModTime()
+		}
+
+		class := &classes.Cold
+
+		if age := now.Sub(used); age <= thresholds.Hot {
+			class = &classes.Hot
+		} else if age <= thresholds.Warm {
+			class = &classes.Warm
+		}
+
+		class.Files = append(class.Files, Path(name))
+		class.Size += info.Size()
+		return nil
+	})
+
+	return classes, err
+}
@@ -0,0 +1,64 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestClassifyByAccess(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{
+		{Path: "hot", Content: "h"},
+		{Path: "sub/warm", Content: "ww"},
+		{Path: "sub/cold", Content: "ccc"},
+		{Path: "read", Content: "rrrr"},
+		{Path: "link", Symlink: "cold"},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	now := time.Now()
+	times := map[Path][2]time.Time{ // access and modification times
+		"sub/warm": {now.Add(-48 * time.Hour), now.Add(-48 * time.Hour)},
+		"sub/cold": {now.Add(-60 * 24 * time.Hour), now.Add(-60 * 24 * time.Hour)},
+		"read":     {now.Add(-time.Hour), now.Add(-60 * 24 * time.Hour)}, // recently read, so hot
+	}
+
+	for name, ts := range times {
+		if err = os.Chtimes(string(dir.JoinPath(name)), ts[0], ts[1]); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	classes, err := dir.ClassifyByAccess(AccessThresholds{Hot: 24 * time.Hour, Warm: 30 * 24 * time.Hour})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := map[string]struct {
+		class AccessClass
+		files int
+		size  int64
+	}{
+		"hot":  {classes.Hot, 2, 5},
+		"warm": {classes.Warm, 1, 2},
+		"cold": {classes.Cold, 1, 3},
+	}
+
+	for name, test := range tests {
+		if len(test.class.Files) != test.files || test.class.Size != test.size {
+			t.Errorf("Expected %d %s files of %d bytes, received %+v", test.files, name, test.size, test.class)
+		}
+	}
+
+	if _, err = dir.ClassifyByAccess(AccessThresholds{Hot: time.Hour, Warm: time.Minute}); err == nil {
+		t.Errorf("Expected an error for a warm threshold shorter than the hot one")
+	}
+}
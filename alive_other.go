@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package pathlib
+
+// processAlive reports true, since whether a process is running cannot be checked on this platform.
+func processAlive(pid int) bool {
+	return true
+}
@@ -0,0 +1,26 @@
+package pathlib
+
+import "syscall"
+
+const (
+	processQueryLimitedInformation = 0x1000
+	stillActive                    = 259
+)
+
+// processAlive reports whether a process with the ID is running on this host.
+func processAlive(pid int) bool {
+	handle, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+
+	if err != nil {
+		return err == syscall.ERROR_ACCESS_DENIED
+	}
+
+	defer syscall.CloseHandle(handle)
+	var code uint32
+
+	if err = syscall.GetExitCodeProcess(handle, &code); err != nil {
+		return true
+	}
+
+	return code == stillActive
+}
@@ -0,0 +1,18 @@
+package pathlib
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// accessTime returns the last access time recorded in the FileInfo, falling back to the modification time.
+func accessTime(info os.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+
+	if !ok {
+		return info.ModTime()
+	}
+
+	return time.Unix(int64(stat.Atimespec.Sec), int64(stat.Atimespec.Nsec))
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+package pathlib
+
+import (
+	"os"
+	"time"
+)
+
+// accessTime returns the modification time, since access times are not portably available on this platform.
+func accessTime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
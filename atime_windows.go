@@ -0,0 +1,18 @@
+package pathlib
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// accessTime returns the last access time recorded in the FileInfo, falling back to the modification time.
+func accessTime(info os.FileInfo) time.Time {
+	data, ok := info.Sys().(*syscall.Win32FileAttributeData)
+
+	if !ok {
+		return info.ModTime()
+	}
+
+	return time.Unix(0, data.LastAccessTime.Nanoseconds())
+}
@@ -0,0 +1,251 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// SnapshotTimeFormat is the time layout (in UTC) used to name the snapshots created by BackupSnapshot, so that their names sort chronologically.
+const SnapshotTimeFormat = "2006-01-02T150405.000Z"
+
+// snapshotPartialPrefix marks snapshots that are still being written, so an interrupted backup is never used as the basis for the next one.
+const snapshotPartialPrefix = ".partial-"
+
+// BackupReport describes the snapshot made by BackupSnapshot.
+type BackupReport struct {
+	// Snapshot is the directory holding the new snapshot.
+	Snapshot Path
+
+	// Previous is the snapshot that unchanged files were hard linked from, or empty if this is the first.
+	Previous Path
+
+	// Linked and Copied count the files that were hard linked from Previous and copied from the source.
+	Linked int
+	Copied int
+
+	// BytesLinked and BytesCopied are the total sizes of the linked and copied files.
+	BytesLinked int64
+	BytesCopied int64
+
+	// Skipped lists the files that were not backed up because they are not regular files, directories, or symbolic links.
+	Skipped []Path
+}
+
+// RetentionPolicy decides which snapshots PruneSnapshots keeps.  A snapshot is kept if any rule keeps it.
+type RetentionPolicy struct {
+	// KeepLast keeps the most recent snapshots.
+	KeepLast int
+
+	// KeepWithin keeps the snapshots taken within this long of the most recent one.
+	KeepWithin time.Duration
+
+	// KeepDaily, KeepWeekly, and KeepMonthly keep the most recent snapshot of each of that many of the latest days, ISO weeks, and months that have snapshots.
+	KeepDaily   int
+	KeepWeekly  int
+	KeepMonthly int
+}
+
+// BackupSnapshot makes a new snapshot of the directory src in a timestamped directory within backupRoot, in the style of rsync --link-dest.  Files that are unchanged since the previous snapshot (with the same size, modification time, and permissions) are hard linked to it rather than copied, so every snapshot is a complete tree but only costs the space of what changed.  Permissions and modification times are preserved.  The snapshot is written under a temporary name and only renamed into place once it is complete.
+func BackupSnapshot(src, backupRoot Path) (BackupReport, error) {
+	var report BackupReport
+
+	if !src.IsDir() {
+		return report, fmt.Errorf("BackupSnapshot only works on directories: %s", src)
+	}
+
+	if absRoot, err := filepath.Abs(string(backupRoot)); err == nil {
+		if absSrc, err := filepath.Abs(string(src)); err == nil && Path(absRoot).within(Path(absSrc)) {
+			return report, fmt.Errorf("Cannot back up %s into %s because it is within it", src, backupRoot)
+		}
+	}
+
+	if err := os.MkdirAll(string(backupRoot), 0755); err != nil {
+		return report, err
+	}
+
+	snapshots, err := Snapshots(backupRoot)
+
+	if err != nil {
+		return report, err
+	}
+
+	if len(snapshots) > 0 {
+		report.Previous = snapshots[len(snapshots)-1]
+	}
+
+	name := time.Now().UTC().Format(SnapshotTimeFormat)
+	report.Snapshot = backupRoot.JoinPath(Path(name))
+
+	if report.Snapshot.lexists() {
+		return report, fmt.Errorf("Cannot create snapshot %s: %w", report.Snapshot, os.ErrExist)
+	}
+
+	partial := backupRoot.JoinPath(Path(snapshotPartialPrefix + name))
+	os.RemoveAll(string(partial))
+	o := newCopyOptions([]CopyOption{PreserveMode(), PreserveTimes()})
+	info, err := os.Stat(string(src))
+
+	if err == nil {
+		err = o.backupDir(src, partial, report.Previous, info, &report)
+	}
+
+	if err == nil {
+		err = os.Rename(string(partial), string(report.Snapshot))
+	}
+
+	if err != nil {
+		os.RemoveAll(string(partial))
+	}
+
+	return report, err
+}
+
+func (o copyOptions) backupDir(src, dst, previous Path, info os.FileInfo, report *BackupReport) error {
+	// the owner needs full access to fill the snapshot; its real permissions are applied afterwards
+	if err := os.Mkdir(string(dst), info.Mode().Perm()|0700); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(string(src))
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := Path(entry.Name())
+		child := src.JoinPath(name)
+		childInfo, err := os.Lstat(string(child))
+
+		if err != nil {
+			return err
+		}
+
+		var prev Path
+
+		if len(previous) > 0 {
+			prev = previous.JoinPath(name)
+		}
+
+		switch {
+		case childInfo.IsDir():
+			err = o.backupDir(child, dst.JoinPath(name), prev, childInfo, report)
+		case childInfo.Mode()&os.ModeSymlink != 0:
+			err = o.copy(child, dst.JoinPath(name), childInfo, nil)
+		case childInfo.Mode().IsRegular():
+			err = o.backupFile(child, dst.JoinPath(name), prev, childInfo, report)
+		default:
+			report.Skipped = append(report.Skipped, child)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return o.applyMetadata(src, dst, info)
+}
+
+// backupFile hard links the file from the previous snapshot if it is unchanged, and copies it otherwise.
+func (o copyOptions) backupFile(src, dst, previous Path, info os.FileInfo, report *BackupReport) error {
+	if len(previous) > 0 {
+		prevInfo, err := os.Lstat(string(previous))
+
+		if err == nil && prevInfo.Mode() == info.Mode() && prevInfo.Size() == info.Size() && prevInfo.ModTime().Equal(info.ModTime()) {
+			// fall back to copying if linking fails, such as when the file has reached its link limit
+			if os.Link(string(previous), string(dst)) == nil {
+				report.Linked++
+				report.BytesLinked += info.Size()
+				return nil
+			}
+		}
+	}
+
+	if err := o.copy(src, dst, info, nil); err != nil {
+		return err
+	}
+
+	report.Copied++
+	report.BytesCopied += info.Size()
+	return nil
+}
+
+// Snapshots returns the completed snapshots made by BackupSnapshot within backupRoot, oldest first.
+func Snapshots(backupRoot Path) ([]Path, error) {
+	entries, err := os.ReadDir(string(backupRoot))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []Path
+
+	for _, entry := range entries {
+		if _, err := time.Parse(SnapshotTimeFormat, entry.Name()); err == nil && entry.IsDir() {
+			snapshots = append(snapshots, backupRoot.JoinPath(Path(entry.Name())))
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i] < snapshots[j] })
+	return snapshots, nil
+}
+
+// PruneSnapshots removes the snapshots within backupRoot that the policy does not keep, returning the ones removed.  Since unchanged files are shared between snapshots by hard links, removing a snapshot only frees the space of the files no other snapshot shares.  A policy that would keep nothing is rejected.
+func PruneSnapshots(backupRoot Path, policy RetentionPolicy) ([]Path, error) {
+	if policy == (RetentionPolicy{}) {
+		return nil, fmt.Errorf("Cannot prune %s with a retention policy that keeps no snapshots", backupRoot)
+	}
+
+	snapshots, err := Snapshots(backupRoot)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keep := make([]bool, len(snapshots))
+	times := make([]time.Time, len(snapshots))
+
+	for i, snapshot := range snapshots {
+		times[i], _ = time.Parse(SnapshotTimeFormat, snapshot.Name())
+	}
+
+	buckets := []struct {
+		count int
+		key   func(time.Time) string
+	}{
+		{policy.KeepLast, func(t time.Time) string { return t.String() }},
+		{policy.KeepDaily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{policy.KeepWeekly, func(t time.Time) string { year, week := t.ISOWeek(); return fmt.Sprint(year, week) }},
+		{policy.KeepMonthly, func(t time.Time) string { return t.Format("2006-01") }},
+	}
+
+	for _, bucket := range buckets {
+		seen := map[string]bool{}
+
+		for i := len(snapshots) - 1; i >= 0 && len(seen) < bucket.count; i-- {
+			if key := bucket.key(times[i]); !seen[key] {
+				seen[key] = true
+				keep[i] = true
+			}
+		}
+	}
+
+	var removed []Path
+
+	for i, snapshot := range snapshots {
+		if keep[i] || times[len(times)-1].Sub(times[i]) < policy.KeepWithin {
+			continue
+		}
+
+		if err = os.RemoveAll(string(snapshot)); err != nil {
+			return removed, err
+		}
+
+		removed = append(removed, snapshot)
+	}
+
+	return removed, nil
+}
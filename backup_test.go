@@ -0,0 +1,122 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestBackupSnapshot(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+	src := dir.JoinPath("src")
+	backups := dir.JoinPath("backups")
+
+	err := CreateTree(src, TreeSpec{
+		{Path: "a.txt", Content: "aaaa"},
+		{Path: "sub/b.txt", Content: "bb", Perms: 0600},
+		{Path: "link", Symlink: "a.txt"},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	first, err := BackupSnapshot(src, backups)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if first.Previous != "" || first.Copied != 2 || first.Linked != 0 || first.BytesCopied != 6 {
+		t.Errorf("Unexpected first report %+v", first)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	old := time.Now().Add(-time.Hour)
+
+	if err = src.JoinPath("a.txt").WriteBytes([]byte("changed")); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	os.Chtimes(string(src.JoinPath("a.txt")), old, old)
+	second, err := BackupSnapshot(src, backups)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if second.Previous != first.Snapshot || second.Copied != 1 || second.Linked != 1 || second.BytesLinked != 2 {
+		t.Errorf("Unexpected second report %+v", second)
+	}
+
+	firstB, _ := os.Stat(string(first.Snapshot.JoinPath("sub", "b.txt")))
+	secondB, _ := os.Stat(string(second.Snapshot.JoinPath("sub", "b.txt")))
+
+	if !os.SameFile(firstB, secondB) || secondB.Mode().Perm() != 0600 {
+		t.Errorf("Expected the unchanged file to be hard linked with its permissions")
+	}
+
+	if data, _ := second.Snapshot.JoinPath("a.txt").ReadBytes(); string(data) != "changed" {
+		t.Errorf("Expected the changed file to be copied but found %q", data)
+	}
+
+	if link, _ := os.Readlink(string(second.Snapshot.JoinPath("link"))); link != "a.txt" {
+		t.Errorf("Expected the symlink to be recreated but found %q", link)
+	}
+
+	if snapshots, _ := Snapshots(backups); len(snapshots) != 2 {
+		t.Errorf("Expected 2 snapshots but found %v", snapshots)
+	}
+
+	if _, err = BackupSnapshot(src, src.JoinPath("backups")); err == nil {
+		t.Errorf("Expected an error backing up into the source")
+	}
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	names := []string{
+		"2024-01-15T120000.000Z",
+		"2024-02-10T120000.000Z",
+		"2024-03-01T080000.000Z",
+		"2024-03-01T200000.000Z",
+		"2024-03-02T120000.000Z",
+		"2024-03-03T120000.000Z",
+	}
+
+	for _, name := range append(names, "not-a-snapshot") {
+		if err := dir.JoinPath(Path(name)).Mkdir(); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	if _, err := PruneSnapshots(dir, RetentionPolicy{}); err == nil {
+		t.Errorf("Expected an error for an empty policy")
+	}
+
+	removed, err := PruneSnapshots(dir, RetentionPolicy{KeepLast: 1, KeepDaily: 2, KeepMonthly: 2})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	expected := fmt.Sprint([]Path{dir.JoinPath(Path(names[0])), dir.JoinPath(Path(names[2])), dir.JoinPath(Path(names[3]))})
+
+	if fmt.Sprint(removed) != expected {
+		t.Errorf("Expected %s to be removed but was %v", expected, removed)
+	}
+
+	if !dir.JoinPath("not-a-snapshot").Exists() {
+		t.Errorf("Expected other directories to be left alone")
+	}
+
+	removed, err = PruneSnapshots(dir, RetentionPolicy{KeepWithin: 48 * time.Hour})
+
+	if err != nil || len(removed) != 1 || removed[0] != dir.JoinPath(Path(names[1])) {
+		t.Errorf("Expected only the February snapshot to be removed but was %v (%v)", removed, err)
+	}
+}
@@ -0,0 +1,83 @@
+// Package benchmarks holds benchmarks of pathlib's hot paths (existence checks, globbing, pattern matching, walking, and copying) along with reproducible fixture trees to run them against, so that performance work such as caching can be measured in-repo.  Run them with "go test -bench . ./benchmarks", adding "-args -files N" to change the number of files in the large fixtures (100,000 by default).
+package benchmarks
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+
+	"github.com/gershwinlabs/pathlib"
+)
+
+// Fixture describes a generated tree of files.  The same Fixture always generates the same names, sizes, and contents.
+type Fixture struct {
+	Files    int   // total number of files
+	PerDir   int   // files per directory, or zero to put every file in the root
+	Depth    int   // how deeply directories are nested, with one level when zero
+	MaxSize  int   // largest file size in bytes, with empty files when zero
+	Seed     int64 // seeds the file sizes and contents
+	Suffixes []string
+}
+
+// Generate creates the tree of the Fixture beneath root, which must not exist yet.  Directories are named "d<level>-<n>" and files "f<n><suffix>", cycling through the Fixture's suffixes (".txt" by default).
+func (f Fixture) Generate(root pathlib.Path) error {
+	if root.Exists() {
+		return fmt.Errorf("Cannot generate a fixture in %s because it already exists: %w", root, os.ErrExist)
+	}
+
+	if err := os.MkdirAll(string(root), 0755); err != nil {
+		return err
+	}
+
+	suffixes := f.Suffixes
+
+	if len(suffixes) == 0 {
+		suffixes = []string{".txt"}
+	}
+
+	random := rand.New(rand.NewSource(f.Seed))
+	dir := root
+
+	for i := 0; i < f.Files; i++ {
+		if f.PerDir > 0 && i%f.PerDir == 0 {
+			dir = f.dirFor(root, i/f.PerDir)
+
+			if err := os.MkdirAll(string(dir), 0755); err != nil {
+				return err
+			}
+		}
+
+		var data []byte
+
+		if f.MaxSize > 0 {
+			data = make([]byte, random.Intn(f.MaxSize+1))
+			random.Read(data)
+		}
+
+		name := pathlib.Path(fmt.Sprintf("f%d%s", i, suffixes[i%len(suffixes)]))
+
+		if err := os.WriteFile(string(dir.JoinPath(name)), data, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dirFor returns the directory holding the nth group of files.  With several levels, the groups are spread over ten subdirectories per level by the digits of n.
+func (f Fixture) dirFor(root pathlib.Path, n int) pathlib.Path {
+	depth := max(f.Depth, 1)
+	dir := root
+
+	for level := 1; level < depth; level++ {
+		divisor := 1
+
+		for i := level; i < depth; i++ {
+			divisor *= 10
+		}
+
+		dir = dir.JoinPath(pathlib.Path(fmt.Sprintf("d%d-%d", level, n/divisor%10)))
+	}
+
+	return dir.JoinPath(pathlib.Path(fmt.Sprintf("d%d-%d", depth, n)))
+}
@@ -0,0 +1,223 @@
+package benchmarks
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/gershwinlabs/pathlib"
+)
+
+var files = flag.Int("files", 100000, "number of files in the large fixtures")
+
+var (
+	scratch     pathlib.Path
+	fixturesMu  sync.Mutex
+	fixtureDirs = map[string]pathlib.Path{}
+)
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	dir, err := os.MkdirTemp("", "pathlib-benchmarks")
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	scratch = pathlib.Path(dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// fixture returns the root of the generated Fixture, generating it the first time it is needed.
+func fixture(b *testing.B, f Fixture) pathlib.Path {
+	fixturesMu.Lock()
+	defer fixturesMu.Unlock()
+
+	key := fmt.Sprintf("%+v", f)
+
+	if root, ok := fixtureDirs[key]; ok {
+		return root
+	}
+
+	root := scratch.JoinPath(pathlib.Path(fmt.Sprintf("fixture%d", len(fixtureDirs))))
+
+	if err := f.Generate(root); err != nil {
+		b.Fatal(err)
+	}
+
+	fixtureDirs[key] = root
+	return root
+}
+
+func flat() Fixture {
+	return Fixture{Files: *files, Suffixes: []string{".txt", ".log", ".go", ".json"}}
+}
+
+func nested() Fixture {
+	return Fixture{Files: *files, PerDir: 100, Depth: 3, Suffixes: []string{".txt", ".log", ".go", ".json"}}
+}
+
+func TestFixture(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pathlib-fixture")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+	f := Fixture{Files: 250, PerDir: 10, Depth: 2, MaxSize: 64, Seed: 1, Suffixes: []string{".a", ".b"}}
+
+	for _, name := range []pathlib.Path{"one", "two"} {
+		if err = f.Generate(pathlib.Path(dir).JoinPath(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := pathlib.Path(dir).JoinPath("one").CountEntries(true)
+
+	if err != nil || count != 250+25+3 {
+		t.Errorf("Expected 250 files in 25 directories in 3 parents but counted %d (%v)", count, err)
+	}
+
+	same := pathlib.Path(dir).JoinPath("one", "d1-2", "d2-24", "f249.b")
+	other := pathlib.Path(dir).JoinPath("two", "d1-2", "d2-24", "f249.b")
+	first, err := same.ReadBytes()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if second, _ := other.ReadBytes(); string(first) != string(second) {
+		t.Errorf("Expected the same fixture to generate the same contents")
+	}
+
+	if err = f.Generate(pathlib.Path(dir).JoinPath("one")); err == nil {
+		t.Errorf("Expected an error generating into an existing directory")
+	}
+}
+
+func BenchmarkExists(b *testing.B) {
+	root := fixture(b, flat())
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if !root.JoinPath(pathlib.Path(fmt.Sprintf("f%d.txt", i%*files/4*4))).Exists() {
+			b.Fatal("fixture file is missing")
+		}
+	}
+}
+
+func BenchmarkExistsMissing(b *testing.B) {
+	root := fixture(b, flat())
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if root.JoinPath(pathlib.Path(fmt.Sprintf("missing%d", i))).Exists() {
+			b.Fatal("missing file exists")
+		}
+	}
+}
+
+func BenchmarkGlob(b *testing.B) {
+	root := fixture(b, flat())
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if matches, err := root.Glob("*.log"); err != nil || len(matches) != *files/4 {
+			b.Fatalf("found %d matches (%v)", len(matches), err)
+		}
+	}
+}
+
+func BenchmarkDirCacheGlob(b *testing.B) {
+	root := fixture(b, flat())
+	var cache pathlib.DirCache
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if matches, err := cache.Glob(root, "*.log"); err != nil || len(matches) != *files/4 {
+			b.Fatalf("found %d matches (%v)", len(matches), err)
+		}
+	}
+}
+
+func BenchmarkRGlob(b *testing.B) {
+	root := fixture(b, nested())
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if matches, err := root.RGlob("d3-*/*.log"); err != nil || len(matches) != *files/4 {
+			b.Fatalf("found %d matches (%v)", len(matches), err)
+		}
+	}
+}
+
+func BenchmarkIter(b *testing.B) {
+	root := fixture(b, nested())
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, err := range root.Iter() {
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// patternPaths returns Paths to match patterns against, without touching the filesystem.
+func patternPaths() []pathlib.Path {
+	paths := make([]pathlib.Path, 10000)
+
+	for i := range paths {
+		paths[i] = pathlib.Path(fmt.Sprintf("/srv/project/d%d/sub%d/file%d.%s", i%10, i%100, i, []string{"go", "txt", "log"}[i%3]))
+	}
+
+	return paths
+}
+
+func BenchmarkPathMatch(b *testing.B) {
+	paths := patternPaths()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		paths[i%len(paths)].Match("project/**/*.go")
+	}
+}
+
+func BenchmarkCompiledPatternMatch(b *testing.B) {
+	paths := patternPaths()
+	pattern, err := pathlib.CompilePattern("project/**/*.go")
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		pattern.Match(paths[i%len(paths)])
+	}
+}
+
+func BenchmarkCopyTree(b *testing.B) {
+	root := fixture(b, Fixture{Files: 1000, PerDir: 50, Depth: 2, MaxSize: 8192})
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		dst := scratch.JoinPath(pathlib.Path(fmt.Sprintf("copy%d", i)))
+
+		if err := root.CopyTree(dst, pathlib.PreserveMode(), pathlib.PreserveTimes()); err != nil {
+			b.Fatal(err)
+		}
+
+		b.StopTimer()
+		dst.RmdirRecursive()
+		b.StartTimer()
+	}
+}
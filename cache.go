@@ -0,0 +1,136 @@
+package pathlib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+)
+
+// Cache is a simple disk cache that stores each entry as a file within Dir, named by the hash of its key.
+type Cache struct {
+	// Dir holds the cache entries.  It is created on first use.
+	Dir Path
+
+	// TTL is how long an entry remains valid after it is filled.  Zero means entries never expire.
+	TTL time.Duration
+
+	// MaxSize is the total size in bytes the cache may grow to before the least recently used entries are evicted.  Zero means unlimited.
+	MaxSize int64
+}
+
+// EntryPath returns the Path where the entry for key is stored, whether or not it exists.
+func (c Cache) EntryPath(key string) Path {
+	sum := sha256.Sum256([]byte(key))
+	return c.Dir.JoinPath(Path(hex.EncodeToString(sum[:])))
+}
+
+// GetOrFill returns the Path of the cache entry for key.  If the entry is missing or older than TTL, fill is called to write its contents, which are stored atomically.  If fill returns an error, no entry is stored.
+func (c Cache) GetOrFill(key string, fill func(io.Writer) error) (Path, error) {
+	entry := c.EntryPath(key)
+
+	if entry.IsFile() {
+		age, err := entry.Age(time.Now())
+
+		if err == nil && (c.TTL == 0 || age < c.TTL) {
+			c.markUsed(entry)
+			return entry, nil
+		}
+	}
+
+	if !c.Dir.Exists() {
+		if err := c.Dir.Mkdir(); err != nil {
+			return entry, err
+		}
+	}
+
+	tmp, err := ioutil.TempFile(string(c.Dir), ".fill-")
+
+	if err != nil {
+		return entry, err
+	}
+
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	err = fill(tmp)
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return entry, err
+	}
+
+	if err = os.Rename(tmp.Name(), string(entry)); err != nil {
+		return entry, err
+	}
+
+	return entry, c.evict(entry)
+}
+
+// Remove deletes the entry for key, if present.
+func (c Cache) Remove(key string) error {
+	entry := c.EntryPath(key)
+
+	if !entry.Exists() {
+		return nil
+	}
+
+	return entry.Unlink()
+}
+
+// markUsed updates the access time of the entry without changing the modification time used for TTL.
+func (c Cache) markUsed(entry Path) {
+	stat, err := os.Stat(string(entry))
+
+	if err == nil {
+		os.Chtimes(string(entry), time.Now(), stat.ModTime())
+	}
+}
+
+// evict removes the least recently used entries until the cache fits within MaxSize.  The keep entry is never evicted.
+func (c Cache) evict(keep Path) error {
+	if c.MaxSize <= 0 {
+		return nil
+	}
+
+	infos, err := ioutil.ReadDir(string(c.Dir))
+
+	if err != nil {
+		return err
+	}
+
+	var total int64
+
+	for _, info := range infos {
+		total += info.Size()
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return accessTime(infos[i]).Before(accessTime(infos[j]))
+	})
+
+	for _, info := range infos {
+		if total <= c.MaxSize {
+			break
+		}
+
+		entry := c.Dir.JoinPath(Path(info.Name()))
+
+		if entry == keep || info.IsDir() {
+			continue
+		}
+
+		if err := entry.Unlink(); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		total -= info.Size()
+	}
+
+	return nil
+}
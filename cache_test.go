@@ -0,0 +1,125 @@
+package pathlib
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCacheGetOrFill(t *testing.T) {
+	c := Cache{Dir: Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20))), TTL: time.Hour}
+	defer c.Dir.RmdirRecursive()
+
+	fills := 0
+	fill := func(w io.Writer) error {
+		fills++
+		_, err := w.Write([]byte("cached"))
+		return err
+	}
+
+	for i := 0; i < 2; i++ {
+		entry, err := c.GetOrFill("key", fill)
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+
+		contents, err := entry.ReadBytes()
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+
+		if string(contents) != "cached" {
+			t.Errorf("Entry has contents %q", contents)
+		}
+	}
+
+	if fills != 1 {
+		t.Errorf("Expected 1 fill, received %d", fills)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	err := os.Chtimes(string(c.EntryPath("key")), old, old)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	_, err = c.GetOrFill("key", fill)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if fills != 2 {
+		t.Errorf("Expired entry was not refilled")
+	}
+}
+
+func TestCacheFillError(t *testing.T) {
+	c := Cache{Dir: Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))}
+	defer c.Dir.RmdirRecursive()
+
+	_, err := c.GetOrFill("key", func(w io.Writer) error {
+		return fmt.Errorf("fill failed")
+	})
+
+	if err == nil {
+		t.Errorf("GetOrFill should return the fill error")
+	}
+
+	count, err := c.Dir.CountEntries(false)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if count != 0 {
+		t.Errorf("Failed fill left %d entries behind", count)
+	}
+}
+
+func TestCacheEvict(t *testing.T) {
+	c := Cache{Dir: Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20))), MaxSize: 25}
+	defer c.Dir.RmdirRecursive()
+
+	fill := func(w io.Writer) error {
+		_, err := w.Write([]byte("0123456789"))
+		return err
+	}
+
+	for i, key := range []string{"a", "b"} {
+		_, err := c.GetOrFill(key, fill)
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+
+		used := time.Now().Add(time.Duration(i-10) * time.Minute)
+		os.Chtimes(string(c.EntryPath(key)), used, used)
+	}
+
+	// using "a" makes "b" the least recently used entry
+	_, err := c.GetOrFill("a", fill)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	_, err = c.GetOrFill("c", fill)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if c.EntryPath("b").Exists() {
+		t.Errorf("Least recently used entry was not evicted")
+	}
+
+	if !c.EntryPath("a").Exists() || !c.EntryPath("c").Exists() {
+		t.Errorf("Recently used entries should not be evicted")
+	}
+}
@@ -0,0 +1,127 @@
+package pathlib
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// CachedPath is a remote Path, such as a file on a network share, read through a local Cache.  Reads are served from the cache while the entry is younger than the TTL; after that the remote's ETag is checked, and the entry is only refetched if the remote has changed.  Writes go through to the remote and update the cache.  Create one with CachedRemote.
+type CachedPath struct {
+	Remote Path
+	Cache  Cache
+}
+
+// CachedRemote returns a CachedPath for remote, caching it in cacheDir for ttl.  As with Cache, a zero ttl means the cache is never revalidated.
+func CachedRemote(remote, cacheDir Path, ttl time.Duration) CachedPath {
+	return CachedPath{Remote: remote, Cache: Cache{Dir: cacheDir, TTL: ttl}}
+}
+
+// etagPath returns where the remote's ETag is stored alongside the cache entry.
+func (c CachedPath) etagPath() Path {
+	return Path(string(c.Cache.EntryPath(string(c.Remote))) + ".etag")
+}
+
+// Local returns the Path of the cache entry for the remote, after fetching or revalidating it as needed.
+func (c CachedPath) Local() (Path, error) {
+	key := string(c.Remote)
+	entry := c.Cache.EntryPath(key)
+
+	if entry.IsFile() {
+		age, err := entry.Age(time.Now())
+
+		if err == nil && (c.Cache.TTL == 0 || age < c.Cache.TTL) {
+			c.Cache.markUsed(entry)
+			return entry, nil
+		}
+	}
+
+	etag, err := c.Remote.ETag()
+
+	if err != nil {
+		return entry, err
+	}
+
+	// the remote is unchanged, so restart the entry's TTL rather than refetching
+	if cached, err := c.etagPath().ReadBytes(); err == nil && string(cached) == etag && entry.IsFile() {
+		now := time.Now()
+		return entry, os.Chtimes(string(entry), now, now)
+	}
+
+	if err = c.Cache.Remove(key); err != nil {
+		return entry, err
+	}
+
+	entry, err = c.Cache.GetOrFill(key, func(w io.Writer) error {
+		f, err := os.Open(string(c.Remote))
+
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		_, err = io.Copy(w, f)
+		return err
+	})
+
+	if err != nil {
+		return entry, err
+	}
+
+	return entry, c.etagPath().writeBytesAtomic([]byte(etag), 0644)
+}
+
+// ReadBytes reads all the bytes of the remote through the cache.
+func (c CachedPath) ReadBytes() ([]byte, error) {
+	entry, err := c.Local()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.ReadBytes()
+}
+
+// WriteBytes writes the bytes to the remote, atomically, and then stores them in the cache so the next read is served locally.
+func (c CachedPath) WriteBytes(data []byte) error {
+	if err := c.Remote.writeBytesAtomic(data, 0644); err != nil {
+		return err
+	}
+
+	etag, err := c.Remote.ETag()
+
+	if err != nil {
+		return err
+	}
+
+	key := string(c.Remote)
+
+	if err = c.Cache.Remove(key); err != nil {
+		return err
+	}
+
+	_, err = c.Cache.GetOrFill(key, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.etagPath().writeBytesAtomic([]byte(etag), 0644)
+}
+
+// Invalidate removes the remote's cache entry, so the next read fetches it again.
+func (c CachedPath) Invalidate() error {
+	if err := c.Cache.Remove(string(c.Remote)); err != nil {
+		return err
+	}
+
+	if err := os.Remove(string(c.etagPath())); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCachedRemote(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer root.RmdirRecursive()
+
+	if err := CreateTree(root, TreeSpec{{Path: "remote/data", Content: "v1"}}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	remote := root.JoinPath("remote", "data")
+	cached := CachedRemote(remote, root.JoinPath("cache"), time.Hour)
+
+	if data, err := cached.ReadBytes(); err != nil || string(data) != "v1" {
+		t.Fatalf("Expected v1, received %q: %v", data, err)
+	}
+
+	// within the TTL, reads are served from the cache even though the remote changed
+	remote.WriteBytes([]byte("v2"))
+
+	if data, _ := cached.ReadBytes(); string(data) != "v1" {
+		t.Errorf("Expected the cached v1, received %q", data)
+	}
+
+	// once expired, the changed ETag causes a refetch
+	entry := cached.Cache.EntryPath(string(remote))
+	expired := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(string(entry), expired, expired)
+
+	if data, _ := cached.ReadBytes(); string(data) != "v2" {
+		t.Errorf("Expected the refetched v2, received %q", data)
+	}
+
+	// an expired entry with an unchanged ETag is revalidated rather than refetched
+	os.Chtimes(string(entry), expired, expired)
+	entry.WriteBytes([]byte("local"))
+	os.Chtimes(string(entry), expired, expired)
+
+	if data, _ := cached.ReadBytes(); string(data) != "local" {
+		t.Errorf("Expected the revalidated entry, received %q", data)
+	}
+
+	if age, _ := entry.Age(time.Now()); age > time.Minute {
+		t.Errorf("Revalidation should restart the TTL, but the entry is %s old", age)
+	}
+
+	if err := cached.WriteBytes([]byte("v3")); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if data, _ := remote.ReadBytes(); string(data) != "v3" {
+		t.Errorf("Expected the write to reach the remote, received %q", data)
+	}
+
+	if data, _ := entry.ReadBytes(); string(data) != "v3" {
+		t.Errorf("Expected the write to update the cache, received %q", data)
+	}
+
+	if err := cached.Invalidate(); err != nil || entry.Exists() {
+		t.Errorf("Expected Invalidate to remove the entry: %v", err)
+	}
+}
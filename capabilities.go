@@ -0,0 +1,100 @@
+package pathlib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// FSCapabilities describes what the filesystem holding a Path supports, as found by Capabilities.
+type FSCapabilities struct {
+	Symlinks      bool
+	HardLinks     bool
+	Reflinks      bool // copy-on-write clones (FICLONE)
+	SparseFiles   bool // files with holes that take no space
+	Xattrs        bool // user extended attributes
+	CaseSensitive bool
+	AtomicRename  bool // renaming over an existing file replaces it in one step
+}
+
+// Capabilities probes the filesystem holding the directory Path (or the directory containing a file Path) by trying each feature in a scratch directory, which is removed afterwards.  The directory must be writable.
+func (p Path) Capabilities() (FSCapabilities, error) {
+	var caps FSCapabilities
+	dir := p
+
+	if !dir.IsDir() {
+		dir = p.Parent()
+	}
+
+	scratch, err := ioutil.TempDir(string(dir), ".pathlib-probe")
+
+	if err != nil {
+		return caps, err
+	}
+
+	defer os.RemoveAll(scratch)
+
+	probe := Path(scratch).JoinPath("probe")
+
+	if err = probe.WriteBytes([]byte("probe")); err != nil {
+		return caps, err
+	}
+
+	caps.Symlinks = os.Symlink("probe", string(Path(scratch).JoinPath("symlink"))) == nil
+	caps.HardLinks = os.Link(string(probe), string(Path(scratch).JoinPath("hardlink"))) == nil
+	caps.Reflinks = probeReflink(probe, Path(scratch).JoinPath("reflink"))
+	caps.Xattrs = probeXattrs(probe)
+	caps.SparseFiles = probeSparse(Path(scratch).JoinPath("sparse"))
+
+	_, err = os.Lstat(string(Path(scratch).JoinPath("PROBE")))
+	caps.CaseSensitive = os.IsNotExist(err)
+
+	replaced := Path(scratch).JoinPath("replaced")
+
+	if err = replaced.WriteBytes([]byte("replaced")); err != nil {
+		return caps, err
+	}
+
+	if err = probe.Rename(replaced); err == nil {
+		contents, err := replaced.ReadBytes()
+		caps.AtomicRename = err == nil && string(contents) == "probe"
+	}
+
+	return caps, nil
+}
+
+// probeSparse creates a file with a large hole and checks that the hole takes no space.
+func probeSparse(p Path) bool {
+	f, err := os.Create(string(p))
+
+	if err != nil {
+		return false
+	}
+
+	defer f.Close()
+
+	const size = 16 * 1024 * 1024
+
+	if _, err = f.WriteAt([]byte{1}, size-1); err != nil {
+		return false
+	}
+
+	if err = f.Sync(); err != nil {
+		return false
+	}
+
+	info, err := f.Stat()
+
+	if err != nil {
+		return false
+	}
+
+	allocated, ok := allocatedBytes(info)
+	return ok && allocated < size/2
+}
+
+// String summarizes the capabilities, for logging.
+func (c FSCapabilities) String() string {
+	return fmt.Sprintf("symlinks=%t hardlinks=%t reflinks=%t sparse=%t xattrs=%t case-sensitive=%t atomic-rename=%t",
+		c.Symlinks, c.HardLinks, c.Reflinks, c.SparseFiles, c.Xattrs, c.CaseSensitive, c.AtomicRename)
+}
@@ -0,0 +1,33 @@
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+const ficlone uintptr = iocWrite | 0x49409 // _IOW(0x94, 9, int)
+
+func probeReflink(src, dst Path) bool {
+	in, err := os.Open(string(src))
+
+	if err != nil {
+		return false
+	}
+
+	defer in.Close()
+
+	out, err := os.Create(string(dst))
+
+	if err != nil {
+		return false
+	}
+
+	defer out.Close()
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, out.Fd(), ficlone, in.Fd())
+	return errno == 0
+}
+
+func probeXattrs(p Path) bool {
+	return syscall.Setxattr(string(p), "user.pathlib-probe", []byte("1"), 0) == nil
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package pathlib
+
+import (
+	"os"
+)
+
+// allocatedBytes is not available here.  (On Windows, files are only sparse once marked with FSCTL_SET_SPARSE.)
+func allocatedBytes(info os.FileInfo) (int64, bool) {
+	return 0, false
+}
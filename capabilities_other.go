@@ -0,0 +1,13 @@
+//go:build !linux
+
+package pathlib
+
+// probeReflink reports false, since cloning files is only probed on Linux.
+func probeReflink(src, dst Path) bool {
+	return false
+}
+
+// probeXattrs reports false, since extended attributes are only probed on Linux.
+func probeXattrs(p Path) bool {
+	return false
+}
@@ -0,0 +1,37 @@
+package pathlib
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := dir.Mkdir(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	caps, err := dir.Capabilities()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if runtime.GOOS == "linux" && (!caps.Symlinks || !caps.HardLinks || !caps.CaseSensitive || !caps.AtomicRename) {
+		t.Errorf("Unexpected capabilities for a Linux filesystem: %s", caps)
+	}
+
+	entries, err := dir.CountEntries(false)
+
+	if err != nil || entries != 0 {
+		t.Errorf("Probe left %d entries behind (%v)", entries, err)
+	}
+
+	if _, err = dir.JoinPath("missing", "file").Capabilities(); err == nil {
+		t.Errorf("Expected an error probing a missing directory")
+	}
+}
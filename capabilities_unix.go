@@ -0,0 +1,19 @@
+//go:build unix
+
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+// allocatedBytes returns how much space the file takes on disk.
+func allocatedBytes(info os.FileInfo) (int64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+
+	if !ok {
+		return 0, false
+	}
+
+	return int64(stat.Blocks) * 512, true
+}
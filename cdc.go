@@ -0,0 +1,294 @@
+package pathlib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/bits"
+	"os"
+)
+
+// CDCOptions sets the chunk sizes used by content-defined chunking.  Zero values use the defaults of 2 KiB, 8 KiB, and 64 KiB.
+type CDCOptions struct {
+	MinSize int
+	AvgSize int
+	MaxSize int
+}
+
+// CDCChunk describes one content-defined chunk of a file.
+type CDCChunk struct {
+	Offset int64  `json:"offset"`
+	Length int64  `json:"length"`
+	SHA256 string `json:"sha256"`
+}
+
+// Delta describes how to rebuild a file from an older version of it: a sequence of ranges copied from the old file and literal data, along with the size and SHA-256 hash of the result so ApplyDelta can verify it.
+type Delta struct {
+	Ops    []DeltaOp `json:"ops"`
+	Size   int64     `json:"size"`
+	SHA256 string    `json:"sha256"`
+}
+
+// DeltaOp is a single step of a Delta.  If Data is nil it copies Length bytes from OldOffset in the old file, otherwise it writes Data.
+type DeltaOp struct {
+	OldOffset int64  `json:"old_offset,omitempty"`
+	Length    int64  `json:"length,omitempty"`
+	Data      []byte `json:"data,omitempty"`
+}
+
+// gearTable holds the random values used by the rolling gear hash.  It is generated from a fixed seed so that chunk boundaries are stable across runs and machines.
+var gearTable = func() (table [256]uint64) {
+	state := uint64(0x9e3779b97f4a7c15)
+
+	for i := range table {
+		// splitmix64
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+
+	return table
+}()
+
+func (o CDCOptions) withDefaults() (CDCOptions, error) {
+	if o.MinSize == 0 {
+		o.MinSize = 2 * 1024
+	}
+
+	if o.AvgSize == 0 {
+		o.AvgSize = 8 * 1024
+	}
+
+	if o.MaxSize == 0 {
+		o.MaxSize = 64 * 1024
+	}
+
+	if o.MinSize < 0 || o.AvgSize < 1 || o.MaxSize < o.MinSize {
+		return o, fmt.Errorf("Invalid chunk sizes %d/%d/%d", o.MinSize, o.AvgSize, o.MaxSize)
+	}
+
+	return o, nil
+}
+
+// cdcCut returns the length of the next chunk at the start of data, which holds at most MaxSize bytes.
+func cdcCut(data []byte, o CDCOptions) int {
+	if len(data) <= o.MinSize {
+		return len(data)
+	}
+
+	mask := uint64(1)<<(bits.Len(uint(o.AvgSize))-1) - 1
+	var h uint64
+
+	for i := o.MinSize; i < len(data); i++ {
+		h = h<<1 + gearTable[data[i]]
+
+		if h&mask == 0 {
+			return i + 1
+		}
+	}
+
+	return len(data)
+}
+
+// cdcSplit streams r through fn one content-defined chunk at a time.  The chunk slice is only valid during the call.
+func cdcSplit(r io.Reader, o CDCOptions, fn func(offset int64, chunk []byte) error) error {
+	buf := make([]byte, o.MaxSize)
+	filled := 0
+	offset := int64(0)
+	eof := false
+
+	for {
+		if !eof && filled < len(buf) {
+			n, err := io.ReadFull(r, buf[filled:])
+			filled += n
+
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				eof = true
+			} else if err != nil {
+				return err
+			}
+		}
+
+		if filled == 0 {
+			return nil
+		}
+
+		cut := cdcCut(buf[:filled], o)
+
+		if err := fn(offset, buf[:cut]); err != nil {
+			return err
+		}
+
+		offset += int64(cut)
+		filled = copy(buf, buf[cut:filled])
+	}
+}
+
+// ChunksCDC splits the file Path into content-defined chunks using a rolling gear hash, so that an insertion or deletion only changes the chunks around it.  The chunks of an old version of a file act as its signature for DeltaFromChunks.
+func (p Path) ChunksCDC(opts CDCOptions) ([]CDCChunk, error) {
+	o, err := opts.withDefaults()
+
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(string(p))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var chunks []CDCChunk
+
+	err = cdcSplit(f, o, func(offset int64, chunk []byte) error {
+		sum := sha256.Sum256(chunk)
+		chunks = append(chunks, CDCChunk{Offset: offset, Length: int64(len(chunk)), SHA256: hex.EncodeToString(sum[:])})
+		return nil
+	})
+
+	return chunks, err
+}
+
+// DeltaTo returns a Delta that rebuilds the file Path from the old file, using the default chunk sizes.
+func (p Path) DeltaTo(old Path) (*Delta, error) {
+	oldChunks, err := old.ChunksCDC(CDCOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return p.DeltaFromChunks(oldChunks, CDCOptions{})
+}
+
+// DeltaFromChunks returns a Delta that rebuilds the file Path from an old file described only by its chunks, which must have been computed with the same options.  This lets a remote side send the chunks of its copy and receive just the data it is missing, as with rsync.
+func (p Path) DeltaFromChunks(oldChunks []CDCChunk, opts CDCOptions) (*Delta, error) {
+	o, err := opts.withDefaults()
+
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(string(p))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	known := make(map[string]CDCChunk, len(oldChunks))
+
+	for _, chunk := range oldChunks {
+		known[chunk.SHA256] = chunk
+	}
+
+	delta := &Delta{}
+	total := sha256.New()
+
+	err = cdcSplit(f, o, func(offset int64, chunk []byte) error {
+		total.Write(chunk)
+		delta.Size += int64(len(chunk))
+		sum := sha256.Sum256(chunk)
+		var last *DeltaOp
+
+		if len(delta.Ops) > 0 {
+			last = &delta.Ops[len(delta.Ops)-1]
+		}
+
+		if match, ok := known[hex.EncodeToString(sum[:])]; ok && match.Length == int64(len(chunk)) {
+			if last != nil && last.Data == nil && last.OldOffset+last.Length == match.Offset {
+				last.Length += match.Length
+			} else {
+				delta.Ops = append(delta.Ops, DeltaOp{OldOffset: match.Offset, Length: match.Length})
+			}
+		} else if last != nil && last.Data != nil {
+			last.Data = append(last.Data, chunk...)
+		} else {
+			delta.Ops = append(delta.Ops, DeltaOp{Data: append([]byte(nil), chunk...)})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	delta.SHA256 = hex.EncodeToString(total.Sum(nil))
+	return delta, nil
+}
+
+// ApplyDelta rebuilds a file at out from the old file and the Delta, verifying its size and hash before atomically moving it into place with the permissions of the old file.  out may be the same Path as old.
+func ApplyDelta(old Path, delta *Delta, out Path) error {
+	in, err := os.Open(string(old))
+
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	tmp, err := ioutil.TempFile(string(out.Parent()), "."+out.Name()+".tmp")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	total := sha256.New()
+	w := io.MultiWriter(tmp, total)
+	var written int64
+
+	for _, op := range delta.Ops {
+		var n int64
+
+		if op.Data != nil {
+			var copied int
+			copied, err = w.Write(op.Data)
+			n = int64(copied)
+		} else {
+			n, err = io.Copy(w, io.NewSectionReader(in, op.OldOffset, op.Length))
+
+			if err == nil && n != op.Length {
+				err = fmt.Errorf("Delta reads past the end of %s", old)
+			}
+		}
+
+		written += n
+
+		if err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if stat, err := in.Stat(); err == nil {
+		if err = os.Chmod(tmp.Name(), stat.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	if written != delta.Size || hex.EncodeToString(total.Sum(nil)) != delta.SHA256 {
+		return fmt.Errorf("Delta applied to %s does not produce the expected file", old)
+	}
+
+	return os.Rename(tmp.Name(), string(out))
+}
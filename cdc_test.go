@@ -0,0 +1,142 @@
+package pathlib
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestChunksCDC(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := dir.Mkdir(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	data := make([]byte, 1<<20)
+	rand.New(rand.NewSource(1)).Read(data)
+	p := dir.JoinPath("data")
+
+	if err := p.WriteBytes(data); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	chunks, err := p.ChunksCDC(CDCOptions{})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var total int64
+
+	for _, chunk := range chunks {
+		if chunk.Offset != total || chunk.Length > 64*1024 {
+			t.Errorf("Unexpected chunk %+v", chunk)
+		}
+
+		total += chunk.Length
+	}
+
+	if total != int64(len(data)) || len(chunks) < 50 {
+		t.Errorf("Expected chunks covering %d bytes, received %d chunks covering %d", len(data), len(chunks), total)
+	}
+
+	// an insertion near the start should leave most later chunks intact
+	edited := dir.JoinPath("edited")
+
+	if err = edited.WriteBytes(append(append(append([]byte(nil), data[:1000]...), "inserted"...), data[1000:]...)); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	editedChunks, err := edited.ChunksCDC(CDCOptions{})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	shared := 0
+	seen := map[string]bool{}
+
+	for _, chunk := range chunks {
+		seen[chunk.SHA256] = true
+	}
+
+	for _, chunk := range editedChunks {
+		if seen[chunk.SHA256] {
+			shared++
+		}
+	}
+
+	if shared < len(chunks)-2 {
+		t.Errorf("Only %d of %d chunks survived an insertion", shared, len(chunks))
+	}
+
+	if _, err = p.ChunksCDC(CDCOptions{MinSize: 10, MaxSize: 5}); err == nil {
+		t.Errorf("Expected an error for invalid chunk sizes")
+	}
+}
+
+func TestDelta(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := dir.Mkdir(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	data := make([]byte, 512*1024)
+	rand.New(rand.NewSource(2)).Read(data)
+	old := dir.JoinPath("old")
+	updated := dir.JoinPath("new")
+	newData := append(append(append([]byte(nil), data[:100000]...), "some new bytes"...), data[200000:]...)
+
+	if err := old.WriteBytes(data); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if err := updated.WriteBytes(newData); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	delta, err := updated.DeltaTo(old)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	literal := 0
+
+	for _, op := range delta.Ops {
+		literal += len(op.Data)
+	}
+
+	if literal > 200*1024 {
+		t.Errorf("Delta carries %d literal bytes for a small edit", literal)
+	}
+
+	out := dir.JoinPath("out")
+
+	if err = ApplyDelta(old, delta, out); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	contents, err := out.ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if !bytes.Equal(contents, newData) {
+		t.Errorf("ApplyDelta did not reconstruct the new file")
+	}
+
+	delta.Ops[0].Data = []byte("corrupt")
+
+	if err = ApplyDelta(old, delta, dir.JoinPath("bad")); err == nil || dir.JoinPath("bad").Exists() {
+		t.Errorf("Expected a verification error for a corrupt delta")
+	}
+}
@@ -0,0 +1,106 @@
+package pathlib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// CheckpointID identifies a directory snapshot taken by Checkpoint.
+type CheckpointID string
+
+// checkpointDir returns the directory holding the checkpoints of the Path, which sits next to it so that restores stay on the same filesystem.
+func (p Path) checkpointDir() Path {
+	return p.Parent().JoinPath(Path("." + p.Name() + ".checkpoints"))
+}
+
+// Checkpoint snapshots the directory Path so it can later be restored with RestoreCheckpoint.  The snapshot is a full copy (preserving permissions, modification times, and symbolic links) rather than a hard-link farm, so it is unaffected by files later being modified in place.
+func (p Path) Checkpoint() (CheckpointID, error) {
+	if !p.IsDir() {
+		return "", fmt.Errorf("Checkpoint only works on directories: %s", p)
+	}
+
+	id := CheckpointID(time.Now().UTC().Format("20060102T150405.000000000Z"))
+	snapshot := p.checkpointDir().JoinPath(Path(id))
+	tmp := Path(string(snapshot) + ".tmp")
+
+	if err := os.MkdirAll(string(p.checkpointDir()), 0700); err != nil {
+		return "", err
+	}
+
+	if err := p.CopyTree(tmp, PreserveMode(), PreserveTimes()); err != nil {
+		os.RemoveAll(string(tmp))
+		return "", err
+	}
+
+	return id, tmp.Rename(snapshot)
+}
+
+// Checkpoints returns the IDs of the checkpoints of the Path, oldest first.
+func (p Path) Checkpoints() ([]CheckpointID, error) {
+	if !p.checkpointDir().IsDir() {
+		return nil, nil
+	}
+
+	infos, err := ioutil.ReadDir(string(p.checkpointDir()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]CheckpointID, 0, len(infos))
+
+	for _, info := range infos {
+		if info.IsDir() && filepath.Ext(info.Name()) != ".tmp" {
+			ids = append(ids, CheckpointID(info.Name()))
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// RestoreCheckpoint replaces the contents of the directory Path with the checkpoint.  The checkpoint is copied alongside the Path first and then swapped in with renames, so a failed restore leaves the Path untouched.  The checkpoint itself is kept and can be restored again.
+func (p Path) RestoreCheckpoint(id CheckpointID) error {
+	snapshot := p.checkpointDir().JoinPath(Path(id))
+
+	if len(id) == 0 || filepath.Base(string(id)) != string(id) || !snapshot.IsDir() {
+		return fmt.Errorf("No checkpoint %s for %s", id, p)
+	}
+
+	staged := p.checkpointDir().JoinPath(Path(string(id) + ".restore.tmp"))
+	old := p.checkpointDir().JoinPath(Path(string(id) + ".old.tmp"))
+
+	if err := snapshot.CopyTree(staged, PreserveMode(), PreserveTimes()); err != nil {
+		os.RemoveAll(string(staged))
+		return err
+	}
+
+	if p.Exists() {
+		if err := p.Rename(old); err != nil {
+			os.RemoveAll(string(staged))
+			return err
+		}
+	}
+
+	if err := staged.Rename(p); err != nil {
+		old.Rename(p)
+		return err
+	}
+
+	return os.RemoveAll(string(old))
+}
+
+// RemoveCheckpoint deletes the checkpoint.
+func (p Path) RemoveCheckpoint(id CheckpointID) error {
+	snapshot := p.checkpointDir().JoinPath(Path(id))
+
+	if len(id) == 0 || filepath.Base(string(id)) != string(id) || !snapshot.IsDir() {
+		return fmt.Errorf("No checkpoint %s for %s", id, p)
+	}
+
+	return snapshot.RmdirRecursive()
+}
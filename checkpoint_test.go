@@ -0,0 +1,89 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestCheckpointRestore(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	app := dir.JoinPath("app")
+
+	err := CreateTree(app, TreeSpec{
+		{Path: "bin/app", Content: "v1", Perms: 0755},
+		{Path: "config", Content: "setting=1"},
+		{Path: "current", Symlink: "bin/app"},
+	})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	id, err := app.Checkpoint()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	// a risky upgrade
+	app.JoinPath("bin/app").WriteBytes([]byte("v2"))
+	app.JoinPath("config").Unlink()
+	app.JoinPath("new-file").Touch()
+
+	err = app.RestoreCheckpoint(id)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	contents, err := app.JoinPath("bin/app").ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if string(contents) != "v1" || !app.JoinPath("config").IsFile() || app.JoinPath("new-file").Exists() {
+		t.Errorf("Checkpoint was not restored correctly")
+	}
+
+	perms, err := app.JoinPath("bin/app").Permissions()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if perms != 0755 {
+		t.Errorf("Permissions were not restored: %s", perms)
+	}
+
+	link, err := os.Readlink(string(app.JoinPath("current")))
+
+	if err != nil || link != "bin/app" {
+		t.Errorf("Symbolic link was not restored: %s", link)
+	}
+
+	ids, err := app.Checkpoints()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if len(ids) != 1 || ids[0] != id {
+		t.Errorf("Checkpoints returned %v", ids)
+	}
+
+	err = app.RemoveCheckpoint(id)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	err = app.RestoreCheckpoint(id)
+
+	if err == nil {
+		t.Errorf("RestoreCheckpoint should fail for removed checkpoints")
+	}
+}
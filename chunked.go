@@ -0,0 +1,254 @@
+package pathlib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// ChunkManifest describes the parts written by a ChunkedWriter.  It is stored next to the parts as "name.manifest.json".
+type ChunkManifest struct {
+	ChunkSize  int64       `json:"chunk_size"`
+	Size       int64       `json:"size"`
+	Parts      []ChunkPart `json:"parts"`
+	Generation int         `json:"generation,omitempty"` // counts rewrites, so new parts never overwrite the ones in use
+}
+
+// ChunkPart describes a single part file within a ChunkManifest.
+type ChunkPart struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ChunkManifestPath returns the Path of the manifest describing the chunked file Path.
+func (p Path) ChunkManifestPath() Path {
+	return Path(string(p) + ".manifest.json")
+}
+
+// ChunkedWriter writes a stream across part files ("name.part-00000", "name.part-00001", ...) of at most chunkSize bytes each, for destinations that limit file sizes.  The manifest is written when the writer is closed.  When rewriting an existing chunked file, the parts are numbered by generation ("name.1.part-00000", ...), so the previous parts stay readable until the manifest switches to the new ones.
+type ChunkedWriter struct {
+	p         Path
+	chunkSize int64
+	manifest  ChunkManifest
+	current   *os.File
+	written   int64
+	hash      hash.Hash
+}
+
+// ChunkedWriter returns a writer that splits its output across part files next to the Path.  The parts of any previous chunked file at the Path are removed once the writer is closed and the manifest has been replaced.
+func (p Path) ChunkedWriter(chunkSize int64) (*ChunkedWriter, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("Invalid chunk size %d", chunkSize)
+	}
+
+	manifest := ChunkManifest{ChunkSize: chunkSize}
+
+	if old, err := p.ReadChunkManifest(); err == nil {
+		manifest.Generation = old.Generation + 1
+	}
+
+	return &ChunkedWriter{p: p, chunkSize: chunkSize, manifest: manifest}, nil
+}
+
+// Write writes the data, starting new part files as each fills up.
+func (w *ChunkedWriter) Write(data []byte) (int, error) {
+	total := 0
+
+	for len(data) > 0 {
+		if w.current == nil || w.written == w.chunkSize {
+			if err := w.nextPart(); err != nil {
+				return total, err
+			}
+		}
+
+		n := int64(len(data))
+
+		if remaining := w.chunkSize - w.written; n > remaining {
+			n = remaining
+		}
+
+		written, err := w.current.Write(data[:n])
+		w.hash.Write(data[:written])
+		w.written += int64(written)
+		w.manifest.Size += int64(written)
+		total += written
+
+		if err != nil {
+			return total, err
+		}
+
+		data = data[n:]
+	}
+
+	return total, nil
+}
+
+func (w *ChunkedWriter) nextPart() error {
+	if err := w.finishPart(); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s.part-%05d", w.p.Name(), len(w.manifest.Parts))
+
+	if w.manifest.Generation > 0 {
+		name = fmt.Sprintf("%s.%d.part-%05d", w.p.Name(), w.manifest.Generation, len(w.manifest.Parts))
+	}
+
+	f, err := os.Create(string(w.p.Parent().JoinPath(Path(name))))
+
+	if err != nil {
+		return err
+	}
+
+	w.current = f
+	w.written = 0
+	w.hash = sha256.New()
+	w.manifest.Parts = append(w.manifest.Parts, ChunkPart{Name: name})
+	return nil
+}
+
+func (w *ChunkedWriter) finishPart() error {
+	if w.current == nil {
+		return nil
+	}
+
+	part := &w.manifest.Parts[len(w.manifest.Parts)-1]
+	part.Size = w.written
+	part.SHA256 = hex.EncodeToString(w.hash.Sum(nil))
+	err := w.current.Close()
+	w.current = nil
+	return err
+}
+
+// Close finishes the last part, atomically replaces the manifest, and then removes the parts of the previous chunked file that are no longer listed.
+func (w *ChunkedWriter) Close() error {
+	if err := w.finishPart(); err != nil {
+		return err
+	}
+
+	encoded, err := json.MarshalIndent(w.manifest, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	old, oldErr := w.p.ReadChunkManifest()
+
+	if err = w.p.ChunkManifestPath().writeBytesAtomic(encoded, 0644); err != nil {
+		return err
+	}
+
+	if oldErr != nil {
+		return nil
+	}
+
+	current := make(map[string]bool, len(w.manifest.Parts))
+
+	for _, part := range w.manifest.Parts {
+		current[part.Name] = true
+	}
+
+	for _, part := range old.Parts {
+		if !current[part.Name] {
+			os.Remove(string(w.p.Parent().JoinPath(Path(part.Name))))
+		}
+	}
+
+	return nil
+}
+
+// ReadChunkManifest reads the manifest of the chunked file Path.  An error is returned if a part is not named as a plain file next to the Path, so a crafted manifest cannot reach files elsewhere.
+func (p Path) ReadChunkManifest() (ChunkManifest, error) {
+	var manifest ChunkManifest
+	contents, err := p.ChunkManifestPath().ReadBytes()
+
+	if err != nil {
+		return manifest, err
+	}
+
+	if err = json.Unmarshal(contents, &manifest); err != nil {
+		return manifest, err
+	}
+
+	for _, part := range manifest.Parts {
+		if part.Name == "" || part.Name == "." || part.Name == ".." || strings.ContainsAny(part.Name, `/\:`) {
+			return manifest, fmt.Errorf("Invalid part name %q in %s", part.Name, p.ChunkManifestPath())
+		}
+	}
+
+	return manifest, nil
+}
+
+// ChunkedReader returns a reader presenting the parts of the chunked file Path as a single stream.  The size and checksum of each part are verified as it is read.
+func (p Path) ChunkedReader() (io.ReadCloser, error) {
+	manifest, err := p.ReadChunkManifest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &chunkedReader{dir: p.Parent(), parts: manifest.Parts}, nil
+}
+
+type chunkedReader struct {
+	dir     Path
+	parts   []ChunkPart
+	current *os.File
+	read    int64
+	hash    hash.Hash
+}
+
+func (r *chunkedReader) Read(data []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.parts) == 0 {
+				return 0, io.EOF
+			}
+
+			f, err := os.Open(string(r.dir.JoinPath(Path(r.parts[0].Name))))
+
+			if err != nil {
+				return 0, err
+			}
+
+			r.current = f
+			r.read = 0
+			r.hash = sha256.New()
+		}
+
+		n, err := r.current.Read(data)
+		r.hash.Write(data[:n])
+		r.read += int64(n)
+
+		if err == io.EOF {
+			part := r.parts[0]
+			r.current.Close()
+			r.current = nil
+			r.parts = r.parts[1:]
+
+			if r.read != part.Size || hex.EncodeToString(r.hash.Sum(nil)) != part.SHA256 {
+				return n, fmt.Errorf("Chunk %s does not match its manifest", part.Name)
+			}
+
+			err = nil
+		}
+
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+func (r *chunkedReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+
+	return r.current.Close()
+}
@@ -0,0 +1,136 @@
+package pathlib
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+func TestChunkedRoundTrip(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := dir.Mkdir()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	p := dir.JoinPath("export.bin")
+	w, err := p.ChunkedWriter(10)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	w.Write(data[:7])
+	w.Write(data[7:])
+
+	err = w.Close()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	manifest, err := p.ReadChunkManifest()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if len(manifest.Parts) != 4 || manifest.Size != int64(len(data)) || manifest.Parts[3].Size != 6 {
+		t.Errorf("Incorrect manifest: %+v", manifest)
+	}
+
+	if !dir.JoinPath("export.bin.part-00003").IsFile() {
+		t.Errorf("Part files were not named as expected")
+	}
+
+	r, err := p.ChunkedReader()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	read, err := ioutil.ReadAll(r)
+	r.Close()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if !bytes.Equal(read, data) {
+		t.Errorf("Read %q, expected %q", read, data)
+	}
+
+	// corrupt a part
+	dir.JoinPath("export.bin.part-00001").WriteBytes([]byte("XXXXXXXXXX"))
+	r, err = p.ChunkedReader()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	_, err = ioutil.ReadAll(r)
+	r.Close()
+
+	if err == nil {
+		t.Errorf("Reading a corrupt part should fail")
+	}
+
+	// rewriting keeps the old parts until the new manifest is in place, then removes them
+	w, err = p.ChunkedWriter(100)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	w.Write(data)
+
+	if manifest, err = p.ReadChunkManifest(); err != nil || len(manifest.Parts) != 4 || !dir.JoinPath("export.bin.part-00001").Exists() {
+		t.Errorf("Old parts were removed before the writer was closed")
+	}
+
+	w.Close()
+
+	if dir.JoinPath("export.bin.part-00001").Exists() {
+		t.Errorf("Old parts were not removed")
+	}
+
+	r, err = p.ChunkedReader()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	read, err = ioutil.ReadAll(r)
+	r.Close()
+
+	if err != nil || !bytes.Equal(read, data) {
+		t.Errorf("Read %q and %v after rewriting, expected %q", read, err, data)
+	}
+}
+
+func TestChunkedManifestPartNames(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := dir.Mkdir()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	p := dir.JoinPath("export.bin")
+
+	for _, name := range []string{"../secret", "/etc/passwd", "sub/part", "..", ""} {
+		manifest := fmt.Sprintf(`{"chunk_size": 10, "size": 0, "parts": [{"name": %q}]}`, name)
+		p.ChunkManifestPath().WriteBytes([]byte(manifest))
+
+		if _, err = p.ChunkedReader(); err == nil {
+			t.Errorf("Expected an error for the part name %q", name)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package pathlib
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CompleteOptions controls Complete.
+type CompleteOptions struct {
+	// ShowHidden includes names starting with a dot even when the partial name does not start with one.
+	ShowHidden bool
+	// DirsOnly only offers directories (including symbolic links to them).
+	DirsOnly bool
+	// MarkDirs appends a separator to directories, so that completing one moves straight into it, as shells do.
+	MarkDirs bool
+}
+
+// Complete returns the Paths that could complete the partial input prefix, for command-line tools offering completion of path arguments.  The prefix is split into a directory, which is kept exactly as typed, and a partial name, which the names in that directory must start with.  Hidden names are only offered when the partial name starts with a dot, unless ShowHidden is set.  A missing directory yields no candidates.
+func Complete(prefix string, opts CompleteOptions) ([]Path, error) {
+	dir, partial := filepath.Split(prefix)
+	readDir := dir
+
+	if len(readDir) == 0 {
+		readDir = "."
+	}
+
+	entries, err := os.ReadDir(readDir)
+
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []Path
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if !strings.HasPrefix(name, partial) {
+			continue
+		}
+
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(partial, ".") && !opts.ShowHidden {
+			continue
+		}
+
+		isDir := entry.IsDir()
+
+		if entry.Type()&os.ModeSymlink != 0 {
+			isDir = Path(filepath.Join(readDir, name)).IsDir()
+		}
+
+		if opts.DirsOnly && !isDir {
+			continue
+		}
+
+		candidate := dir + name
+
+		if isDir && opts.MarkDirs {
+			candidate += string(filepath.Separator)
+		}
+
+		candidates = append(candidates, Path(candidate))
+	}
+
+	return candidates, nil
+}
@@ -0,0 +1,48 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	err := CreateTree(root, TreeSpec{
+		{Path: "config.json"}, {Path: "configs/a"}, {Path: ".config/b"}, {Path: "docs/c"}, {Path: "conflink", Symlink: "configs"},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer root.RmdirRecursive()
+
+	base := string(root) + "/"
+
+	tests := map[string]struct {
+		prefix   string
+		opts     CompleteOptions
+		expected string
+	}{
+		"partial":     {base + "con", CompleteOptions{}, fmt.Sprint([]Path{Path(base + "config.json"), Path(base + "configs"), Path(base + "conflink")})},
+		"mark dirs":   {base + "conf", CompleteOptions{MarkDirs: true}, fmt.Sprint([]Path{Path(base + "config.json"), Path(base + "configs/"), Path(base + "conflink/")})},
+		"dirs only":   {base + "c", CompleteOptions{DirsOnly: true}, fmt.Sprint([]Path{Path(base + "configs"), Path(base + "conflink")})},
+		"hidden":      {base + ".", CompleteOptions{}, fmt.Sprint([]Path{Path(base + ".config")})},
+		"show hidden": {base, CompleteOptions{ShowHidden: true, DirsOnly: true}, fmt.Sprint([]Path{Path(base + ".config"), Path(base + "configs"), Path(base + "conflink"), Path(base + "docs")})},
+		"into dir":    {base + "configs/", CompleteOptions{}, fmt.Sprint([]Path{Path(base + "configs/a")})},
+		"missing":     {base + "missing/x", CompleteOptions{}, fmt.Sprint([]Path(nil))},
+	}
+
+	for name, test := range tests {
+		candidates, err := Complete(test.prefix, test.opts)
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+
+		if fmt.Sprint(candidates) != test.expected {
+			t.Errorf("%s: expected %s, received %v", name, test.expected, candidates)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package pathlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConfigDecoder decodes configuration data into v.
+type ConfigDecoder func(data []byte, v any) error
+
+var (
+	configFormatsMu sync.RWMutex
+	configFormats   = map[string]ConfigDecoder{
+		"json": json.Unmarshal,
+	}
+)
+
+// RegisterConfigFormat registers the decoder used by LoadConfig for files with the extension ext (without the dot, eg. "yaml").  JSON is registered by default; YAML and TOML can be supported by registering the decoder from the library of your choice (eg. yaml.Unmarshal), which keeps pathlib free of third-party dependencies.
+func RegisterConfigFormat(ext string, decode ConfigDecoder) {
+	configFormatsMu.Lock()
+	defer configFormatsMu.Unlock()
+	configFormats[strings.ToLower(strings.TrimPrefix(ext, "."))] = decode
+}
+
+// ConfigOptions controls the behavior of LoadConfigWithOptions.
+type ConfigOptions struct {
+	// Defaults are config files decoded in order before the Path itself, so values in later files override earlier ones.  Defaults that do not exist are skipped.
+	Defaults []Path
+
+	// EnvPrefix is prepended to the names in `env:"NAME"` struct tags when looking up environment overrides.
+	EnvPrefix string
+}
+
+// LoadConfig decodes the config file Path into v, choosing the format by extension (or by sniffing the contents if the extension is unknown), then applies environment overrides for struct fields tagged with `env:"NAME"`.
+func (p Path) LoadConfig(v any) error {
+	return p.LoadConfigWithOptions(v, ConfigOptions{})
+}
+
+// LoadConfigWithOptions is like LoadConfig, but first merges the default config files and uses the environment prefix in opts.
+func (p Path) LoadConfigWithOptions(v any, opts ConfigOptions) error {
+	for _, defaults := range opts.Defaults {
+		if !defaults.Exists() {
+			continue
+		}
+
+		if err := defaults.decodeConfig(v); err != nil {
+			return err
+		}
+	}
+
+	if err := p.decodeConfig(v); err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil
+	}
+
+	return applyEnvOverrides(rv.Elem(), opts.EnvPrefix)
+}
+
+func (p Path) decodeConfig(v any) error {
+	data, err := p.ReadBytes()
+
+	if err != nil {
+		return err
+	}
+
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(string(p)), "."))
+
+	configFormatsMu.RLock()
+	decode, ok := configFormats[format]
+
+	if !ok {
+		decode, ok = configFormats[sniffConfigFormat(data)]
+	}
+
+	configFormatsMu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("Unable to determine config format of %s", p)
+	}
+
+	if err := decode(data, v); err != nil {
+		return fmt.Errorf("Unable to decode config %s: %w", p, err)
+	}
+
+	return nil
+}
+
+// sniffConfigFormat guesses the format of configuration data from its contents.
+func sniffConfigFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(trimmed, []byte("{")) || bytes.HasPrefix(trimmed, []byte("[{")) {
+		return "json"
+	}
+
+	for _, line := range strings.Split(string(trimmed), "\n") {
+		line = strings.TrimSpace(line)
+
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") || strings.Contains(line, " = ") {
+			return "toml"
+		}
+
+		if line == "---" || strings.Contains(line, ": ") || strings.HasSuffix(line, ":") {
+			return "yaml"
+		}
+	}
+
+	return ""
+}
+
+// applyEnvOverrides sets the struct fields of v tagged with `env:"NAME"` from the environment, recursing into nested structs.
+func applyEnvOverrides(v reflect.Value, prefix string) error {
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+
+		if !field.CanSet() {
+			continue
+		}
+
+		name, tagged := t.Field(i).Tag.Lookup("env")
+
+		if !tagged {
+			if err := applyEnvOverrides(field, prefix); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		value, ok := os.LookupEnv(prefix + name)
+
+		if !ok {
+			continue
+		}
+
+		if err := setFromString(field, value); err != nil {
+			return fmt.Errorf("Invalid value for %s%s: %w", prefix, name, err)
+		}
+	}
+
+	return nil
+}
+
+func setFromString(field reflect.Value, value string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(value)
+
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	Name    string        `json:"name"`
+	Port    int           `json:"port" env:"PORT"`
+	Debug   bool          `json:"debug" env:"DEBUG"`
+	Timeout time.Duration `json:"timeout" env:"TIMEOUT"`
+	Nested  struct {
+		Value string `json:"value" env:"NESTED_VALUE"`
+	} `json:"nested"`
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := dir.Mkdir()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	defaults := dir.JoinPath("defaults.json")
+	err = defaults.WriteBytes([]byte(`{"name": "default", "port": 80, "nested": {"value": "a"}}`))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	config := dir.JoinPath("app.conf") // unknown extension, should be sniffed as JSON
+	err = config.WriteBytes([]byte(`{"port": 8080}`))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	os.Setenv("PATHLIBTEST_DEBUG", "true")
+	os.Setenv("PATHLIBTEST_NESTED_VALUE", "b")
+	defer os.Unsetenv("PATHLIBTEST_DEBUG")
+	defer os.Unsetenv("PATHLIBTEST_NESTED_VALUE")
+
+	var cfg testConfig
+	opts := ConfigOptions{
+		Defaults:  []Path{defaults, dir.JoinPath("missing.json")},
+		EnvPrefix: "PATHLIBTEST_",
+	}
+
+	err = config.LoadConfigWithOptions(&cfg, opts)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if cfg.Name != "default" || cfg.Port != 8080 || !cfg.Debug || cfg.Nested.Value != "b" {
+		t.Errorf("Config loaded incorrectly: %+v", cfg)
+	}
+}
+
+func TestLoadConfigRegisteredFormat(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s.kv", randomString(20)))
+	err := p.WriteBytes([]byte("anything"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	var cfg testConfig
+	err = p.LoadConfig(&cfg)
+
+	if err == nil {
+		t.Errorf("LoadConfig should fail for unknown formats")
+	}
+
+	RegisterConfigFormat(".kv", func(data []byte, v any) error {
+		v.(*testConfig).Name = string(data)
+		return nil
+	})
+
+	// unregistered again, so the format is unknown at the start of every run
+	t.Cleanup(func() {
+		configFormatsMu.Lock()
+		defer configFormatsMu.Unlock()
+		delete(configFormats, "kv")
+	})
+
+	err = p.LoadConfig(&cfg)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if cfg.Name != "anything" {
+		t.Errorf("Registered format was not used: %+v", cfg)
+	}
+}
+
+func TestSniffConfigFormat(t *testing.T) {
+	tests := map[string]string{
+		`{"a": 1}`:              "json",
+		"[server]\nport = 80\n": "toml",
+		"# comment\nport: 80\n": "yaml",
+		"---\nname: foo\n":      "yaml",
+		"just some text":        "",
+	}
+
+	for data, target := range tests {
+		if format := sniffConfigFormat([]byte(data)); format != target {
+			t.Errorf("sniffConfigFormat(%q) = %q, expected %q", data, format, target)
+		}
+	}
+}
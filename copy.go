@@ -0,0 +1,590 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// CopyOption configures Copy, CopyTree, and Move.
+type CopyOption func(*copyOptions)
+
+type copyOptions struct {
+	preserveMode    bool
+	preserveTimes   bool
+	preserveOwner   bool
+	preserveXattrs  bool
+	overwrite       bool
+	dereference     bool
+	exclude         func(Path) bool
+	inspect         func(Path, io.Reader) error
+	report          *CopyReport
+	stats           *CopyStats
+	archive         bool
+	verifyManifests bool
+	privileged      PrivilegedOps
+	umask           func() os.FileMode // reads the umask at most once per copy
+	errs            MultiError         // if set, the errors copying the entries of directories are collected here instead of ending the copy
+}
+
+// ErrRejected is returned by Inspect hooks (possibly wrapped) to veto copying a file.
+var ErrRejected = errors.New("rejected by inspection")
+
+// CopyReport lists the attributes that could not be applied to copies, when requested with ReportUnapplied.  For FSPath copies, it also lists the features the destination Filesystem lacks.
+type CopyReport struct {
+	Unapplied []UnappliedAttribute
+	Rejected  []RejectedFile
+}
+
+// CopyStats counts what a copy did, when requested with ReportStats.
+type CopyStats struct {
+	// Files, Dirs, and Symlinks count what was copied.  Files includes device nodes copied in Archive mode.
+	Files    int
+	Dirs     int
+	Symlinks int
+
+	// Bytes is the total size of the files copied.
+	Bytes int64
+
+	// Unchanged counts the files and symbolic links that SyncTo left alone because the copy was already up to date.
+	Unchanged int
+
+	// Excluded counts the entries skipped by Exclude, not including those within excluded directories.
+	Excluded int
+
+	// Duration is how long the copy took.
+	Duration time.Duration
+}
+
+// RejectedFile describes a file that an Inspect hook vetoed.
+type RejectedFile struct {
+	Path Path // the original
+	Err  error
+}
+
+// UnappliedAttribute describes an attribute of a copy that could not be set.
+type UnappliedAttribute struct {
+	Path      Path   // the copy
+	Attribute string // "owner", "immutable", "xattr NAME", or "xattrs" if none could be read, and for FSPath copies "mode", "times", or "symlink"
+	Err       error
+}
+
+// PreserveMode makes copies keep the exact permissions of the originals.  Without it, copies are created with the originals' permissions filtered by the umask, as with cp.
+func PreserveMode() CopyOption {
+	return func(o *copyOptions) {
+		o.preserveMode = true
+	}
+}
+
+// PreserveTimes makes copies keep the access and modification times of the originals.
+func PreserveTimes() CopyOption {
+	return func(o *copyOptions) {
+		o.preserveTimes = true
+	}
+}
+
+// PreserveOwnership makes copies keep the user and group of the originals, which usually requires root.  Ownership is not supported on Windows.
+func PreserveOwnership() CopyOption {
+	return func(o *copyOptions) {
+		o.preserveOwner = true
+	}
+}
+
+// PreserveXattrs makes copies keep the extended attributes of the originals.  Extended attributes are only copied on Linux, and setting those outside the user namespace usually requires root.
+func PreserveXattrs() CopyOption {
+	return func(o *copyOptions) {
+		o.preserveXattrs = true
+	}
+}
+
+// Archive makes copies as faithful as cp -a: it implies PreserveMode, PreserveTimes, PreserveOwnership, and PreserveXattrs, and also recreates device nodes and copies the immutable flag.  Device nodes and the immutable flag usually require root, and like ownership, failing to apply the immutable flag is recorded in the report given to ReportUnapplied if there is one.
+func Archive() CopyOption {
+	return func(o *copyOptions) {
+		o.preserveMode, o.preserveTimes, o.preserveOwner, o.preserveXattrs = true, true, true, true
+		o.archive = true
+	}
+}
+
+// WithPrivilegedOps makes copies change ownership, create device nodes, and set the immutable flag through ops instead of HostPrivilegedOps, such as a FakePrivilegedOps in tests.
+func WithPrivilegedOps(ops PrivilegedOps) CopyOption {
+	return func(o *copyOptions) {
+		o.privileged = ops
+	}
+}
+
+// ReportUnapplied makes ownership and extended attributes that cannot be applied (typically because the copy runs unprivileged) get recorded in the report instead of failing the copy.  Files vetoed by Inspect are recorded in it too.
+func ReportUnapplied(report *CopyReport) CopyOption {
+	return func(o *copyOptions) {
+		o.report = report
+	}
+}
+
+// ReportStats makes copies count the files, directories, symbolic links, and bytes they copy, and how long they take, in stats.  Unlike ReportUnapplied, it does not change how failures are handled.  Counts accumulate if stats is reused.
+func ReportStats(stats *CopyStats) CopyOption {
+	return func(o *copyOptions) {
+		o.stats = stats
+	}
+}
+
+// Overwrite lets copies replace existing files, and CopyTree merge into an existing directory.  Without it, copying onto an existing Path fails with an error wrapping os.ErrExist.
+func Overwrite() CopyOption {
+	return func(o *copyOptions) {
+		o.overwrite = true
+	}
+}
+
+// DereferenceSymlinks copies what symbolic links point to instead of recreating the links.  Links that lead back to a directory being copied are recreated as links rather than followed.
+func DereferenceSymlinks() CopyOption {
+	return func(o *copyOptions) {
+		o.dereference = true
+	}
+}
+
+// Exclude skips any file, symbolic link, or directory (and everything within it) for which the function returns true.  It is called with the Path being copied from.
+func Exclude(fn func(Path) bool) CopyOption {
+	return func(o *copyOptions) {
+		o.exclude = fn
+	}
+}
+
+// Inspect calls fn with the contents of each regular file as it is copied, so content can be scanned (eg. for viruses) without reading the files again afterwards.  If fn returns an error wrapping ErrRejected, the file is not copied (leaving any existing file at the destination as it was) and the rest of the copy continues, with the file recorded in the report given to ReportUnapplied; any other error fails the copy.  fn need not read everything.
+func Inspect(fn func(src Path, r io.Reader) error) CopyOption {
+	return func(o *copyOptions) {
+		o.inspect = fn
+	}
+}
+
+func newCopyOptions(opts []CopyOption) copyOptions {
+	o := copyOptions{privileged: HostPrivilegedOps, umask: sync.OnceValue(Umask)}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Copy copies the file (or symbolic link) Path to dst.
+func (p Path) Copy(dst Path, opts ...CopyOption) error {
+	o := newCopyOptions(opts)
+	defer o.timed(time.Now())
+	info, err := o.stat(p)
+
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		return fmt.Errorf("Cannot copy %s because it is a directory; use CopyTree", p)
+	}
+
+	return o.copy(p, dst, info, nil)
+}
+
+// CopyTree copies the directory Path and everything within it to dst.  Symbolic links are recreated rather than followed unless DereferenceSymlinks is given.  Failing to copy an entry does not stop the rest of the tree being copied; the failures are returned together as a MultiError keyed by the Paths being copied from.
+func (p Path) CopyTree(dst Path, opts ...CopyOption) error {
+	o := newCopyOptions(opts)
+	defer o.timed(time.Now())
+	info, err := o.stat(p)
+
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("CopyTree only works on directories: %s", p)
+	}
+
+	absSrc, err := filepath.Abs(string(p))
+
+	if err != nil {
+		return err
+	}
+
+	absDst, err := filepath.Abs(string(dst))
+
+	if err != nil {
+		return err
+	}
+
+	if Path(absDst).within(Path(absSrc)) {
+		return fmt.Errorf("Cannot copy %s to %s because it is within the directory being copied", p, dst)
+	}
+
+	o.errs = MultiError{}
+
+	if err = o.copy(p, dst, info, nil); err != nil {
+		return err
+	}
+
+	if o.verifyManifests {
+		if err = verifyDirManifests(OS, filepath.ToSlash(string(dst)), o.errs); err != nil {
+			return err
+		}
+	}
+
+	return o.errs.ErrorOrNil()
+}
+
+// Move moves the Path to dst like Rename, but when they are on different filesystems (where Rename fails) it falls back to copying the file or tree, preserving permissions and times, and then deleting the original.  Like Rename, it replaces an existing file at dst.
+func (p Path) Move(dst Path) error {
+	err := p.Rename(dst)
+
+	if err == nil || !isCrossDevice(err) {
+		return err
+	}
+
+	return p.moveByCopy(dst)
+}
+
+func (p Path) moveByCopy(dst Path) error {
+	info, err := os.Lstat(string(p))
+
+	if err != nil {
+		return err
+	}
+
+	opts := []CopyOption{PreserveMode(), PreserveTimes()}
+
+	if info.IsDir() {
+		if dst.lexists() {
+			return fmt.Errorf("Cannot move %s to %s: %w", p, dst, os.ErrExist)
+		}
+
+		if err = p.CopyTree(dst, opts...); err != nil {
+			os.RemoveAll(string(dst))
+			return err
+		}
+
+		return p.RmdirRecursive()
+	}
+
+	if err = p.Copy(dst, append(opts, Overwrite())...); err != nil {
+		return err
+	}
+
+	return p.Unlink()
+}
+
+func (o copyOptions) stat(p Path) (os.FileInfo, error) {
+	if o.dereference {
+		return os.Stat(string(p))
+	}
+
+	return os.Lstat(string(p))
+}
+
+// copy copies src to dst according to its type.  ancestors holds the directories being copied, to detect symlink loops when dereferencing.
+func (o copyOptions) copy(src, dst Path, info os.FileInfo, ancestors []os.FileInfo) error {
+	if info.Mode()&os.ModeSymlink == 0 {
+		// dst may also be a link to src, which opening it for writing would truncate
+		if target, err := os.Stat(string(dst)); err == nil && os.SameFile(info, target) {
+			return fmt.Errorf("Cannot copy %s to %s because they are the same file", src, dst)
+		}
+
+		if existing, err := os.Lstat(string(dst)); err == nil {
+			if !o.overwrite {
+				return fmt.Errorf("Cannot copy %s to %s: %w", src, dst, os.ErrExist)
+			}
+
+			if existing.IsDir() != info.IsDir() {
+				return fmt.Errorf("Cannot copy %s over %s because only one is a directory", src, dst)
+			}
+		}
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		if err := o.copySymlink(src, dst); err != nil {
+			return err
+		}
+
+		o.count(func(s *CopyStats) { s.Symlinks++ })
+		return o.applyOwnership(src, dst, info)
+	case info.IsDir():
+		return o.copyDir(src, dst, info, append(ancestors, info))
+	case info.Mode().IsRegular():
+		if o.inspect != nil {
+			rejected, err := o.copyInspected(src, dst, info.Mode().Perm())
+
+			if rejected || err != nil {
+				return err
+			}
+		} else if err := copyFile(src, dst, info.Mode().Perm()); err != nil {
+			return err
+		}
+
+		o.count(func(s *CopyStats) { s.Files++; s.Bytes += info.Size() })
+		return o.applyMetadata(src, dst, info)
+	case info.Mode()&os.ModeDevice != 0 && o.archive:
+		return o.copyDevice(src, dst, info)
+	}
+
+	return fmt.Errorf("Cannot copy %s because it is not a regular file, directory, or symbolic link", src)
+}
+
+// copyInspected copies a file while streaming its contents to the Inspect hook.  The copy is written to a temporary file that only replaces dst once the hook accepts it, so a vetoed file leaves an existing dst untouched.
+func (o copyOptions) copyInspected(src, dst Path, perms os.FileMode) (bool, error) {
+	in, err := os.Open(string(src))
+
+	if err != nil {
+		return false, err
+	}
+
+	defer in.Close()
+
+	// as if dst were opened for writing: an existing file keeps its permissions, and a new one is subject to umask
+	if stat, err := os.Stat(string(dst)); err == nil {
+		perms = stat.Mode().Perm()
+	} else {
+		perms &^= o.umask()
+	}
+
+	out, err := os.CreateTemp(string(dst.Parent()), "."+dst.Name()+".tmp")
+
+	if err != nil {
+		return false, err
+	}
+
+	defer os.Remove(out.Name()) // no-op once renamed
+
+	reader, writer := io.Pipe()
+	verdict := make(chan error, 1)
+
+	go func() {
+		err := o.inspect(src, reader)
+		io.Copy(io.Discard, reader) // let the copy finish if the hook stopped reading early
+		verdict <- err
+	}()
+
+	_, err = io.Copy(io.MultiWriter(out, writer), in)
+	writer.CloseWithError(err)
+	inspectErr := <-verdict
+
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if inspectErr != nil {
+		return true, o.rejected(src, inspectErr)
+	}
+
+	if err = os.Chmod(out.Name(), perms); err != nil {
+		return false, err
+	}
+
+	return false, os.Rename(out.Name(), string(dst))
+}
+
+// rejected records a file vetoed by the Inspect hook, or returns the hook's error if it was not a veto.
+func (o copyOptions) rejected(src Path, err error) error {
+	if !errors.Is(err, ErrRejected) {
+		return fmt.Errorf("Cannot copy %s: %w", src, err)
+	}
+
+	if o.report != nil {
+		o.report.Rejected = append(o.report.Rejected, RejectedFile{Path: src, Err: err})
+	}
+
+	return nil
+}
+
+func (o copyOptions) copySymlink(src, dst Path) error {
+	link, err := os.Readlink(string(src))
+
+	if err != nil {
+		return err
+	}
+
+	if existing, err := os.Lstat(string(dst)); err == nil {
+		if !o.overwrite {
+			return fmt.Errorf("Cannot copy %s to %s: %w", src, dst, os.ErrExist)
+		}
+
+		if existing.IsDir() {
+			return fmt.Errorf("Cannot copy %s over %s because only one is a directory", src, dst)
+		}
+
+		if err = os.Remove(string(dst)); err != nil {
+			return err
+		}
+	}
+
+	return os.Symlink(link, string(dst))
+}
+
+// copyDevice recreates the device node src at dst.
+func (o copyOptions) copyDevice(src, dst Path, info os.FileInfo) error {
+	major, minor, ok := deviceNumbers(info)
+
+	if !ok {
+		return fmt.Errorf("Cannot copy device %s because device nodes are not supported on this platform", src)
+	}
+
+	if o.overwrite {
+		if err := os.Remove(string(dst)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := o.privileged.Mknod(dst, info.Mode()&(os.ModeDevice|os.ModeCharDevice|os.ModePerm), major, minor); err != nil {
+		return err
+	}
+
+	o.count(func(s *CopyStats) { s.Files++ })
+
+	return o.applyMetadata(src, dst, info)
+}
+
+// copyEntry copies the directory entry src to dst.
+func (o copyOptions) copyEntry(src, dst Path, ancestors []os.FileInfo) error {
+	info, err := os.Lstat(string(src))
+
+	if err != nil {
+		return err
+	}
+
+	if o.dereference && info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Stat(string(src))
+
+		if err == nil && !(target.IsDir() && isAncestor(ancestors, target)) {
+			info = target
+		}
+	}
+
+	return o.copy(src, dst, info, ancestors)
+}
+
+func (o copyOptions) copyDir(src, dst Path, info os.FileInfo, ancestors []os.FileInfo) error {
+	// the owner needs full access to fill the copy; its real permissions are applied afterwards
+	if err := os.Mkdir(string(dst), info.Mode().Perm()|0700); err != nil && !(o.overwrite && os.IsExist(err)) {
+		return err
+	}
+
+	o.count(func(s *CopyStats) { s.Dirs++ })
+
+	entries, err := os.ReadDir(string(src))
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		child := src.JoinPath(Path(entry.Name()))
+
+		if o.exclude != nil && o.exclude(child) {
+			o.count(func(s *CopyStats) { s.Excluded++ })
+			continue
+		}
+
+		if err = o.copyEntry(child, dst.JoinPath(Path(entry.Name())), ancestors); err != nil {
+			if o.errs == nil {
+				return err
+			}
+
+			o.errs.add(child, err)
+		}
+	}
+
+	// as with files, the copy gets the original's permissions filtered by the umask
+	if perms := info.Mode().Perm() &^ o.umask(); !o.preserveMode && perms&0700 != 0700 {
+		if err = os.Chmod(string(dst), perms); err != nil {
+			return err
+		}
+	}
+
+	return o.applyMetadata(src, dst, info)
+}
+
+func (o copyOptions) applyMetadata(src, dst Path, info os.FileInfo) error {
+	// ownership first, since changing it can clear setuid and setgid bits
+	if err := o.applyOwnership(src, dst, info); err != nil {
+		return err
+	}
+
+	if o.preserveXattrs {
+		for name, err := range copyXattrs(src, dst) {
+			attribute := "xattrs"
+
+			if len(name) > 0 {
+				attribute = "xattr " + name
+			}
+
+			if err = o.unapplied(dst, attribute, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	if o.preserveMode {
+		if err := os.Chmod(string(dst), info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	if o.preserveTimes {
+		if err := os.Chtimes(string(dst), accessTime(info), info.ModTime()); err != nil {
+			return err
+		}
+	}
+
+	// the immutable flag last, since nothing else can be changed once it is set
+	if o.archive {
+		// sources whose flags cannot be read, such as on filesystems without them, are treated as mutable
+		if immutable, err := o.privileged.IsImmutable(src); err == nil && immutable {
+			if err = o.privileged.SetImmutable(dst, true); err != nil {
+				return o.unapplied(dst, "immutable", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (o copyOptions) applyOwnership(src, dst Path, info os.FileInfo) error {
+	if !o.preserveOwner {
+		return nil
+	}
+
+	uid, gid, ok := fileOwner(info)
+
+	if !ok {
+		return o.unapplied(dst, "owner", fmt.Errorf("Ownership is not supported on this platform"))
+	}
+
+	if err := o.privileged.Lchown(dst, uid, gid); err != nil {
+		return o.unapplied(dst, "owner", err)
+	}
+
+	return nil
+}
+
+// count updates the stats, if they were requested.
+func (o copyOptions) count(fn func(*CopyStats)) {
+	if o.stats != nil {
+		fn(o.stats)
+	}
+}
+
+// timed adds the time since start to the stats' Duration, if they were requested.
+func (o copyOptions) timed(start time.Time) {
+	o.count(func(s *CopyStats) { s.Duration += time.Since(start) })
+}
+
+// unapplied records the failure to set an attribute in the report, or returns it if there is no report.
+func (o copyOptions) unapplied(dst Path, attribute string, err error) error {
+	if o.report == nil {
+		return err
+	}
+
+	o.report.Unapplied = append(o.report.Unapplied, UnappliedAttribute{Path: dst, Attribute: attribute, Err: err})
+	return nil
+}
@@ -0,0 +1,53 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestCopyTreeReport(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	src := dir.JoinPath("src")
+
+	if err := CreateTree(src, TreeSpec{{Path: "a", Content: "a"}, {Path: "sub/b"}}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	if err := syscall.Setxattr(string(src.JoinPath("a")), "user.pathlib", []byte("value"), 0); err != nil {
+		t.Skip("Extended attributes are not supported here: " + err.Error())
+	}
+
+	// a file owned by someone else can only be reproduced with privileges
+	if err := os.Lchown(string(src.JoinPath("sub", "b")), 12345, 12345); err != nil && os.Geteuid() == 0 {
+		t.Fatalf(err.Error())
+	}
+
+	var report CopyReport
+	dst := dir.JoinPath("dst")
+
+	if err := src.CopyTree(dst, PreserveOwnership(), PreserveXattrs(), ReportUnapplied(&report)); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if value, err := getXattr(dst.JoinPath("a"), "user.pathlib"); err != nil || string(value) != "value" {
+		t.Errorf("Extended attribute was not copied: %q %v", value, err)
+	}
+
+	if os.Geteuid() == 0 {
+		info, err := os.Stat(string(dst.JoinPath("sub", "b")))
+
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		if uid, _, _ := fileOwner(info); uid != 12345 || len(report.Unapplied) != 0 {
+			t.Errorf("Ownership not preserved as root (uid %d, report %v)", uid, report.Unapplied)
+		}
+	} else if len(report.Unapplied) == 0 || report.Unapplied[0].Attribute != "owner" {
+		t.Errorf("Expected unapplied ownership in the report, received %v", report.Unapplied)
+	}
+}
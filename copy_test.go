@@ -0,0 +1,326 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCopy(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{{Path: "src", Content: "hello", Perms: 0600}, {Path: "existing", Content: "old"}})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	src := dir.JoinPath("src")
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	if err = os.Chtimes(string(src), modTime, modTime); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	dst := dir.JoinPath("dst")
+
+	if err = src.Copy(dst, PreserveMode(), PreserveTimes()); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	info, err := os.Stat(string(dst))
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if info.Mode().Perm() != 0600 || !info.ModTime().Equal(modTime) {
+		t.Errorf("Metadata not preserved: %v %v", info.Mode(), info.ModTime())
+	}
+
+	existing := dir.JoinPath("existing")
+
+	if err = src.Copy(existing); !errors.Is(err, os.ErrExist) {
+		t.Errorf("Expected os.ErrExist, received %v", err)
+	}
+
+	if err = src.Copy(existing, Overwrite()); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if contents, _ := existing.ReadBytes(); string(contents) != "hello" {
+		t.Errorf("Overwrite did not replace the contents: %q", contents)
+	}
+
+	if err = dir.Copy(dir.JoinPath("dircopy")); err == nil {
+		t.Errorf("Expected an error copying a directory with Copy")
+	}
+
+	if err = src.Copy(src, Overwrite()); err == nil {
+		t.Errorf("Expected an error copying a file onto itself")
+	}
+
+	if contents, _ := src.ReadBytes(); string(contents) != "hello" {
+		t.Errorf("Copying a file onto itself changed it: %q", contents)
+	}
+}
+
+func TestCopyTree(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	src := dir.JoinPath("src")
+
+	err := CreateTree(src, TreeSpec{
+		{Path: "a", Content: "a"}, {Path: "sub/b", Content: "b"}, {Path: "skip/c"},
+		{Path: "sub/link", Symlink: "../a"}, {Path: "sub/up", Symlink: ".."},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	dst := dir.JoinPath("dst")
+	exclude := Exclude(func(p Path) bool { return p.Name() == "skip" })
+
+	if err = src.CopyTree(dst, exclude); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if dst.JoinPath("skip").Exists() || !dst.JoinPath("sub", "b").IsFile() {
+		t.Errorf("Excluded or missing entries in the copy")
+	}
+
+	if link, err := os.Readlink(string(dst.JoinPath("sub", "link"))); err != nil || link != "../a" {
+		t.Errorf("Symbolic link not recreated: %q %v", link, err)
+	}
+
+	if err = src.CopyTree(src.JoinPath("sub", "copy")); err == nil || src.JoinPath("sub", "copy").Exists() {
+		t.Errorf("Expected an error copying a tree into itself")
+	}
+
+	if err = src.CopyTree(dst); !errors.Is(err, os.ErrExist) {
+		t.Errorf("Expected os.ErrExist, received %v", err)
+	}
+
+	if err = src.CopyTree(dst, Overwrite()); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	deref := dir.JoinPath("deref")
+
+	if err = src.CopyTree(deref, DereferenceSymlinks(), exclude); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if isSymlink(deref.JoinPath("sub", "link")) || !deref.JoinPath("sub", "link").IsFile() {
+		t.Errorf("Symbolic link to a file was not dereferenced")
+	}
+
+	if !isSymlink(deref.JoinPath("sub", "up")) {
+		t.Errorf("Symbolic link loop should have been recreated as a link")
+	}
+}
+
+func TestCopyTreeUmask(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no umask")
+	}
+
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	src := dir.JoinPath("src")
+
+	if err := CreateTree(src, TreeSpec{{Path: "ro/a", Content: "a", Perms: 0666}, {Path: "ro/", Perms: 0567}}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+	defer os.Chmod(string(src.JoinPath("ro")), 0700)
+
+	dst := dir.JoinPath("dst")
+	err := WithUmask(0027, func() error { return src.CopyTree(dst) })
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer os.Chmod(string(dst.JoinPath("ro")), 0700)
+
+	perms := map[Path]os.FileMode{
+		dst.JoinPath("ro"):      0540,
+		dst.JoinPath("ro", "a"): 0640,
+	}
+
+	for p, target := range perms {
+		if actual, err := p.Permissions(); err != nil || actual != target {
+			t.Errorf("%s has permissions %s, expected %s", p, actual, target)
+		}
+	}
+}
+
+func TestMove(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{{Path: "file", Content: "x"}, {Path: "tree/a", Content: "a"}})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	if err = dir.JoinPath("file").Move(dir.JoinPath("moved")); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if dir.JoinPath("file").Exists() || !dir.JoinPath("moved").IsFile() {
+		t.Errorf("File was not moved")
+	}
+
+	// exercise the cross-device fallback directly
+	if err = dir.JoinPath("tree").moveByCopy(dir.JoinPath("tree2")); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if dir.JoinPath("tree").Exists() || !dir.JoinPath("tree2", "a").IsFile() {
+		t.Errorf("Tree was not moved by copying")
+	}
+
+	other := Path(fmt.Sprintf("/dev/shm/pathlib-%s", randomString(20)))
+	err = dir.JoinPath("moved").Rename(other)
+
+	if !isCrossDevice(err) {
+		if err == nil {
+			other.Unlink()
+		}
+
+		t.Skip("/dev/shm is not a separate filesystem")
+	}
+
+	if err = dir.JoinPath("moved").Move(other); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer other.Unlink()
+
+	if dir.JoinPath("moved").Exists() || !other.IsFile() {
+		t.Errorf("File was not moved across filesystems")
+	}
+}
+
+func isSymlink(p Path) bool {
+	info, err := os.Lstat(string(p))
+	return err == nil && info.Mode()&os.ModeSymlink != 0
+}
+
+func TestCopyTreeInspect(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	err := CreateTree(dir, TreeSpec{
+		{Path: "src/clean.txt", Content: "hello"},
+		{Path: "src/sub/infected.txt", Content: "X5O!P%@AP EICAR"},
+		{Path: "src/sub/big.bin", Content: strings.Repeat("x", 1<<20)},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	scanned := make(map[Path]bool)
+	scanner := Inspect(func(src Path, r io.Reader) error {
+		scanned[src] = true
+		head := make([]byte, 4)
+		n, _ := io.ReadFull(r, head)
+
+		// stop reading early, which must not stall the copy
+		if string(head[:n]) == "X5O!" {
+			return fmt.Errorf("%w: signature found", ErrRejected)
+		}
+
+		return nil
+	})
+
+	var report CopyReport
+
+	if err = dir.JoinPath("src").CopyTree(dir.JoinPath("dst"), scanner, ReportUnapplied(&report)); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if len(scanned) != 3 {
+		t.Errorf("Expected 3 files to be scanned, received %v", scanned)
+	}
+
+	if dir.JoinPath("dst", "sub", "infected.txt").Exists() || !dir.JoinPath("dst", "sub", "big.bin").Exists() {
+		t.Errorf("Expected only the infected file to be left out")
+	}
+
+	if len(report.Rejected) != 1 || report.Rejected[0].Path != dir.JoinPath("src", "sub", "infected.txt") {
+		t.Errorf("Unexpected rejections %+v", report.Rejected)
+	}
+
+	// a vetoed file must not destroy the one it would have replaced
+	if err = dir.JoinPath("dst", "sub", "infected.txt").WriteBytes([]byte("good")); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if err = dir.JoinPath("src").CopyTree(dir.JoinPath("dst"), scanner, Overwrite()); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if contents, _ := dir.JoinPath("dst", "sub", "infected.txt").ReadBytes(); string(contents) != "good" {
+		t.Errorf("A vetoed file replaced the existing copy: %q", contents)
+	}
+
+	failing := Inspect(func(Path, io.Reader) error { return errors.New("scanner unavailable") })
+
+	if err = dir.JoinPath("src").CopyTree(dir.JoinPath("failed"), failing); err == nil {
+		t.Errorf("Expected a scanner error to fail the copy")
+	}
+
+	// FSPath copies are inspected too
+	mem := NewMemFilesystem()
+	report = CopyReport{}
+
+	if err = PathOn(OS, string(dir.JoinPath("src"))).CopyTree(PathOn(mem, "dst"), scanner, ReportUnapplied(&report)); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if _, err = mem.Stat("dst/sub/infected.txt"); err == nil || len(report.Rejected) != 1 {
+		t.Errorf("Expected the infected file to be rejected from the FSPath copy")
+	}
+}
+
+func TestCopyTreeStats(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{
+		{Path: "src/a", Content: "hello"},
+		{Path: "src/sub/b", Content: "world!"},
+		{Path: "src/link", Symlink: "a"},
+		{Path: "src/skip/c", Content: "c"},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	var stats CopyStats
+	exclude := Exclude(func(p Path) bool { return p.Name() == "skip" })
+
+	if err = dir.JoinPath("src").CopyTree(dir.JoinPath("dst"), exclude, ReportStats(&stats)); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	expected := CopyStats{Files: 2, Dirs: 2, Symlinks: 1, Bytes: 11, Excluded: 1, Duration: stats.Duration}
+
+	if stats != expected || stats.Duration <= 0 {
+		t.Errorf("Expected %+v, received %+v", expected, stats)
+	}
+}
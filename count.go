@@ -0,0 +1,127 @@
+package pathlib
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// readdirBatchSize is the number of directory entries read per call when streaming a directory.
+const readdirBatchSize = 1024
+
+// CountEntries returns the number of entries within the directory Path without building a list of Paths.  If recursive is true, entries within subdirectories are counted as well.  Symbolic links to directories are counted but not followed.
+func (p Path) CountEntries(recursive bool) (int, error) {
+	if !p.IsDir() {
+		return 0, fmt.Errorf("CountEntries only works on directories: %s", p)
+	}
+
+	return countEntries(string(p), recursive)
+}
+
+func countEntries(dir string, recursive bool) (int, error) {
+	f, err := os.Open(dir)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer f.Close()
+
+	count := 0
+
+	for {
+		if !recursive {
+			names, err := f.Readdirnames(readdirBatchSize)
+			count += len(names)
+
+			if err == io.EOF {
+				return count, nil
+			}
+
+			if err != nil {
+				return count, err
+			}
+
+			continue
+		}
+
+		// ReadDir reports entry types from the directory itself (d_type) where the filesystem supports it, avoiding a stat per entry
+		entries, err := f.ReadDir(readdirBatchSize)
+		count += len(entries)
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+
+			subCount, subErr := countEntries(filepath.Join(dir, entry.Name()), true)
+			count += subCount
+
+			if subErr != nil {
+				return count, subErr
+			}
+		}
+
+		if err == io.EOF {
+			return count, nil
+		}
+
+		if err != nil {
+			return count, err
+		}
+	}
+}
+
+// lstatParallelThreshold is the number of entries above which lstatEntries spreads the calls across a worker pool.
+const lstatParallelThreshold = 64
+
+// lstatEntries returns the FileInfo of each of the directory entries, calling lstat from a pool of workers for large directories since each call is a separate round trip to the filesystem.  Entries that have been removed since they were read are left as nil.
+func lstatEntries(entries []os.DirEntry) ([]os.FileInfo, error) {
+	infos := make([]os.FileInfo, len(entries))
+
+	workers := 1
+
+	if len(entries) >= lstatParallelThreshold {
+		workers = runtime.GOMAXPROCS(0) * 2
+	}
+
+	indexes := make(chan int)
+	var firstErr error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				info, err := entries[i].Info()
+
+				if err != nil && !os.IsNotExist(err) {
+					mu.Lock()
+
+					if firstErr == nil {
+						firstErr = err
+					}
+
+					mu.Unlock()
+				}
+
+				infos[i] = info
+			}
+		}()
+	}
+
+	for i := range entries {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+	return infos, firstErr
+}
@@ -0,0 +1,122 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCountEntries(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := root.JoinPath("a", "b").Mkdir()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer root.RmdirRecursive()
+
+	for _, name := range []Path{"one", "two", "a/three", "a/b/four"} {
+		err = root.JoinPath(name).Touch()
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+	}
+
+	count, err := root.CountEntries(false)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if count != 3 {
+		t.Errorf("Expected 3 entries, received %d", count)
+	}
+
+	count, err = root.CountEntries(true)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if count != 6 {
+		t.Errorf("Expected 6 recursive entries, received %d", count)
+	}
+}
+
+func TestCountEntriesNotDir(t *testing.T) {
+	_, err := Path("/etc/passwd").CountEntries(false)
+
+	if err == nil {
+		t.Errorf("CountEntries should fail for files")
+	}
+}
+
+func benchmarkTree(b *testing.B, dirs, filesPerDir int) Path {
+	root := Path(fmt.Sprintf("/tmp/pathlib-bench-%s", randomString(20)))
+
+	for d := 0; d < dirs; d++ {
+		dir := root.JoinPath(Path(fmt.Sprintf("dir%d", d)))
+
+		if err := dir.Mkdir(); err != nil {
+			b.Fatal(err)
+		}
+
+		for f := 0; f < filesPerDir; f++ {
+			if err := dir.JoinPath(Path(fmt.Sprintf("file%d", f))).WriteBytes([]byte("x")); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	return root
+}
+
+func BenchmarkCountEntriesRecursive(b *testing.B) {
+	root := benchmarkTree(b, 50, 200)
+	defer root.RmdirRecursive()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := root.CountEntries(true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTreeSize(b *testing.B) {
+	root := benchmarkTree(b, 50, 200)
+	defer root.RmdirRecursive()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := root.TreeSize(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkWalkStat is the naive filepath.Walk approach, which calls lstat for every entry, for comparison.
+func BenchmarkWalkStat(b *testing.B) {
+	root := benchmarkTree(b, 50, 200)
+	defer root.RmdirRecursive()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var size int64
+
+		err := filepath.Walk(string(root), func(path string, info os.FileInfo, err error) error {
+			if err == nil && info.Mode().IsRegular() {
+				size += info.Size()
+			}
+
+			return err
+		})
+
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
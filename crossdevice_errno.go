@@ -0,0 +1,18 @@
+//go:build !plan9
+
+package pathlib
+
+import (
+	"errors"
+	"runtime"
+	"syscall"
+)
+
+// isCrossDevice reports whether a rename failed because the source and target are on different filesystems.
+func isCrossDevice(err error) bool {
+	if runtime.GOOS == "windows" {
+		return errors.Is(err, syscall.Errno(17)) // ERROR_NOT_SAME_DEVICE
+	}
+
+	return errors.Is(err, syscall.EXDEV)
+}
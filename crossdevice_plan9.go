@@ -0,0 +1,6 @@
+package pathlib
+
+// isCrossDevice reports false, since Plan 9 has no cross-device error.
+func isCrossDevice(err error) bool {
+	return false
+}
@@ -0,0 +1,147 @@
+package pathlib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// DuplicateTree is a set of directories with identical recursive contents, as found by DuplicateDirs.
+type DuplicateTree struct {
+	// Hash identifies the shared contents, covering the names, types, and content of everything within each directory.
+	Hash string
+
+	// Size is the total size of the regular files within one of the directories, which is the space that removing a duplicate would reclaim.
+	Size int64
+
+	Dirs []Path
+}
+
+type dirDigest struct {
+	hash string
+	size int64
+}
+
+// DuplicateDirs finds directories beneath the directory Path whose recursive contents match, for cleanup tooling that removes redundant copies of whole trees.  Two directories match when they hold the same names with the same types, the regular files have the same content, and symbolic links have the same targets; the names of the directories themselves and metadata such as permissions and times are ignored.  Empty directories are not reported, and when matching directories are found within directories that also match, only the outermost are reported.  Symbolic links are not followed.  The results are sorted by Size, largest first.
+func (p Path) DuplicateDirs() ([]DuplicateTree, error) {
+	if !p.IsDir() {
+		return nil, fmt.Errorf("DuplicateDirs only works on directories: %s", p)
+	}
+
+	digests := map[Path]dirDigest{}
+
+	if _, err := digestDir(p, digests); err != nil {
+		return nil, err
+	}
+
+	groups := map[string][]Path{}
+
+	for dir, digest := range digests {
+		if dir != p {
+			groups[digest.hash] = append(groups[digest.hash], dir)
+		}
+	}
+
+	var duplicates []DuplicateTree
+
+	for hash, dirs := range groups {
+		if len(dirs) < 2 || hash == emptyDirHash || coveredByParents(dirs, digests) {
+			continue
+		}
+
+		sort.Slice(dirs, func(i, j int) bool { return dirs[i] < dirs[j] })
+		duplicates = append(duplicates, DuplicateTree{Hash: hash, Size: digests[dirs[0]].size, Dirs: dirs})
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Size != duplicates[j].Size {
+			return duplicates[i].Size > duplicates[j].Size
+		}
+
+		return duplicates[i].Dirs[0] < duplicates[j].Dirs[0]
+	})
+
+	return duplicates, nil
+}
+
+var emptyDirHash = hex.EncodeToString(sha256.New().Sum(nil))
+
+// digestDir hashes the directory from the sorted names, types, and hashes of its entries, recording the digest of it and every directory beneath it.
+func digestDir(dir Path, digests map[Path]dirDigest) (dirDigest, error) {
+	entries, err := os.ReadDir(string(dir))
+
+	if err != nil {
+		return dirDigest{}, err
+	}
+
+	h := sha256.New()
+	var digest dirDigest
+
+	for _, entry := range entries {
+		child := dir.JoinPath(Path(entry.Name()))
+		var kind, sum string
+
+		switch {
+		case entry.IsDir():
+			childDigest, err := digestDir(child, digests)
+
+			if err != nil {
+				return dirDigest{}, err
+			}
+
+			kind, sum = "dir", childDigest.hash
+			digest.size += childDigest.size
+		case entry.Type()&os.ModeSymlink != 0:
+			target, err := os.Readlink(string(child))
+
+			if err != nil {
+				return dirDigest{}, err
+			}
+
+			kind, sum = "symlink", target
+		case entry.Type().IsRegular():
+			info, err := entry.Info()
+
+			if err != nil {
+				return dirDigest{}, err
+			}
+
+			if sum, err = child.sha256Hex(); err != nil {
+				return dirDigest{}, err
+			}
+
+			kind = "file"
+			digest.size += info.Size()
+		default:
+			kind = entry.Type().String()
+		}
+
+		fmt.Fprintf(h, "%q %s %q\n", entry.Name(), kind, sum)
+	}
+
+	digest.hash = hex.EncodeToString(h.Sum(nil))
+	digests[dir] = digest
+	return digest, nil
+}
+
+// coveredByParents reports whether the directories' distinct parents are themselves duplicates, in which case the parents' group already covers them.
+func coveredByParents(dirs []Path, digests map[Path]dirDigest) bool {
+	parents := map[Path]bool{}
+	var hash string
+
+	for _, dir := range dirs {
+		parent := dir.Parent()
+		digest, ok := digests[parent]
+
+		if !ok || parents[parent] || (hash != "" && digest.hash != hash) {
+			return false
+		}
+
+		parents[parent] = true
+		hash = digest.hash
+	}
+
+	return true
+}
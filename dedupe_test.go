@@ -0,0 +1,62 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDuplicateDirs(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	err := CreateTree(dir, TreeSpec{
+		{Path: "photos/2023/a.jpg", Content: "aaaa"},
+		{Path: "photos/2023/b.jpg", Content: "bb"},
+		{Path: "photos/notes.txt", Content: "notes"},
+		{Path: "backup/photos/2023/a.jpg", Content: "aaaa"},
+		{Path: "backup/photos/2023/b.jpg", Content: "bb"},
+		{Path: "backup/photos/notes.txt", Content: "notes"},
+		{Path: "old/2023/a.jpg", Content: "aaaa"},
+		{Path: "old/2023/b.jpg", Content: "bb"},
+		{Path: "renamed/x.jpg", Content: "aaaa"},
+		{Path: "renamed/b.jpg", Content: "bb"},
+		{Path: "links1/l", Symlink: "target"},
+		{Path: "links2/l", Symlink: "target"},
+		{Path: "empty1", Dir: true},
+		{Path: "empty2", Dir: true},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	duplicates, err := dir.DuplicateDirs()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	expected := []string{
+		fmt.Sprint([]Path{dir.JoinPath("backup", "photos"), dir.JoinPath("photos")}),
+		fmt.Sprint([]Path{dir.JoinPath("backup", "photos", "2023"), dir.JoinPath("old", "2023"), dir.JoinPath("photos", "2023")}),
+		fmt.Sprint([]Path{dir.JoinPath("links1"), dir.JoinPath("links2")}),
+	}
+
+	if len(duplicates) != len(expected) {
+		t.Fatalf("Expected %d duplicate trees but found %v", len(expected), duplicates)
+	}
+
+	for i, duplicate := range duplicates {
+		if fmt.Sprint(duplicate.Dirs) != expected[i] {
+			t.Errorf("Expected duplicate %d to be %s but was %v", i, expected[i], duplicate.Dirs)
+		}
+	}
+
+	if duplicates[0].Size != 11 || duplicates[1].Size != 6 || duplicates[2].Size != 0 {
+		t.Errorf("Unexpected sizes %d, %d, %d", duplicates[0].Size, duplicates[1].Size, duplicates[2].Size)
+	}
+
+	if _, err = dir.JoinPath("photos", "notes.txt").DuplicateDirs(); err == nil {
+		t.Errorf("Expected an error for a file")
+	}
+}
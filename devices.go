@@ -0,0 +1,13 @@
+package pathlib
+
+// DeviceInfo describes a block device or partition found by BlockDevices.
+type DeviceInfo struct {
+	Path        Path   // the device node, eg. /dev/sda1 or \\.\C:
+	Name        string // the kernel or volume name, eg. sda1 or C:
+	Size        int64  // in bytes
+	Model       string // empty for partitions and virtual devices
+	Removable   bool
+	ReadOnly    bool
+	Parent      Path   // the disk a partition belongs to, or empty for whole disks
+	MountPoints []Path // where the device is mounted, if anywhere
+}
@@ -0,0 +1,150 @@
+package pathlib
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// these are variables so tests can point them at fixtures
+var (
+	sysBlockRoot  = Path("/sys/block")
+	mountInfoPath = Path("/proc/self/mountinfo")
+)
+
+// BlockDevices lists the block devices in sysfs, like lsblk, with each disk followed by its partitions.  Mount points are matched by device number from /proc/self/mountinfo, so devices mounted under other names (eg. /dev/root or /dev/mapper links) are still found.
+func BlockDevices() ([]DeviceInfo, error) {
+	entries, err := os.ReadDir(string(sysBlockRoot))
+
+	if err != nil {
+		return nil, err
+	}
+
+	mountInfos, err := readMountInfo(mountInfoPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mounts := make(map[string][]Path)
+
+	for _, mount := range mountInfos {
+		mounts[mount.devNum] = append(mounts[mount.devNum], mount.mountPoint)
+	}
+
+	var devices []DeviceInfo
+
+	for _, entry := range entries {
+		disk := sysBlockRoot.JoinPath(Path(entry.Name()))
+		info := sysBlockDevice(disk, mounts)
+		info.Model = strings.TrimSpace(sysfsString(disk.JoinPath("device", "model")))
+		devices = append(devices, info)
+
+		partitions, err := os.ReadDir(string(disk))
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, partition := range partitions {
+			sysPartition := disk.JoinPath(Path(partition.Name()))
+
+			if !sysPartition.JoinPath("partition").Exists() {
+				continue
+			}
+
+			partitionInfo := sysBlockDevice(sysPartition, mounts)
+			partitionInfo.Removable = info.Removable
+			partitionInfo.Parent = info.Path
+			devices = append(devices, partitionInfo)
+		}
+	}
+
+	return devices, nil
+}
+
+// sysBlockDevice reads the attributes shared by disks and partitions from their sysfs directory.
+func sysBlockDevice(sys Path, mounts map[string][]Path) DeviceInfo {
+	sectors, _ := strconv.ParseInt(sysfsString(sys.JoinPath("size")), 10, 64)
+
+	return DeviceInfo{
+		Path:        Path("/dev").JoinPath(Path(sys.Name())),
+		Name:        sys.Name(),
+		Size:        sectors * 512, // sysfs always counts 512 byte sectors
+		Removable:   sysfsString(sys.JoinPath("removable")) == "1",
+		ReadOnly:    sysfsString(sys.JoinPath("ro")) == "1",
+		MountPoints: mounts[sysfsString(sys.JoinPath("dev"))],
+	}
+}
+
+// sysfsString reads a single-value sysfs attribute, returning an empty string if it is missing.
+func sysfsString(p Path) string {
+	contents, _ := p.ReadBytes()
+	return strings.TrimSpace(string(contents))
+}
+
+// mountInfo is a single line of /proc/self/mountinfo.
+type mountInfo struct {
+	devNum     string // "major:minor"
+	mountPoint Path
+	source     string // eg. /dev/sda1, or the filesystem type for virtual mounts
+}
+
+// readMountInfo reads the mounts of the calling process's namespace.
+func readMountInfo(p Path) ([]mountInfo, error) {
+	contents, err := p.ReadBytes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var mounts []mountInfo
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) < 5 {
+			continue
+		}
+
+		mount := mountInfo{devNum: fields[2], mountPoint: Path(unescapeMountPoint(fields[4]))}
+
+		// the optional fields end with a "-", followed by the filesystem type and source
+		for i := 5; i+2 < len(fields); i++ {
+			if fields[i] == "-" {
+				mount.source = unescapeMountPoint(fields[i+2])
+				break
+			}
+		}
+
+		mounts = append(mounts, mount)
+	}
+
+	return mounts, scanner.Err()
+}
+
+// unescapeMountPoint decodes the octal escapes (eg. \040 for a space) the kernel uses in mount points.
+func unescapeMountPoint(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var builder strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				builder.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+
+		builder.WriteByte(s[i])
+	}
+
+	return builder.String()
+}
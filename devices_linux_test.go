@@ -0,0 +1,54 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBlockDevices(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer root.RmdirRecursive()
+
+	err := CreateTree(root, TreeSpec{
+		{Path: "block/sda/size", Content: "2048\n"},
+		{Path: "block/sda/dev", Content: "8:0\n"},
+		{Path: "block/sda/removable", Content: "1\n"},
+		{Path: "block/sda/ro", Content: "0\n"},
+		{Path: "block/sda/device/model", Content: "Flash Drive  \n"},
+		{Path: "block/sda/sda1/partition", Content: "1\n"},
+		{Path: "block/sda/sda1/size", Content: "1024\n"},
+		{Path: "block/sda/sda1/dev", Content: "8:1\n"},
+		{Path: "block/sda/queue/rotational", Content: "0\n"},
+		{Path: "mountinfo", Content: "23 28 0:22 / /proc rw - proc proc rw\n40 28 8:1 / /media/My\\040Drive rw - vfat /dev/sda1 rw\n41 28 8:1 /sub /mnt rw - vfat /dev/sda1 rw\n"},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer func(block, mountInfo Path) { sysBlockRoot, mountInfoPath = block, mountInfo }(sysBlockRoot, mountInfoPath)
+	sysBlockRoot, mountInfoPath = root.JoinPath("block"), root.JoinPath("mountinfo")
+	devices, err := BlockDevices()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if len(devices) != 2 {
+		t.Fatalf("Expected a disk and a partition, received %+v", devices)
+	}
+
+	disk, partition := devices[0], devices[1]
+
+	if disk.Path != "/dev/sda" || disk.Size != 2048*512 || disk.Model != "Flash Drive" || !disk.Removable || disk.Parent != "" || len(disk.MountPoints) != 0 {
+		t.Errorf("Unexpected disk %+v", disk)
+	}
+
+	if partition.Path != "/dev/sda1" || partition.Size != 1024*512 || partition.Parent != "/dev/sda" || !partition.Removable {
+		t.Errorf("Unexpected partition %+v", partition)
+	}
+
+	if fmt.Sprint(partition.MountPoints) != "[/media/My Drive /mnt]" {
+		t.Errorf("Unexpected mount points %q", partition.MountPoints)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !windows
+
+package pathlib
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// BlockDevices is only supported on Linux and Windows.
+func BlockDevices() ([]DeviceInfo, error) {
+	return nil, fmt.Errorf("BlockDevices is not supported on %s", runtime.GOOS)
+}
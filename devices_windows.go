@@ -0,0 +1,74 @@
+package pathlib
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procGetLogicalDriveStringsW = kernel32.NewProc("GetLogicalDriveStringsW")
+	procGetDriveTypeW           = kernel32.NewProc("GetDriveTypeW")
+	procGetDiskFreeSpaceExW     = kernel32.NewProc("GetDiskFreeSpaceExW")
+	procGetVolumeInformationW   = kernel32.NewProc("GetVolumeInformationW")
+)
+
+const (
+	driveRemovable     = 2
+	driveCDROM         = 5
+	fileReadOnlyVolume = 0x80000
+)
+
+// BlockDevices lists the mounted volumes (drive letters), with the size and flags reported by the volume APIs.  Windows does not expose disk models or partition relationships through these APIs, so Model and Parent are left empty.
+func BlockDevices() ([]DeviceInfo, error) {
+	buf := make([]uint16, 256)
+	n, _, err := procGetLogicalDriveStringsW.Call(uintptr(len(buf)), uintptr(unsafe.Pointer(&buf[0])))
+
+	if n == 0 {
+		return nil, err
+	}
+
+	var devices []DeviceInfo
+
+	// the buffer holds NUL-terminated roots such as C:\, ending with an empty string
+	for start := 0; start < int(n); {
+		end := start
+
+		for end < int(n) && buf[end] != 0 {
+			end++
+		}
+
+		root := syscall.UTF16ToString(buf[start:end])
+		start = end + 1
+
+		if root == "" {
+			break
+		}
+
+		rootPtr, _ := syscall.UTF16PtrFromString(root)
+		driveType, _, _ := procGetDriveTypeW.Call(uintptr(unsafe.Pointer(rootPtr)))
+		name := root[:len(root)-1]
+
+		info := DeviceInfo{
+			Path:        Path(`\\.\` + name),
+			Name:        name,
+			Removable:   driveType == driveRemovable || driveType == driveCDROM,
+			MountPoints: []Path{Path(root)},
+		}
+
+		var total uint64
+
+		if ok, _, _ := procGetDiskFreeSpaceExW.Call(uintptr(unsafe.Pointer(rootPtr)), 0, uintptr(unsafe.Pointer(&total)), 0); ok != 0 {
+			info.Size = int64(total)
+		}
+
+		var flags uint32
+
+		if ok, _, _ := procGetVolumeInformationW.Call(uintptr(unsafe.Pointer(rootPtr)), 0, 0, 0, 0, uintptr(unsafe.Pointer(&flags)), 0, 0); ok != 0 {
+			info.ReadOnly = flags&fileReadOnlyVolume != 0
+		}
+
+		devices = append(devices, info)
+	}
+
+	return devices, nil
+}
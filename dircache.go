@@ -0,0 +1,142 @@
+package pathlib
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DirCache memoizes directory listings, revalidating each one against the directory's modification time, which changes whenever entries are added, removed, or renamed.  This makes repeated ReadDir and Glob calls over mostly-static trees (build systems, asset servers) cost a single stat per directory.  The zero value is ready to use and it is safe for concurrent use.
+type DirCache struct {
+	mu      sync.Mutex
+	entries map[Path]dirCacheEntry
+}
+
+type dirCacheEntry struct {
+	modTime time.Time
+	names   []string
+}
+
+// listing returns the sorted entry names of the directory, from the cache if it is still valid.
+func (c *DirCache) listing(dir Path) ([]string, error) {
+	absPath, err := filepath.Abs(string(dir))
+
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(absPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[Path(absPath)]
+	c.mu.Unlock()
+
+	if ok && entry.modTime.Equal(stat.ModTime()) {
+		return entry.names, nil
+	}
+
+	f, err := os.Open(absPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := f.Readdirnames(-1)
+	f.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[Path]dirCacheEntry)
+	}
+
+	c.entries[Path(absPath)] = dirCacheEntry{modTime: stat.ModTime(), names: names}
+	return names, nil
+}
+
+// ReadDir returns the absolute Paths of the entries in the directory, sorted by name.
+func (c *DirCache) ReadDir(dir Path) ([]Path, error) {
+	absPath, err := filepath.Abs(string(dir))
+
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := c.listing(Path(absPath))
+
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]Path, 0, len(names))
+
+	for _, name := range names {
+		paths = append(paths, Path(absPath).JoinPath(Path(name)))
+	}
+
+	return paths, nil
+}
+
+// Glob returns the absolute Paths within the directory that match the pattern, like Path.Glob, but using cached listings.  The pattern may span several directory levels (eg. "*/*.go").
+func (c *DirCache) Glob(dir Path, pattern string) ([]Path, error) {
+	absPath, err := filepath.Abs(string(dir))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	matches := []Path{Path(absPath)}
+
+	for _, component := range strings.Split(filepath.Clean(pattern), string(filepath.Separator)) {
+		var next []Path
+
+		for _, parent := range matches {
+			names, err := c.listing(parent)
+
+			if err != nil {
+				continue // not a directory
+			}
+
+			for _, name := range names {
+				if matched, _ := filepath.Match(component, name); matched {
+					next = append(next, parent.JoinPath(Path(name)))
+				}
+			}
+		}
+
+		matches = next
+	}
+
+	return matches, nil
+}
+
+// Invalidate forgets the cached listing of the directory.
+func (c *DirCache) Invalidate(dir Path) {
+	absPath, err := filepath.Abs(string(dir))
+
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, Path(absPath))
+}
@@ -0,0 +1,68 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDirCache(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(root, TreeSpec{{Path: "a/one.go"}, {Path: "a/two.txt"}, {Path: "b/three.go"}, {Path: "four.go"}})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer root.RmdirRecursive()
+
+	var cache DirCache
+	matches, err := cache.Glob(root, "*/*.go")
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if fmt.Sprint(matches) != fmt.Sprint([]Path{root.JoinPath("a/one.go"), root.JoinPath("b/three.go")}) {
+		t.Errorf("Glob returned %v", matches)
+	}
+
+	// a change that keeps the directory modification time is not noticed until invalidated
+	stat, err := os.Stat(string(root.JoinPath("a")))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	root.JoinPath("a/five.go").Touch()
+	os.Chtimes(string(root.JoinPath("a")), stat.ModTime(), stat.ModTime())
+
+	paths, err := cache.ReadDir(root.JoinPath("a"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if len(paths) != 2 {
+		t.Errorf("Expected the cached listing, received %v", paths)
+	}
+
+	later := stat.ModTime().Add(time.Second)
+	os.Chtimes(string(root.JoinPath("a")), later, later)
+	paths, err = cache.ReadDir(root.JoinPath("a"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if len(paths) != 3 || paths[0] != root.JoinPath("a/five.go") {
+		t.Errorf("Listing was not revalidated: %v", paths)
+	}
+
+	_, err = cache.Glob(root, "[")
+
+	if err == nil {
+		t.Errorf("Glob should fail for malformed patterns")
+	}
+}
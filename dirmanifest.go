@@ -0,0 +1,188 @@
+package pathlib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DirManifestName is the name of the file describing a directory's contents, as written by WriteDirManifest.
+const DirManifestName = ".manifest.json"
+
+// DirManifest describes the contents of a directory, for datasets and other directories handed between teams or systems.  Unlike the sha256sum-style manifest of WriteManifest, it also says what the data is and where it came from.
+type DirManifest struct {
+	// Schema identifies the format of the data, such as a URL or a version.
+	Schema string `json:"schema,omitempty"`
+
+	// Producer identifies what created the data, such as a job or team name.
+	Producer string `json:"producer,omitempty"`
+
+	// Description says what the data is.
+	Description string `json:"description,omitempty"`
+
+	// Created is when the manifest was written.
+	Created time.Time `json:"created"`
+
+	// Files maps the slash-separated name of each regular file within the directory (other than manifests) to its SHA-256 checksum.
+	Files map[string]string `json:"files"`
+}
+
+// DirManifest reads the DirManifestName file of the directory Path.  If there is none, the error wraps os.ErrNotExist.
+func (p Path) DirManifest() (DirManifest, error) {
+	return readDirManifest(OS, filepath.ToSlash(string(p)))
+}
+
+// WriteDirManifest writes the manifest to the DirManifestName file of the directory Path, with its Files set to the checksums of the directory's contents, and its Created time set to now if it is zero.  It is written atomically, so readers never see a partial manifest.
+func (p Path) WriteDirManifest(manifest DirManifest) error {
+	files, err := checksumDir(OS, filepath.ToSlash(string(p)))
+
+	if err != nil {
+		return err
+	}
+
+	manifest.Files = files
+
+	if manifest.Created.IsZero() {
+		manifest.Created = time.Now().UTC()
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return p.JoinPath(DirManifestName).writeBytesAtomic(append(data, '\n'), 0644)
+}
+
+// VerifyDirManifest checks the files within the directory Path against the checksums of its DirManifestName file.  Files that are missing, modified, or not listed make it return an error wrapping ErrManifestMismatch.
+func (p Path) VerifyDirManifest() error {
+	return verifyDirManifest(OS, filepath.ToSlash(string(p)))
+}
+
+// VerifyDirManifests makes CopyTree and SyncTo check every directory within the copy that has a DirManifestName file against it once the copy is complete, so a copy that differs from what the producer described is caught.  Mismatches are returned as a MultiError of errors wrapping ErrManifestMismatch, keyed by the directories within the copy.
+func VerifyDirManifests() CopyOption {
+	return func(o *copyOptions) {
+		o.verifyManifests = true
+	}
+}
+
+func readDirManifest(fsys fs.FS, dir string) (DirManifest, error) {
+	var manifest DirManifest
+	data, err := fs.ReadFile(fsys, path.Join(dir, DirManifestName))
+
+	if err != nil {
+		return manifest, err
+	}
+
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("Malformed manifest %s: %w", path.Join(dir, DirManifestName), err)
+	}
+
+	return manifest, nil
+}
+
+// checksumDir returns the SHA-256 checksums of the regular files within dir, other than manifests, keyed by their slash-separated names relative to dir.
+func checksumDir(fsys fs.FS, dir string) (map[string]string, error) {
+	files := map[string]string{}
+
+	err := fs.WalkDir(fsys, dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.Type().IsRegular() || entry.Name() == DirManifestName {
+			return nil
+		}
+
+		f, err := fsys.Open(name)
+
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+		h := sha256.New()
+
+		if _, err = io.Copy(h, f); err != nil {
+			return err
+		}
+
+		rel := strings.TrimPrefix(name, strings.TrimSuffix(dir, "/")+"/")
+
+		if dir == "." {
+			rel = name
+		}
+
+		files[rel] = hex.EncodeToString(h.Sum(nil))
+		return nil
+	})
+
+	return files, err
+}
+
+func verifyDirManifest(fsys fs.FS, dir string) error {
+	manifest, err := readDirManifest(fsys, dir)
+
+	if err != nil {
+		return err
+	}
+
+	actual, err := checksumDir(fsys, dir)
+
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+
+	for name, sum := range actual {
+		if want, ok := manifest.Files[name]; !ok {
+			problems = append(problems, "unlisted "+name)
+		} else if want != sum {
+			problems = append(problems, "modified "+name)
+		}
+	}
+
+	for name := range manifest.Files {
+		if _, ok := actual[name]; !ok {
+			problems = append(problems, "missing "+name)
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("%s (%s): %w", dir, strings.Join(problems, ", "), ErrManifestMismatch)
+	}
+
+	return nil
+}
+
+// verifyDirManifests verifies each directory within root that has a manifest, recording the failures in errs.
+func verifyDirManifests(fsys fs.FS, root string, errs MultiError) error {
+	return fs.WalkDir(fsys, root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.Name() != DirManifestName || entry.IsDir() {
+			return nil
+		}
+
+		dir := path.Dir(name)
+
+		if err = verifyDirManifest(fsys, dir); err != nil {
+			errs.add(Path(filepath.FromSlash(dir)), err)
+		}
+
+		return nil
+	})
+}
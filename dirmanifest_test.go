@@ -0,0 +1,86 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestDirManifest(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{{Path: "data.csv", Content: "a,b\n"}, {Path: "parts/1.csv", Content: "1,2\n"}})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	if _, err = dir.DirManifest(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected ErrNotExist without a manifest, received %v", err)
+	}
+
+	if err = dir.WriteDirManifest(DirManifest{Schema: "sales/v2", Producer: "nightly-export"}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	manifest, err := dir.DirManifest()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if manifest.Schema != "sales/v2" || manifest.Producer != "nightly-export" || manifest.Created.IsZero() || len(manifest.Files) != 2 || len(manifest.Files["parts/1.csv"]) != 64 {
+		t.Errorf("Unexpected manifest %+v", manifest)
+	}
+
+	if err = dir.VerifyDirManifest(); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	dir.JoinPath("data.csv").WriteBytes([]byte("changed"))
+	dir.JoinPath("extra").WriteBytes([]byte("extra"))
+
+	if err = dir.VerifyDirManifest(); !errors.Is(err, ErrManifestMismatch) {
+		t.Errorf("Expected ErrManifestMismatch, received %v", err)
+	}
+}
+
+func TestCopyTreeVerifyDirManifests(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{{Path: "src/good/a", Content: "a"}, {Path: "src/bad/b", Content: "b"}, {Path: "src/bad/skip", Content: "s"}})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	for _, sub := range []Path{"src/good", "src/bad"} {
+		if err = dir.JoinPath(sub).WriteDirManifest(DirManifest{}); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	// leaving a listed file out of the copy makes it fail verification
+	exclude := Exclude(func(p Path) bool { return p.Name() == "skip" })
+	err = dir.JoinPath("src").CopyTree(dir.JoinPath("dst"), exclude, VerifyDirManifests())
+	var errs MultiError
+
+	if !errors.As(err, &errs) || len(errs) != 1 || !errors.Is(errs[dir.JoinPath("dst/bad")], ErrManifestMismatch) {
+		t.Errorf("Expected a mismatch for only dst/bad, received %v", err)
+	}
+
+	// the same through Filesystems
+	mem := NewMemFilesystem()
+	err = PathOn(OS, string(dir.JoinPath("src"))).CopyTree(PathOn(mem, "dst"), exclude, VerifyDirManifests())
+
+	if !errors.As(err, &errs) || len(errs) != 1 || !errors.Is(errs["dst/bad"], ErrManifestMismatch) {
+		t.Errorf("Expected a mismatch for only dst/bad, received %v", err)
+	}
+
+	if err = PathOn(OS, string(dir.JoinPath("src"))).SyncTo(PathOn(mem, "dst"), VerifyDirManifests()); err != nil {
+		t.Errorf("Expected the complete copy to verify, received %v", err)
+	}
+}
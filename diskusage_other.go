@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DiskUsage is not supported on this platform.
+func (p Path) DiskUsage() (DiskUsage, error) {
+	return DiskUsage{}, fmt.Errorf("DiskUsage is not supported on this platform: %w", errors.ErrUnsupported)
+}
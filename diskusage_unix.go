@@ -0,0 +1,20 @@
+//go:build linux || darwin || freebsd
+
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+// DiskUsage returns the size and free space of the filesystem holding the Path.
+func (p Path) DiskUsage() (DiskUsage, error) {
+	var stat syscall.Statfs_t
+
+	if err := syscall.Statfs(string(p), &stat); err != nil {
+		return DiskUsage{}, &os.PathError{Op: "statfs", Path: string(p), Err: err}
+	}
+
+	size := int64(stat.Bsize)
+	return DiskUsage{Total: int64(stat.Blocks) * size, Free: int64(stat.Bfree) * size, Available: int64(stat.Bavail) * size}, nil
+}
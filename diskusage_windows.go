@@ -0,0 +1,25 @@
+package pathlib
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// DiskUsage returns the size and free space of the volume holding the Path.  Available accounts for any disk quota of the current user.
+func (p Path) DiskUsage() (DiskUsage, error) {
+	ptr, err := syscall.UTF16PtrFromString(string(p))
+
+	if err != nil {
+		return DiskUsage{}, err
+	}
+
+	var available, total, free uint64
+	ok, _, err := procGetDiskFreeSpaceExW.Call(uintptr(unsafe.Pointer(ptr)), uintptr(unsafe.Pointer(&available)), uintptr(unsafe.Pointer(&total)), uintptr(unsafe.Pointer(&free)))
+
+	if ok == 0 {
+		return DiskUsage{}, &os.PathError{Op: "GetDiskFreeSpaceEx", Path: string(p), Err: err}
+	}
+
+	return DiskUsage{Total: int64(total), Free: int64(free), Available: int64(available)}, nil
+}
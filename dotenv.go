@@ -0,0 +1,174 @@
+package pathlib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ReadDotenv parses the .env file Path into a map of variable names to values.  Blank lines, comments, and an optional leading "export" are ignored.  Double-quoted values support \n, \t, \", and \\ escapes, single-quoted values are taken literally, and unquoted values end at an inline " #" comment.
+func (p Path) ReadDotenv() (map[string]string, error) {
+	contents, err := p.ReadBytes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	env := make(map[string]string)
+
+	for i, line := range strings.Split(string(contents), "\n") {
+		key, value, ok, err := parseDotenvLine(line)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", p, i+1, err)
+		}
+
+		if ok {
+			env[key] = value
+		}
+	}
+
+	return env, nil
+}
+
+// WriteDotenv writes the variables to the .env file Path.  If the file already exists, its comments and the order of its variables are preserved: existing variables are updated in place, variables missing from env are removed, and new variables are appended in sorted order.
+func (p Path) WriteDotenv(env map[string]string) error {
+	var lines []string
+
+	if p.Exists() {
+		contents, err := p.ReadBytes()
+
+		if err != nil {
+			return err
+		}
+
+		lines = strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n")
+	}
+
+	written := make(map[string]bool)
+	out := make([]string, 0, len(lines)+len(env))
+
+	for _, line := range lines {
+		key, _, ok, err := parseDotenvLine(line)
+
+		if err != nil || !ok {
+			out = append(out, line)
+			continue
+		}
+
+		value, keep := env[key]
+
+		if !keep || written[key] {
+			continue
+		}
+
+		prefix := ""
+
+		if strings.HasPrefix(strings.TrimSpace(line), "export ") {
+			prefix = "export "
+		}
+
+		out = append(out, prefix+key+"="+quoteDotenvValue(value))
+		written[key] = true
+	}
+
+	keys := make([]string, 0, len(env))
+
+	for key := range env {
+		if !written[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		out = append(out, key+"="+quoteDotenvValue(env[key]))
+	}
+
+	return p.WriteBytes([]byte(strings.Join(out, "\n") + "\n"))
+}
+
+// parseDotenvLine parses a single line of a .env file.  The ok result is false for blank lines and comments.
+func parseDotenvLine(line string) (key, value string, ok bool, err error) {
+	line = strings.TrimSpace(line)
+
+	if len(line) == 0 || strings.HasPrefix(line, "#") {
+		return "", "", false, nil
+	}
+
+	line = strings.TrimPrefix(line, "export ")
+	eq := strings.Index(line, "=")
+
+	if eq <= 0 {
+		return "", "", false, fmt.Errorf("expected KEY=VALUE: %q", line)
+	}
+
+	key = strings.TrimSpace(line[:eq])
+	raw := strings.TrimSpace(line[eq+1:])
+
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		value, err = unquoteDotenvValue(raw[1:])
+	case strings.HasPrefix(raw, "'"):
+		end := strings.Index(raw[1:], "'")
+
+		if end < 0 {
+			return "", "", false, fmt.Errorf("unterminated quote for %s", key)
+		}
+
+		value = raw[1 : end+1]
+	default:
+		if comment := strings.Index(raw, " #"); comment >= 0 {
+			raw = raw[:comment]
+		}
+
+		value = strings.TrimSpace(raw)
+	}
+
+	if err != nil {
+		return "", "", false, fmt.Errorf("%w for %s", err, key)
+	}
+
+	return key, value, true, nil
+}
+
+func unquoteDotenvValue(raw string) (string, error) {
+	var builder strings.Builder
+
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+
+		if c == '"' {
+			return builder.String(), nil
+		}
+
+		if c == '\\' && i+1 < len(raw) {
+			i++
+
+			switch raw[i] {
+			case 'n':
+				builder.WriteByte('\n')
+			case 't':
+				builder.WriteByte('\t')
+			default:
+				builder.WriteByte(raw[i])
+			}
+
+			continue
+		}
+
+		builder.WriteByte(c)
+	}
+
+	return "", fmt.Errorf("unterminated quote")
+}
+
+func quoteDotenvValue(value string) string {
+	if !strings.ContainsAny(value, " \t\n#\"'\\=") {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,79 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestReadDotenv(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s.env", randomString(20)))
+	err := p.WriteBytes([]byte("# database settings\nexport DB_HOST=localhost # inline\nDB_PASS=\"se cret\\n\"\nLITERAL='a\\nb'\n\nEMPTY=\n"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	env, err := p.ReadDotenv()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	tests := map[string]string{
+		"DB_HOST": "localhost",
+		"DB_PASS": "se cret\n",
+		"LITERAL": `a\nb`,
+		"EMPTY":   "",
+	}
+
+	if len(env) != len(tests) {
+		t.Errorf("Expected %d variables, received %d", len(tests), len(env))
+	}
+
+	for key, target := range tests {
+		if env[key] != target {
+			t.Errorf("%s = %q, expected %q", key, env[key], target)
+		}
+	}
+}
+
+func TestWriteDotenv(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s.env", randomString(20)))
+	err := p.WriteBytes([]byte("# header\nB=1\nexport A=2\nREMOVED=3\n"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	err = p.WriteDotenv(map[string]string{"A": "two words", "B": "1", "D": "4", "C": "3"})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	contents, err := p.ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	target := "# header\nB=1\nexport A=\"two words\"\nC=3\nD=4\n"
+
+	if string(contents) != target {
+		t.Errorf("WriteDotenv wrote %q, expected %q", contents, target)
+	}
+
+	env, err := p.ReadDotenv()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if env["A"] != "two words" {
+		t.Errorf("Round trip failed: %q", env["A"])
+	}
+}
@@ -0,0 +1,166 @@
+package pathlib
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// DownloadOptions controls the behavior of DownloadFrom.  The zero value is usable.
+type DownloadOptions struct {
+	// Client is the HTTP client used for the request.  If nil, http.DefaultClient is used.
+	Client *http.Client
+
+	// Checksum is the expected hex-encoded digest of the complete file.  If empty, no verification is done.
+	Checksum string
+
+	// Hash creates the hash used to verify Checksum.  If nil, SHA-256 is used.
+	Hash func() hash.Hash
+
+	// Progress, if set, is called as data is written with the bytes written so far and the total size, which is -1 if unknown.
+	Progress func(written, total int64)
+}
+
+// DownloadFrom streams the contents of the url to the Path.  Data is written to a ".part" file next to the Path and only renamed into place once the download completes and passes checksum verification.  If a ".part" file is left over from an interrupted download, the download is resumed from where it left off when the server supports Range requests.
+func (p Path) DownloadFrom(ctx context.Context, url string, opts DownloadOptions) error {
+	if p.IsDir() {
+		return fmt.Errorf("Cannot download to %s because it is a directory.", p)
+	}
+
+	client := opts.Client
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	newHash := opts.Hash
+
+	if newHash == nil {
+		newHash = sha256.New
+	}
+
+	partial := Path(string(p) + ".part")
+	var offset int64
+
+	if stat, err := os.Stat(string(partial)); err == nil {
+		offset = stat.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	flag := os.O_WRONLY | os.O_CREATE
+	body, length := io.Reader(resp.Body), resp.ContentLength
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			return fmt.Errorf("Unexpected Content-Range from %s: %s", url, resp.Header.Get("Content-Range"))
+		}
+
+		flag |= os.O_APPEND
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the ".part" file already holds the whole file, and only needs verifying and renaming
+		if offset == 0 || resp.Header.Get("Content-Range") != fmt.Sprintf("bytes */%d", offset) {
+			return fmt.Errorf("Download of %s failed: %s", url, resp.Status)
+		}
+
+		flag |= os.O_APPEND
+		body, length = http.NoBody, 0
+	case http.StatusOK:
+		offset = 0
+		flag |= os.O_TRUNC
+	default:
+		return fmt.Errorf("Download of %s failed: %s", url, resp.Status)
+	}
+
+	h := newHash()
+
+	if offset > 0 && len(opts.Checksum) > 0 {
+		existing, err := os.Open(string(partial))
+
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(h, existing)
+		existing.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	outfile, err := os.OpenFile(string(partial), flag, 0644)
+
+	if err != nil {
+		return err
+	}
+
+	total := int64(-1)
+
+	if length >= 0 {
+		total = offset + length
+	}
+
+	w := &progressWriter{written: offset, total: total, progress: opts.Progress}
+	_, err = io.Copy(io.MultiWriter(outfile, h, w), body)
+	closeErr := outfile.Close()
+
+	if err != nil {
+		return err
+	}
+
+	if closeErr != nil {
+		return closeErr
+	}
+
+	if len(opts.Checksum) > 0 {
+		sum := hex.EncodeToString(h.Sum(nil))
+
+		if !strings.EqualFold(sum, opts.Checksum) {
+			partial.Unlink()
+			return fmt.Errorf("Checksum mismatch for %s: expected %s, received %s", url, opts.Checksum, sum)
+		}
+	}
+
+	return partial.Rename(p)
+}
+
+// progressWriter reports the number of bytes that pass through it.
+type progressWriter struct {
+	written  int64
+	total    int64
+	progress func(written, total int64)
+}
+
+func (w *progressWriter) Write(data []byte) (int, error) {
+	w.written += int64(len(data))
+
+	if w.progress != nil {
+		w.progress(w.written, w.total)
+	}
+
+	return len(data), nil
+}
@@ -0,0 +1,116 @@
+package pathlib
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownloadFrom(t *testing.T) {
+	content := []byte(strings.Repeat("pathlib download test ", 1000))
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "data.bin", time.Now(), bytes.NewReader(content))
+	}))
+
+	defer server.Close()
+
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer p.Unlink()
+
+	// simulate an interrupted download
+	err := Path(string(p) + ".part").WriteBytes(content[:100])
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	var lastWritten int64
+	opts := DownloadOptions{
+		Checksum: checksum,
+		Progress: func(written, total int64) { lastWritten = written },
+	}
+
+	err = p.DownloadFrom(context.Background(), server.URL, opts)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	downloaded, err := p.ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if !bytes.Equal(downloaded, content) {
+		t.Errorf("Downloaded content does not match")
+	}
+
+	if lastWritten != int64(len(content)) {
+		t.Errorf("Progress reported %d bytes, expected %d", lastWritten, len(content))
+	}
+
+	if Path(string(p) + ".part").Exists() {
+		t.Errorf("Partial file should have been renamed")
+	}
+}
+
+func TestDownloadFromBadChecksum(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+
+	defer server.Close()
+
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := p.DownloadFrom(context.Background(), server.URL, DownloadOptions{Checksum: "00"})
+
+	if err == nil {
+		t.Errorf("DownloadFrom should fail with a bad checksum")
+	}
+
+	if p.Exists() || Path(string(p)+".part").Exists() {
+		t.Errorf("Failed download should not leave files behind")
+	}
+}
+
+func TestDownloadFromCompletePart(t *testing.T) {
+	content := []byte("already downloaded")
+	sum := sha256.Sum256(content)
+
+	// ServeContent answers a range starting at the end with 416 and "bytes */N"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "data.bin", time.Now(), bytes.NewReader(content))
+	}))
+
+	defer server.Close()
+
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer p.Unlink()
+
+	err := Path(string(p) + ".part").WriteBytes(content)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	err = p.DownloadFrom(context.Background(), server.URL, DownloadOptions{Checksum: hex.EncodeToString(sum[:])})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if downloaded, _ := p.ReadBytes(); !bytes.Equal(downloaded, content) || Path(string(p)+".part").Exists() {
+		t.Errorf("Complete partial file was not renamed into place")
+	}
+}
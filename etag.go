@@ -0,0 +1,84 @@
+package pathlib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// ETagStrategy selects how ETags are computed.
+type ETagStrategy int
+
+const (
+	// ETagModTime derives the ETag from the file size and modification time, which is cheap but changes whenever the file is touched.
+	ETagModTime ETagStrategy = iota
+
+	// ETagContentHash derives the ETag from a SHA-256 hash of the contents, which requires reading the whole file.
+	ETagContentHash
+)
+
+// ETag returns a quoted HTTP entity tag for the file Path based on its size and modification time.
+func (p Path) ETag() (string, error) {
+	return p.ETagWithStrategy(ETagModTime)
+}
+
+// ETagWithStrategy returns a quoted HTTP entity tag for the file Path using the given strategy.
+func (p Path) ETagWithStrategy(strategy ETagStrategy) (string, error) {
+	f, err := os.Open(string(p))
+
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	stat, err := f.Stat()
+
+	if err != nil {
+		return "", err
+	}
+
+	if stat.IsDir() {
+		return "", fmt.Errorf("Cannot compute an ETag for %s because it is a directory.", p)
+	}
+
+	if strategy == ETagModTime {
+		return fmt.Sprintf(`"%x-%x"`, stat.Size(), stat.ModTime().UnixNano()), nil
+	}
+
+	h := sha256.New()
+
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`, nil
+}
+
+// ServeWithCaching replies to the request with the contents of the file Path, setting ETag and Last-Modified headers and answering If-None-Match, If-Modified-Since, and Range requests (see http.ServeContent).
+func (p Path) ServeWithCaching(w http.ResponseWriter, r *http.Request) {
+	f, err := os.Open(string(p))
+
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	defer f.Close()
+
+	stat, err := f.Stat()
+
+	if err != nil || stat.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	if etag, err := p.ETag(); err == nil {
+		w.Header().Set("ETag", etag)
+	}
+
+	http.ServeContent(w, r, p.Name(), stat.ModTime(), f)
+}
@@ -0,0 +1,81 @@
+package pathlib
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestETag(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := p.WriteBytes([]byte("etag contents"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	for _, strategy := range []ETagStrategy{ETagModTime, ETagContentHash} {
+		first, err := p.ETagWithStrategy(strategy)
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+
+		second, err := p.ETagWithStrategy(strategy)
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+
+		if first != second || len(first) < 3 || first[0] != '"' {
+			t.Errorf("ETag strategy %d is not stable: %s != %s", strategy, first, second)
+		}
+	}
+
+	_, err = Path("/tmp").ETag()
+
+	if err == nil {
+		t.Errorf("ETag should fail for directories")
+	}
+}
+
+func TestServeWithCaching(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s.txt", randomString(20)))
+	err := p.WriteBytes([]byte("cached contents"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	w := httptest.NewRecorder()
+	p.ServeWithCaching(w, httptest.NewRequest("GET", "/", nil))
+	etag := w.Header().Get("ETag")
+	lastModified := w.Header().Get("Last-Modified")
+
+	if w.Code != http.StatusOK || len(etag) == 0 || len(lastModified) == 0 {
+		t.Errorf("Caching headers not set: %d %v", w.Code, w.Header())
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	p.ServeWithCaching(w, r)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for matching ETag, received %d", w.Code)
+	}
+
+	r = httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("If-Modified-Since", lastModified)
+	w = httptest.NewRecorder()
+	p.ServeWithCaching(w, r)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for If-Modified-Since, received %d", w.Code)
+	}
+}
@@ -0,0 +1,178 @@
+package pathlib
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// LockExt is appended to the name of a file to name the lock file ExclusiveWriter holds while replacing it.
+const LockExt = ".lock"
+
+// ErrLocked is returned (wrapped) by ExclusiveWriter when another writer holds the lock.
+var ErrLocked = errors.New("locked by another writer")
+
+// ExclusiveFileWriter replaces the contents of a file while holding its lock file.  Create one with ExclusiveWriter.
+type ExclusiveFileWriter struct {
+	path  Path
+	lock  Path
+	tmp   *os.File
+	perms os.FileMode
+	done  bool
+}
+
+// ExclusiveWriter elects a single writer among processes that update the same file Path.  The writer that creates the lock file (the Path with LockExt appended) wins; the others get an error wrapping ErrLocked and should retry later or give up.  What is written goes to a temporary file that Close syncs and renames into place, so readers see either the old or the new contents, never a partial write, and the update survives a crash once Close returns.  The permissions of an existing file are preserved.  The lock file holds the host name and process ID of the writer, so a lock left behind by a writer that crashed is taken over once that process is gone.  Whether a process on another host is running cannot be checked, so a lock left by a writer there must be removed by hand.
+func ExclusiveWriter(p Path) (*ExclusiveFileWriter, error) {
+	lock := p + LockExt
+	f, err := os.OpenFile(string(lock), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+
+	if os.IsExist(err) && breakStaleLock(lock) {
+		f, err = os.OpenFile(string(lock), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	}
+
+	if os.IsExist(err) {
+		return nil, fmt.Errorf("Cannot write %s: %w", p, ErrLocked)
+	} else if err != nil {
+		return nil, err
+	}
+
+	host, _ := os.Hostname()
+	fmt.Fprintf(f, "%s %d\n", host, os.Getpid())
+
+	if err = f.Close(); err != nil {
+		os.Remove(string(lock))
+		return nil, err
+	}
+
+	perms := os.FileMode(0644)
+
+	if stat, err := os.Stat(string(p)); err == nil {
+		perms = stat.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(string(p.Parent()), "."+p.Name()+".tmp")
+
+	if err != nil {
+		os.Remove(string(lock))
+		return nil, err
+	}
+
+	return &ExclusiveFileWriter{path: p, lock: lock, tmp: tmp, perms: perms}, nil
+}
+
+// Write writes to the new contents of the file.
+func (w *ExclusiveFileWriter) Write(data []byte) (int, error) {
+	if w.done {
+		return 0, os.ErrClosed
+	}
+
+	return w.tmp.Write(data)
+}
+
+// Close syncs the new contents, renames them into place, syncs the directory, and releases the lock.  If any step fails, the file is left as it was and the lock is released.
+func (w *ExclusiveFileWriter) Close() error {
+	if w.done {
+		return os.ErrClosed
+	}
+
+	w.done = true
+	defer os.Remove(string(w.lock))
+	defer os.Remove(w.tmp.Name()) // no-op once renamed
+
+	err := w.tmp.Sync()
+
+	if closeErr := w.tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Chmod(w.tmp.Name(), w.perms)
+	}
+
+	if err == nil {
+		err = os.Rename(w.tmp.Name(), string(w.path))
+	}
+
+	if err == nil {
+		err = syncDir(w.path.Parent())
+	}
+
+	return err
+}
+
+// Abort discards what was written and releases the lock, leaving the file as it was.
+func (w *ExclusiveFileWriter) Abort() error {
+	if w.done {
+		return os.ErrClosed
+	}
+
+	w.done = true
+	w.tmp.Close()
+	os.Remove(w.tmp.Name())
+	return os.Remove(string(w.lock))
+}
+
+// breakStaleLock removes the lock file if the process that holds it is no longer running on this host, returning true if it did.  The lock is first moved aside, so that of several processes finding the same stale lock, only the one that moves it removes it; one that moves aside a lock taken meanwhile puts it back.
+func breakStaleLock(lock Path) bool {
+	contents, err := lock.ReadBytes()
+
+	if err != nil || !lockIsStale(contents) {
+		return false
+	}
+
+	aside := Path(fmt.Sprintf("%s.%d.stale", lock, os.Getpid()))
+
+	if err = os.Rename(string(lock), string(aside)); err != nil {
+		return false
+	}
+
+	defer os.Remove(string(aside))
+
+	if moved, err := aside.ReadBytes(); err != nil || !bytes.Equal(moved, contents) {
+		os.Link(string(aside), string(lock)) // fails if yet another lock was taken, which then holds
+		return false
+	}
+
+	return true
+}
+
+// lockIsStale reports whether the contents of a lock file name a process on this host that is no longer running.
+func lockIsStale(contents []byte) bool {
+	host, pid, ok := strings.Cut(strings.TrimSpace(string(contents)), " ")
+	id, err := strconv.Atoi(pid)
+
+	if !ok || err != nil {
+		return false
+	}
+
+	if current, _ := os.Hostname(); host != current {
+		return false
+	}
+
+	return !processAlive(id)
+}
+
+// syncDir flushes the directory, so that renames within it survive a crash.  Windows cannot sync directories, and does not need to.
+func syncDir(dir Path) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	f, err := os.Open(string(dir))
+
+	if err != nil {
+		return err
+	}
+
+	err = f.Sync()
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
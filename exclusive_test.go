@@ -0,0 +1,143 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"testing"
+)
+
+func TestExclusiveWriter(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{{Path: "config", Content: "old", Perms: 0600}})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	p := dir.JoinPath("config")
+	var wg sync.WaitGroup
+	writers := make(chan *ExclusiveFileWriter, 10)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			w, err := ExclusiveWriter(p)
+
+			if err == nil {
+				writers <- w
+			} else if !errors.Is(err, ErrLocked) {
+				t.Errorf(err.Error())
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(writers)
+
+	if len(writers) != 1 {
+		t.Fatalf("Expected exactly one writer to win, %d did", len(writers))
+	}
+
+	w := <-writers
+	w.Write([]byte("new "))
+	w.Write([]byte("contents"))
+
+	// readers see the old contents until Close
+	if data, _ := p.ReadBytes(); string(data) != "old" {
+		t.Errorf("Expected the old contents before Close, received %q", data)
+	}
+
+	if err = w.Close(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if data, _ := p.ReadBytes(); string(data) != "new contents" {
+		t.Errorf("Expected the new contents, received %q", data)
+	}
+
+	if stat, _ := os.Stat(string(p)); stat.Mode().Perm() != 0600 {
+		t.Errorf("Expected the permissions to be preserved, received %s", stat.Mode())
+	}
+
+	if entries, _ := os.ReadDir(string(dir)); len(entries) != 1 {
+		t.Errorf("Expected the lock and temporary files to be removed, received %v", entries)
+	}
+
+	// the lock is released, so the next round has a winner too
+	w, err = ExclusiveWriter(p)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	w.Write([]byte("discarded"))
+
+	if err = w.Abort(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if data, _ := p.ReadBytes(); string(data) != "new contents" {
+		t.Errorf("Expected Abort to leave the contents, received %q", data)
+	}
+
+	if _, err = w.Write([]byte("late")); err == nil {
+		t.Errorf("Expected writing after Abort to fail")
+	}
+}
+
+func TestExclusiveWriterStaleLock(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{{Path: "config", Content: "old"}})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	// a process that has exited stands in for a writer that crashed
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+
+	if err = cmd.Run(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	p := dir.JoinPath("config")
+	host, _ := os.Hostname()
+	locks := map[string]bool{
+		fmt.Sprintf("%s %d\n", host, cmd.Process.Pid):       true,
+		fmt.Sprintf("%s %d\n", host, os.Getpid()):           false,
+		fmt.Sprintf("other-%s %d\n", host, cmd.Process.Pid): false,
+	}
+
+	for contents, stale := range locks {
+		if err = (p + LockExt).WriteBytes([]byte(contents)); err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		w, err := ExclusiveWriter(p)
+
+		if stale && err != nil {
+			t.Errorf("Expected the lock %q to be taken over, received %v", contents, err)
+		} else if !stale && !errors.Is(err, ErrLocked) {
+			t.Errorf("Expected the lock %q to hold, received %v", contents, err)
+		}
+
+		if w != nil {
+			w.Abort()
+		}
+
+		os.Remove(string(p + LockExt))
+	}
+
+	if entries, _ := os.ReadDir(string(dir)); len(entries) != 1 {
+		t.Errorf("Expected the stale lock to be removed, received %v", entries)
+	}
+}
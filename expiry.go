@@ -0,0 +1,134 @@
+package pathlib
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ExpiryExt is the extension of the sidecar files SetExpiry writes where extended attributes are unavailable, so the expiry of "data.bin" is kept in "data.bin.expires".
+const ExpiryExt = ".expires"
+
+// expiryXattr is the extended attribute SetExpiry stores the expiry time in.
+const expiryXattr = "user.pathlib.expires"
+
+// SetExpiry marks the file or directory Path to be removed by SweepExpired once the time has passed, so retention policies for temporary data live with the data.  The time is kept in an extended attribute where possible, and otherwise in a sidecar file (see ExpiryExt) holding the time in RFC 3339 format.  A zero time clears the expiry.
+func (p Path) SetExpiry(t time.Time) error {
+	if _, err := os.Lstat(string(p)); err != nil {
+		return err
+	}
+
+	sidecar := p.Sidecar(ExpiryExt)
+
+	if t.IsZero() {
+		if err := removeXattr(p, expiryXattr); err != nil {
+			return err
+		}
+
+		if err := os.Remove(string(sidecar)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	value := []byte(t.UTC().Format(time.RFC3339Nano))
+
+	if setXattr(p, expiryXattr, value) == nil {
+		if err := os.Remove(string(sidecar)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	return sidecar.writeBytesAtomic(append(value, '\n'), 0644)
+}
+
+// Expiry returns the time set on the Path by SetExpiry, and false if it has none.
+func (p Path) Expiry() (time.Time, bool, error) {
+	value, err := getXattr(p, expiryXattr)
+
+	if err != nil {
+		value, err = p.Sidecar(ExpiryExt).ReadBytes()
+
+		if os.IsNotExist(err) {
+			return time.Time{}, false, nil
+		} else if err != nil {
+			return time.Time{}, false, err
+		}
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(value)))
+
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return t, true, nil
+}
+
+// SweepExpired removes everything beneath root whose expiry (see SetExpiry) has passed, along with its expiry sidecar, returning the Paths removed.  Expired directories are removed with everything within them.  Expiry sidecars whose files no longer exist are removed too, if their contents show SetExpiry wrote them.
+func SweepExpired(root Path) ([]Path, error) {
+	var removed []Path
+	now := time.Now()
+
+	err := filepath.Walk(string(root), func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) && path != string(root) {
+			return nil // a sidecar removed along with its file
+		} else if err != nil {
+			return err
+		}
+
+		p := Path(path)
+
+		if owner, ok := p.SidecarOwner(ExpiryExt); ok && info.Mode().IsRegular() {
+			if !owner.lexists() && isExpirySidecar(p) {
+				return os.Remove(path)
+			}
+
+			return nil
+		}
+
+		expiry, ok, err := p.Expiry()
+
+		if err != nil || !ok || expiry.After(now) {
+			return err
+		}
+
+		if err = os.RemoveAll(path); err != nil {
+			return err
+		}
+
+		if err = os.Remove(string(p.Sidecar(ExpiryExt))); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		removed = append(removed, p)
+
+		if info.IsDir() {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+
+	return removed, err
+}
+
+// isExpirySidecar reports whether the file holds an expiry time as written by SetExpiry, so files that merely share the extension are left alone.
+func isExpirySidecar(p Path) bool {
+	if info, err := os.Stat(string(p)); err != nil || info.Size() > 64 {
+		return false
+	}
+
+	value, err := p.ReadBytes()
+
+	if err != nil {
+		return false
+	}
+
+	_, err = time.Parse(time.RFC3339Nano, strings.TrimSpace(string(value)))
+	return err == nil
+}
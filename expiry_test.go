@@ -0,0 +1,76 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestExpiry(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	err := CreateTree(dir, TreeSpec{
+		{Path: "expired.bin", Content: "x"},
+		{Path: "fresh.bin", Content: "x"},
+		{Path: "forever.bin", Content: "x"},
+		{Path: "cache/entry", Content: "x"},
+		{Path: "orphan.bin.expires", Content: "2000-01-01T00:00:00Z\n"},
+		{Path: "notes.expires", Content: "renew the certificates\n"},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	for p, expiry := range map[Path]time.Time{"expired.bin": past, "fresh.bin": future, "cache": past} {
+		if err = dir.JoinPath(p).SetExpiry(expiry); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	expiry, ok, err := dir.JoinPath("fresh.bin").Expiry()
+
+	if err != nil || !ok || !expiry.Equal(future) {
+		t.Errorf("Expected expiry %v but was %v (%v, %v)", future, expiry, ok, err)
+	}
+
+	if _, ok, err = dir.JoinPath("forever.bin").Expiry(); ok || err != nil {
+		t.Errorf("Expected no expiry (%v)", err)
+	}
+
+	removed, err := SweepExpired(dir)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	expected := fmt.Sprint([]Path{dir.JoinPath("cache"), dir.JoinPath("expired.bin")})
+
+	if fmt.Sprint(removed) != expected {
+		t.Errorf("Expected %s to be removed but was %v", expected, removed)
+	}
+
+	for _, p := range []Path{"cache", "expired.bin", "expired.bin.expires", "cache.expires", "orphan.bin.expires"} {
+		if dir.JoinPath(p).Exists() {
+			t.Errorf("Expected %s to be removed", p)
+		}
+	}
+
+	for _, p := range []Path{"fresh.bin", "forever.bin", "notes.expires"} {
+		if !dir.JoinPath(p).Exists() {
+			t.Errorf("Expected %s to be kept", p)
+		}
+	}
+
+	if err = dir.JoinPath("fresh.bin").SetExpiry(time.Time{}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if _, ok, _ = dir.JoinPath("fresh.bin").Expiry(); ok || dir.JoinPath("fresh.bin.expires").Exists() {
+		t.Errorf("Expected the expiry to be cleared")
+	}
+}
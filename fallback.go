@@ -0,0 +1,76 @@
+package pathlib
+
+import (
+	"os"
+)
+
+// FallbackPath reads from a primary Path, falling back to a secondary replica when the primary is missing or cannot be read, as in a local cache over a network share.  Create one with Fallback.
+type FallbackPath struct {
+	Primary   Path
+	Secondary Path
+
+	// Heal copies the secondary back over the primary whenever a read falls back.  Healing is best-effort: if it fails, the read still succeeds from the secondary.
+	Heal bool
+}
+
+// Fallback returns a FallbackPath reading from primary, or from secondary when primary is missing or erroring.
+func Fallback(primary, secondary Path) FallbackPath {
+	return FallbackPath{Primary: primary, Secondary: secondary}
+}
+
+// WithHealing returns a copy of the FallbackPath that heals the primary on fallback.
+func (f FallbackPath) WithHealing() FallbackPath {
+	f.Heal = true
+	return f
+}
+
+// Exists returns true if either the primary or the secondary exists.
+func (f FallbackPath) Exists() bool {
+	return f.Primary.Exists() || f.Secondary.Exists()
+}
+
+// ReadBytes reads all the bytes from the primary, or from the secondary if that fails.  If both fail, the primary's error is returned.
+func (f FallbackPath) ReadBytes() ([]byte, error) {
+	data, err := f.Primary.ReadBytes()
+
+	if err == nil {
+		return data, nil
+	}
+
+	data, secondaryErr := f.Secondary.ReadBytes()
+
+	if secondaryErr != nil {
+		return nil, err
+	}
+
+	if f.Heal {
+		if info, statErr := os.Stat(string(f.Secondary)); statErr == nil && os.MkdirAll(string(f.Primary.Parent()), 0755) == nil {
+			f.Primary.writeBytesAtomic(data, info.Mode().Perm())
+		}
+	}
+
+	return data, nil
+}
+
+// Open opens the primary for reading, or the secondary if that fails.  With Heal set, the secondary is copied over the primary first and the healed primary is opened.  If both fail, the primary's error is returned.
+func (f FallbackPath) Open() (*os.File, error) {
+	file, err := os.Open(string(f.Primary))
+
+	if err == nil {
+		return file, nil
+	}
+
+	if f.Heal && os.MkdirAll(string(f.Primary.Parent()), 0755) == nil && f.Secondary.Copy(f.Primary, Overwrite(), PreserveMode(), PreserveTimes()) == nil {
+		if file, healedErr := os.Open(string(f.Primary)); healedErr == nil {
+			return file, nil
+		}
+	}
+
+	file, secondaryErr := os.Open(string(f.Secondary))
+
+	if secondaryErr != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
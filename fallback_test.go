@@ -0,0 +1,72 @@
+package pathlib
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestFallback(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer root.RmdirRecursive()
+
+	err := CreateTree(root, TreeSpec{
+		{Path: "cache", Dir: true},
+		{Path: "replica/data.txt", Content: "replica"},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	primary := root.JoinPath("cache", "sub", "data.txt")
+	fallback := Fallback(primary, root.JoinPath("replica", "data.txt"))
+
+	if data, err := fallback.ReadBytes(); err != nil || string(data) != "replica" {
+		t.Errorf("Expected the replica's contents, received %q: %v", data, err)
+	}
+
+	if primary.Exists() {
+		t.Errorf("The primary should not be healed without Heal")
+	}
+
+	if _, err = Fallback(primary, root.JoinPath("missing")).ReadBytes(); err == nil {
+		t.Errorf("Expected an error when both Paths are missing")
+	}
+
+	if data, err := fallback.WithHealing().ReadBytes(); err != nil || string(data) != "replica" {
+		t.Errorf("Expected the replica's contents, received %q: %v", data, err)
+	}
+
+	if data, _ := primary.ReadBytes(); string(data) != "replica" {
+		t.Errorf("Expected the primary to be healed, received %q", data)
+	}
+
+	primary.WriteBytes([]byte("primary"))
+	f, err := fallback.Open()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	data, _ := io.ReadAll(f)
+	f.Close()
+
+	if string(data) != "primary" {
+		t.Errorf("Expected the primary's contents, received %q", data)
+	}
+
+	primary.Unlink()
+	f, err = fallback.WithHealing().Open()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	opened := FromFile(f)
+	f.Close()
+
+	if opened != primary {
+		t.Errorf("Expected Open to heal and open the primary")
+	}
+}
@@ -0,0 +1,49 @@
+package pathlib
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWatchFanotify(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := dir.JoinPath("a", "b", "c").Mkdir()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := dir.WatchFanotify(ctx)
+
+	if os.IsPermission(err) {
+		t.Skip("fanotify requires CAP_SYS_ADMIN")
+	}
+
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	target := dir.JoinPath("a", "b", "c", "file")
+	err = target.WriteBytes([]byte("watched"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	for event := range events {
+		if event.Path == target && event.Op == FanotifyCloseWrite {
+			return
+		}
+	}
+
+	t.Errorf("Did not receive a close-write event for %s", target)
+}
@@ -0,0 +1,160 @@
+package pathlib
+
+import (
+	"io/fs"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// FaultConfig selects the failures a FaultyFilesystem injects.  The zero value injects none.
+type FaultConfig struct {
+	// FailEvery makes every Nth operation fail with EIO.
+	FailEvery int
+
+	// FailOps limits FailEvery and Latency to the named operations ("open", "stat", "readdir", "read", "write", "mkdir", "remove", or "rename").  Empty means every operation.
+	FailOps []string
+
+	// Latency delays every operation.
+	Latency time.Duration
+
+	// ShortReads limits how many bytes each Read on an opened file returns, to exercise readers that assume full reads.
+	ShortReads int
+
+	// DiskSize makes writes fail with ENOSPC once the total bytes written would exceed it.
+	DiskSize int64
+}
+
+// FaultyFilesystem wraps a Filesystem and injects failures, so error handling can be tested deterministically.  Only the Filesystem methods are wrapped; capability interfaces such as Symlinker are hidden.  It is safe for concurrent use.
+type FaultyFilesystem struct {
+	fsys    Filesystem
+	config  FaultConfig
+	mu      sync.Mutex
+	ops     int
+	written int64
+}
+
+// NewFaultyFilesystem returns a FaultyFilesystem injecting the configured failures into fsys.
+func NewFaultyFilesystem(fsys Filesystem, config FaultConfig) *FaultyFilesystem {
+	return &FaultyFilesystem{fsys: fsys, config: config}
+}
+
+// inject applies the configured latency and EIO failures to the operation.
+func (f *FaultyFilesystem) inject(op, name string) error {
+	if len(f.config.FailOps) > 0 && !slices.Contains(f.config.FailOps, op) {
+		return nil
+	}
+
+	time.Sleep(f.config.Latency)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ops++
+
+	if f.config.FailEvery > 0 && f.ops%f.config.FailEvery == 0 {
+		return &fs.PathError{Op: op, Path: name, Err: syscall.EIO}
+	}
+
+	return nil
+}
+
+func (f *FaultyFilesystem) Open(name string) (fs.File, error) {
+	if err := f.inject("open", name); err != nil {
+		return nil, err
+	}
+
+	file, err := f.fsys.Open(name)
+
+	if err != nil || f.config.ShortReads <= 0 {
+		return file, err
+	}
+
+	return shortReadFile{file, f.config.ShortReads}, nil
+}
+
+func (f *FaultyFilesystem) Stat(name string) (fs.FileInfo, error) {
+	if err := f.inject("stat", name); err != nil {
+		return nil, err
+	}
+
+	return f.fsys.Stat(name)
+}
+
+func (f *FaultyFilesystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	if err := f.inject("readdir", name); err != nil {
+		return nil, err
+	}
+
+	return f.fsys.ReadDir(name)
+}
+
+func (f *FaultyFilesystem) ReadFile(name string) ([]byte, error) {
+	if err := f.inject("read", name); err != nil {
+		return nil, err
+	}
+
+	return f.fsys.ReadFile(name)
+}
+
+func (f *FaultyFilesystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if err := f.inject("write", name); err != nil {
+		return err
+	}
+
+	if f.config.DiskSize > 0 {
+		f.mu.Lock()
+
+		if f.written+int64(len(data)) > f.config.DiskSize {
+			f.mu.Unlock()
+			return &fs.PathError{Op: "write", Path: name, Err: errNoSpace}
+		}
+
+		f.written += int64(len(data))
+		f.mu.Unlock()
+	}
+
+	return f.fsys.WriteFile(name, data, perm)
+}
+
+func (f *FaultyFilesystem) MkdirAll(name string, perm fs.FileMode) error {
+	if err := f.inject("mkdir", name); err != nil {
+		return err
+	}
+
+	return f.fsys.MkdirAll(name, perm)
+}
+
+func (f *FaultyFilesystem) Remove(name string) error {
+	if err := f.inject("remove", name); err != nil {
+		return err
+	}
+
+	return f.fsys.Remove(name)
+}
+
+func (f *FaultyFilesystem) RemoveAll(name string) error {
+	if err := f.inject("remove", name); err != nil {
+		return err
+	}
+
+	return f.fsys.RemoveAll(name)
+}
+
+func (f *FaultyFilesystem) Rename(oldname, newname string) error {
+	if err := f.inject("rename", oldname); err != nil {
+		return err
+	}
+
+	return f.fsys.Rename(oldname, newname)
+}
+
+// shortReadFile returns at most limit bytes from each Read.
+type shortReadFile struct {
+	fs.File
+	limit int
+}
+
+func (f shortReadFile) Read(data []byte) (int, error) {
+	return f.File.Read(data[:min(len(data), f.limit)])
+}
@@ -0,0 +1,60 @@
+package pathlib
+
+import (
+	"errors"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestFaultyFilesystem(t *testing.T) {
+	mem := NewMemFilesystem()
+	mem.WriteFile("data", []byte("0123456789"), 0644)
+
+	faulty := NewFaultyFilesystem(mem, FaultConfig{FailEvery: 3, FailOps: []string{"read"}})
+	var failures int
+
+	for i := 0; i < 9; i++ {
+		if _, err := PathOn(faulty, "data").ReadBytes(); errors.Is(err, syscall.EIO) {
+			failures++
+		}
+	}
+
+	// stats are not in FailOps, so they never fail
+	if failures != 3 || !PathOn(faulty, "data").Exists() {
+		t.Errorf("Expected 3 of 9 reads to fail, received %d", failures)
+	}
+
+	faulty = NewFaultyFilesystem(mem, FaultConfig{ShortReads: 3, DiskSize: 15, Latency: 10 * time.Millisecond})
+	f, err := faulty.Open("data")
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	buf := make([]byte, 10)
+
+	if n, _ := f.Read(buf); n != 3 {
+		t.Errorf("Expected a short read of 3 bytes, received %d", n)
+	}
+
+	if rest, _ := io.ReadAll(f); string(rest) != "3456789" {
+		t.Errorf("Expected the rest of the file, received %q", rest)
+	}
+
+	f.Close()
+	start := time.Now()
+
+	if err = PathOn(faulty, "a").WriteBytes([]byte("0123456789")); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if time.Since(start) < 10*time.Millisecond {
+		t.Errorf("Expected the write to be delayed")
+	}
+
+	if err = PathOn(faulty, "b").WriteBytes([]byte("0123456789")); !errors.Is(err, errNoSpace) {
+		t.Errorf("Expected ENOSPC, received %v", err)
+	}
+}
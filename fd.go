@@ -0,0 +1,63 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// FromFile returns the Path of the open file.  Where the platform can report the current location of a descriptor (Linux and Windows), that is used, so files renamed since they were opened are still found; otherwise the name the file was opened with is returned.  On Linux, files without a usable name (deleted files, memfds, pipes, and sockets) are returned as /proc/self/fd/N, which stays valid while the file is open.
+func FromFile(f *os.File) Path {
+	if name, ok := filePath(f); ok {
+		return Path(name)
+	}
+
+	return Path(f.Name())
+}
+
+// WithFd opens the Path with the mode (as Open does) and passes its raw descriptor to fn, for APIs that work with descriptors rather than *os.File.  The file is kept open until fn returns and then closed, so the descriptor must not be used afterwards.
+func (p Path) WithFd(mode string, fn func(fd uintptr) error) error {
+	f, err := p.Open(mode)
+
+	if err != nil {
+		return err
+	}
+
+	err = fn(f.Fd())
+	runtime.KeepAlive(f)
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// ReopenReadOnly opens the Path again for reading only, and checks that it is still the same file as f, which may have been opened for writing.  This lets code hand a read-only descriptor to less trusted consumers, and fails rather than returning a different file if the Path was replaced since f was opened.
+func (p Path) ReopenReadOnly(f *os.File) (*os.File, error) {
+	original, err := f.Stat()
+
+	if err != nil {
+		return nil, err
+	}
+
+	reopened, err := os.Open(string(p))
+
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := reopened.Stat()
+
+	if err != nil {
+		reopened.Close()
+		return nil, err
+	}
+
+	if !os.SameFile(original, current) {
+		reopened.Close()
+		return nil, fmt.Errorf("Cannot reopen %s because it is no longer the file that was open", p)
+	}
+
+	return reopened, nil
+}
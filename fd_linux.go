@@ -0,0 +1,30 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// filePath reads the descriptor's current path from /proc/self/fd.
+func filePath(f *os.File) (string, bool) {
+	fdPath := fmt.Sprintf("/proc/self/fd/%d", f.Fd())
+	target, err := os.Readlink(fdPath)
+
+	if err != nil {
+		return "", false
+	}
+
+	// deleted files, memfds, pipes, and sockets have no usable name
+	if !filepath.IsAbs(target) || !Path(target).lexists() {
+		return fdPath, true
+	}
+
+	if fdInfo, err := f.Stat(); err == nil {
+		if info, err := os.Stat(target); err != nil || !os.SameFile(fdInfo, info) {
+			return fdPath, true
+		}
+	}
+
+	return target, true
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !windows
+
+package pathlib
+
+import (
+	"os"
+)
+
+// filePath reports false, since the current path of a descriptor is not looked up on this platform.
+func filePath(f *os.File) (string, bool) {
+	return "", false
+}
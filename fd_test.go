@@ -0,0 +1,77 @@
+package pathlib
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestFromFile(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	f, err := p.Open("w")
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer f.Close()
+
+	if FromFile(f) != p {
+		t.Errorf("Expected %s, received %s", p, FromFile(f))
+	}
+
+	if runtime.GOOS != "linux" {
+		p.Unlink()
+		return
+	}
+
+	renamed := Path(string(p) + "-renamed")
+
+	if err = p.Rename(renamed); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if FromFile(f) != renamed {
+		t.Errorf("Expected the renamed %s, received %s", renamed, FromFile(f))
+	}
+
+	renamed.Unlink()
+
+	if deleted := FromFile(f); !strings.HasPrefix(string(deleted), "/proc/self/fd/") {
+		t.Errorf("Expected a /proc/self/fd Path for a deleted file, received %s", deleted)
+	}
+}
+
+func TestReopenReadOnly(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	f, err := p.Open("w")
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer f.Close()
+	defer p.Unlink()
+
+	f.Write([]byte("data"))
+	reopened, err := p.ReopenReadOnly(f)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if _, err = reopened.Write([]byte("x")); err == nil {
+		t.Errorf("Expected writing to the reopened file to fail")
+	}
+
+	reopened.Close()
+
+	// replace the file behind the Path
+	p.Unlink()
+	p.WriteBytes([]byte("imposter"))
+
+	if _, err = p.ReopenReadOnly(f); err == nil {
+		t.Errorf("Expected an error reopening a replaced file")
+	}
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package pathlib
+
+import (
+	"fmt"
+	"syscall"
+	"testing"
+)
+
+func TestWithFd(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer p.Unlink()
+
+	err := p.WithFd("w", func(fd uintptr) error {
+		_, err := syscall.Write(int(fd), []byte("through fd"))
+		return err
+	})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if contents, _ := p.ReadBytes(); string(contents) != "through fd" {
+		t.Errorf("Unexpected contents %q", contents)
+	}
+}
@@ -0,0 +1,37 @@
+package pathlib
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var procGetFinalPathNameByHandleW = kernel32.NewProc("GetFinalPathNameByHandleW")
+
+// filePath asks Windows for the descriptor's current path.
+func filePath(f *os.File) (string, bool) {
+	buf := make([]uint16, syscall.MAX_PATH)
+
+	for {
+		n, _, _ := procGetFinalPathNameByHandleW.Call(f.Fd(), uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)), 0)
+
+		if n == 0 {
+			return "", false
+		}
+
+		if int(n) > len(buf) {
+			buf = make([]uint16, n)
+			continue
+		}
+
+		name := syscall.UTF16ToString(buf[:n])
+
+		// prefer C:\dir\file over \\?\C:\dir\file, keeping UNC paths valid
+		if strings.HasPrefix(name, `\\?\UNC\`) {
+			return `\\` + name[len(`\\?\UNC\`):], true
+		}
+
+		return strings.TrimPrefix(name, `\\?\`), true
+	}
+}
@@ -0,0 +1,69 @@
+//go:build darwin || freebsd || netbsd || openbsd || dragonfly
+
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+const (
+	userImmutableFlag   = 0x2     // UF_IMMUTABLE
+	userAppendFlag      = 0x4     // UF_APPEND
+	systemImmutableFlag = 0x20000 // SF_IMMUTABLE
+	systemAppendFlag    = 0x40000 // SF_APPEND
+)
+
+// IsImmutable returns true if the Path has the user or system immutable flag (chflags uchg or schg).
+func (p Path) IsImmutable() (bool, error) {
+	flags, err := p.fileFlags()
+	return flags&(userImmutableFlag|systemImmutableFlag) != 0, err
+}
+
+// SetImmutable sets or clears the immutable flag of the Path.  When run as root, the system flag (schg) is set, which even root cannot clear while the securelevel is raised; otherwise the user flag (uchg) is set.  Clearing removes both.
+func (p Path) SetImmutable(immutable bool) error {
+	return p.setFileFlag(userImmutableFlag, systemImmutableFlag, immutable)
+}
+
+// IsAppendOnly returns true if the Path has the user or system append-only flag (chflags uappnd or sappnd).
+func (p Path) IsAppendOnly() (bool, error) {
+	flags, err := p.fileFlags()
+	return flags&(userAppendFlag|systemAppendFlag) != 0, err
+}
+
+// SetAppendOnly sets or clears the append-only flag of the Path, choosing between the system (sappnd) and user (uappnd) flags as SetImmutable does.
+func (p Path) SetAppendOnly(appendOnly bool) error {
+	return p.setFileFlag(userAppendFlag, systemAppendFlag, appendOnly)
+}
+
+func (p Path) fileFlags() (uint32, error) {
+	var stat syscall.Stat_t
+
+	if err := syscall.Lstat(string(p), &stat); err != nil {
+		return 0, &os.PathError{Op: "lstat", Path: string(p), Err: err}
+	}
+
+	return uint32(stat.Flags), nil
+}
+
+func (p Path) setFileFlag(userFlag, systemFlag uint32, set bool) error {
+	flags, err := p.fileFlags()
+
+	if err != nil {
+		return err
+	}
+
+	if !set {
+		flags &^= userFlag | systemFlag
+	} else if os.Geteuid() == 0 {
+		flags |= systemFlag
+	} else {
+		flags |= userFlag
+	}
+
+	if err = syscall.Chflags(string(p), int(flags)); err != nil {
+		return &os.PathError{Op: "chflags", Path: string(p), Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package pathlib
+
+import (
+	"fmt"
+)
+
+// IsImmutable is not supported on this platform.
+func (p Path) IsImmutable() (bool, error) {
+	return false, fmt.Errorf("Immutable files are not supported on this platform: %s", p)
+}
+
+// SetImmutable is not supported on this platform.
+func (p Path) SetImmutable(immutable bool) error {
+	return fmt.Errorf("Immutable files are not supported on this platform: %s", p)
+}
+
+// IsAppendOnly is not supported on this platform.
+func (p Path) IsAppendOnly() (bool, error) {
+	return false, fmt.Errorf("Append-only files are not supported on this platform: %s", p)
+}
+
+// SetAppendOnly is not supported on this platform.
+func (p Path) SetAppendOnly(appendOnly bool) error {
+	return fmt.Errorf("Append-only files are not supported on this platform: %s", p)
+}
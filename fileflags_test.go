@@ -0,0 +1,80 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestImmutable(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := p.WriteBytes([]byte("data")); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	if err := p.SetImmutable(true); err != nil {
+		t.Skip("Cannot set the immutable attribute here: " + err.Error())
+	}
+
+	defer p.SetImmutable(false)
+
+	immutable, err := p.IsImmutable()
+
+	if err != nil || !immutable {
+		t.Errorf("Expected an immutable file, received %v %v", immutable, err)
+	}
+
+	if err = p.WriteBytes([]byte("changed")); err == nil {
+		t.Errorf("Expected writing an immutable file to fail")
+	}
+
+	if err = p.SetImmutable(false); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if immutable, _ = p.IsImmutable(); immutable {
+		t.Errorf("Immutable attribute was not cleared")
+	}
+}
+
+func TestAppendOnly(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := p.WriteBytes([]byte("data")); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	if err := p.SetAppendOnly(true); err != nil {
+		t.Skip("Cannot set the append-only attribute here: " + err.Error())
+	}
+
+	defer p.SetAppendOnly(false)
+
+	appendOnly, err := p.IsAppendOnly()
+
+	if err != nil || !appendOnly {
+		t.Errorf("Expected an append-only file, received %v %v", appendOnly, err)
+	}
+
+	if err = p.WriteBytes([]byte("truncated")); err == nil {
+		t.Errorf("Expected truncating an append-only file to fail")
+	}
+
+	f, err := os.OpenFile(string(p), os.O_WRONLY|os.O_APPEND, 0)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	_, err = f.Write([]byte(" more"))
+	f.Close()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+}
@@ -0,0 +1,181 @@
+package pathlib
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Filesystem is a backend that Paths can operate on through PathOn.  It extends io/fs.FS with the operations needed for writing, so the same Path API can target the OS, an in-memory filesystem in tests, read-only assets such as embed.FS or a zip.Reader, or remote stores.  Names use forward slashes, as with io/fs.
+type Filesystem interface {
+	fs.FS
+	Stat(name string) (fs.FileInfo, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	MkdirAll(name string, perm fs.FileMode) error
+	Remove(name string) error
+	RemoveAll(name string) error
+	Rename(oldname, newname string) error
+}
+
+// Symlinker is implemented by Filesystems that support symbolic links.  Object stores generally do not, so operations such as FSPath.CopyTree check for it and degrade gracefully.
+type Symlinker interface {
+	Symlink(oldname, newname string) error
+	Readlink(name string) (string, error)
+	Lstat(name string) (fs.FileInfo, error)
+}
+
+// Chmoder is implemented by Filesystems that can change permissions.
+type Chmoder interface {
+	Chmod(name string, mode fs.FileMode) error
+}
+
+// Watcher is implemented by Filesystems that can report changes.  Watch sends the name of each file or directory created, written, renamed, or removed at or beneath name, until ctx is done.
+type Watcher interface {
+	Watch(ctx context.Context, name string) (<-chan string, error)
+}
+
+// chtimeser is implemented by Filesystems that can set modification times.
+type chtimeser interface {
+	Chtimes(name string, atime, mtime time.Time) error
+}
+
+// OS is the Filesystem backed by the operating system, which plain Paths use.  Unlike io/fs, it accepts absolute and relative OS paths.
+var OS Filesystem = osFilesystem{}
+
+type osFilesystem struct{}
+
+func (osFilesystem) osNames() {}
+
+func (osFilesystem) Open(name string) (fs.File, error) {
+	return os.Open(filepath.FromSlash(name))
+}
+
+func (osFilesystem) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(filepath.FromSlash(name))
+}
+
+func (osFilesystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(filepath.FromSlash(name))
+}
+
+func (osFilesystem) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(filepath.FromSlash(name))
+}
+
+func (osFilesystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(filepath.FromSlash(name), data, perm)
+}
+
+func (osFilesystem) MkdirAll(name string, perm fs.FileMode) error {
+	return os.MkdirAll(filepath.FromSlash(name), perm)
+}
+
+func (osFilesystem) Remove(name string) error {
+	return os.Remove(filepath.FromSlash(name))
+}
+
+func (osFilesystem) RemoveAll(name string) error {
+	return os.RemoveAll(filepath.FromSlash(name))
+}
+
+func (osFilesystem) Rename(oldname, newname string) error {
+	return os.Rename(filepath.FromSlash(oldname), filepath.FromSlash(newname))
+}
+
+func (osFilesystem) Glob(pattern string) ([]string, error) {
+	return filepath.Glob(filepath.FromSlash(pattern))
+}
+
+func (osFilesystem) Symlink(oldname, newname string) error {
+	return os.Symlink(filepath.FromSlash(oldname), filepath.FromSlash(newname))
+}
+
+func (osFilesystem) Readlink(name string) (string, error) {
+	target, err := os.Readlink(filepath.FromSlash(name))
+	return filepath.ToSlash(target), err
+}
+
+func (osFilesystem) Lstat(name string) (fs.FileInfo, error) {
+	return os.Lstat(filepath.FromSlash(name))
+}
+
+func (osFilesystem) Chmod(name string, mode fs.FileMode) error {
+	return os.Chmod(filepath.FromSlash(name), mode)
+}
+
+func (osFilesystem) Chtimes(name string, atime, mtime time.Time) error {
+	return os.Chtimes(filepath.FromSlash(name), atime, mtime)
+}
+
+// ErrReadOnly is returned when writing to a read-only Filesystem.
+var ErrReadOnly = errors.New("read-only filesystem")
+
+// FromFS adapts any io/fs.FS, such as embed.FS, a zip.Reader, or fstest.MapFS, into a read-only Filesystem.  If fsys already implements Filesystem, it is returned as-is.
+func FromFS(fsys fs.FS) Filesystem {
+	if filesystem, ok := fsys.(Filesystem); ok {
+		return filesystem
+	}
+
+	return readOnlyFS{fsys}
+}
+
+type readOnlyFS struct {
+	fsys fs.FS
+}
+
+// ReadOnly returns the Path bound to a read-only view of the OS Filesystem.  Reads work as usual, but every mutating method (WriteBytes, Touch, Mkdir, Unlink, Rmdir, Rename, and so on) fails with an error wrapping ErrReadOnly, which protects real data during dry runs and analyses.
+func ReadOnly(p Path) FSPath {
+	return PathOn(readOnlyOS{readOnlyFS{OS}}, string(p))
+}
+
+// readOnlyOS is a read-only OS Filesystem, which keeps taking native OS paths.
+type readOnlyOS struct {
+	readOnlyFS
+}
+
+func (readOnlyOS) osNames() {}
+
+func (r readOnlyFS) Open(name string) (fs.File, error) {
+	return r.fsys.Open(name)
+}
+
+func (r readOnlyFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(r.fsys, name)
+}
+
+func (r readOnlyFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(r.fsys, name)
+}
+
+func (r readOnlyFS) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(r.fsys, name)
+}
+
+func (r readOnlyFS) Glob(pattern string) ([]string, error) {
+	return fs.Glob(r.fsys, pattern)
+}
+
+func (readOnlyFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return &fs.PathError{Op: "write", Path: name, Err: ErrReadOnly}
+}
+
+func (readOnlyFS) MkdirAll(name string, perm fs.FileMode) error {
+	return &fs.PathError{Op: "mkdir", Path: name, Err: ErrReadOnly}
+}
+
+func (readOnlyFS) Remove(name string) error {
+	return &fs.PathError{Op: "remove", Path: name, Err: ErrReadOnly}
+}
+
+func (readOnlyFS) RemoveAll(name string) error {
+	return &fs.PathError{Op: "remove", Path: name, Err: ErrReadOnly}
+}
+
+func (readOnlyFS) Rename(oldname, newname string) error {
+	return &fs.PathError{Op: "rename", Path: oldname, Err: ErrReadOnly}
+}
@@ -0,0 +1,89 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FindUp searches for name in the directory Path (or the directory containing it, if the Path is a file) and then in each of its ancestors, returning the nearest match, as tools do to find their configuration or project root.  name may have several components (eg. ".git/config").  If there is no match, an error wrapping os.ErrNotExist is returned.
+func (p Path) FindUp(name string) (Path, error) {
+	var found Path
+
+	err := p.searchUp([]string{name}, func(match Path) bool {
+		found = match
+		return false
+	})
+
+	if err == nil && len(found) == 0 {
+		err = fmt.Errorf("Cannot find %s in %s or its parents: %w", name, p, os.ErrNotExist)
+	}
+
+	return found, err
+}
+
+// CollectUp searches for name like FindUp, but returns every match from the Path up to the root, nearest first, so that settings can be merged with nearer files taking precedence.  No matches is not an error.
+func (p Path) CollectUp(name string) ([]Path, error) {
+	var found []Path
+
+	err := p.searchUp([]string{name}, func(match Path) bool {
+		found = append(found, match)
+		return true
+	})
+
+	return found, err
+}
+
+// searchUp calls fn with each of the names that exists, checking the names in order in each directory from the Path upwards, and stopping early if fn returns false.
+func (p Path) searchUp(names []string, fn func(Path) bool) error {
+	abs, err := filepath.Abs(string(p))
+
+	if err != nil {
+		return err
+	}
+
+	dir := Path(abs)
+
+	if info, err := os.Stat(abs); err == nil && !info.IsDir() {
+		dir = dir.Parent()
+	}
+
+	for {
+		for _, name := range names {
+			if match := dir.JoinPath(Path(name)); match.Exists() && !fn(match) {
+				return nil
+			}
+		}
+
+		parent := dir.Parent()
+
+		if parent == dir {
+			return nil
+		}
+
+		dir = parent
+	}
+}
+
+// DefaultProjectMarkers are the names ProjectRoot looks for when none are given.
+var DefaultProjectMarkers = []string{".git", ".hg", ".svn", "go.mod", "package.json", "Cargo.toml", "pyproject.toml", "pom.xml", "build.gradle", "Makefile"}
+
+// ProjectRoot returns the nearest directory, starting from the Path and moving up, that contains any of the markers (or DefaultProjectMarkers if none are given), so tools agree on where a project begins.  If no directory does, an error wrapping os.ErrNotExist is returned.
+func (p Path) ProjectRoot(markers ...string) (Path, error) {
+	if len(markers) == 0 {
+		markers = DefaultProjectMarkers
+	}
+
+	var root Path
+
+	err := p.searchUp(markers, func(match Path) bool {
+		root = match.Parent()
+		return false
+	})
+
+	if err == nil && len(root) == 0 {
+		err = fmt.Errorf("Cannot find a project root for %s: %w", p, os.ErrNotExist)
+	}
+
+	return root, err
+}
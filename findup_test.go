@@ -0,0 +1,89 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestFindUp(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	err := CreateTree(dir, TreeSpec{
+		{Path: ".editorconfig", Content: "root = true"},
+		{Path: "a/.editorconfig", Content: "[*.go]"},
+		{Path: "a/b/c/main.go", Content: "package main"},
+		{Path: "a/b/.git/config", Content: ""},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	start := dir.JoinPath("a", "b", "c", "main.go")
+	found, err := start.FindUp(".editorconfig")
+
+	if err != nil || found != dir.JoinPath("a", ".editorconfig") {
+		t.Errorf("Expected the nearest .editorconfig but found %s (%v)", found, err)
+	}
+
+	if found, err = start.FindUp(".git/config"); err != nil || found != dir.JoinPath("a", "b", ".git", "config") {
+		t.Errorf("Expected .git/config but found %s (%v)", found, err)
+	}
+
+	if _, err = start.FindUp("no-such-file-" + randomString(10)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected an error wrapping os.ErrNotExist but got %v", err)
+	}
+
+	all, err := dir.JoinPath("a", "b", "c").CollectUp(".editorconfig")
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if len(all) < 2 || all[0] != dir.JoinPath("a", ".editorconfig") || all[1] != dir.JoinPath(".editorconfig") {
+		t.Errorf("Expected the .editorconfig files nearest first but found %v", all)
+	}
+}
+
+func TestProjectRoot(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	err := CreateTree(dir, TreeSpec{
+		{Path: "repo/.git", Dir: true},
+		{Path: "repo/tools/go.mod", Content: "module tools"},
+		{Path: "repo/tools/cmd/main.go", Content: "package main"},
+		{Path: "repo/web/src/app.js", Content: ""},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := map[string]struct {
+		start    Path
+		markers  []string
+		expected Path
+	}{
+		"nearest":    {dir.JoinPath("repo", "tools", "cmd", "main.go"), nil, dir.JoinPath("repo", "tools")},
+		"git only":   {dir.JoinPath("repo", "tools", "cmd"), []string{".git"}, dir.JoinPath("repo")},
+		"no module":  {dir.JoinPath("repo", "web", "src"), nil, dir.JoinPath("repo")},
+		"at a root":  {dir.JoinPath("repo"), []string{".git"}, dir.JoinPath("repo")},
+		"any marker": {dir.JoinPath("repo", "web"), []string{"package.json", ".git"}, dir.JoinPath("repo")},
+	}
+
+	for name, test := range tests {
+		root, err := test.start.ProjectRoot(test.markers...)
+
+		if err != nil || root != test.expected {
+			t.Errorf("%s: expected %s but found %s (%v)", name, test.expected, root, err)
+		}
+	}
+
+	if _, err = dir.ProjectRoot("no-such-marker-" + randomString(10)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected an error wrapping os.ErrNotExist but got %v", err)
+	}
+}
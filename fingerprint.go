@@ -0,0 +1,72 @@
+package pathlib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ErrModified is returned by WriteBytesIfUnchanged when the file no longer matches the expected Fingerprint.
+var ErrModified = errors.New("file was modified")
+
+// Fingerprint records the state of a file so later changes to it can be detected.
+type Fingerprint struct {
+	Size    int64
+	ModTime time.Time
+	SHA256  string
+}
+
+// Equal returns true if both Fingerprints describe the same file state.
+func (f Fingerprint) Equal(other Fingerprint) bool {
+	return f.Size == other.Size && f.ModTime.Equal(other.ModTime) && f.SHA256 == other.SHA256
+}
+
+// Fingerprint returns the size, modification time, and SHA-256 hash of the file Path.
+func (p Path) Fingerprint() (Fingerprint, error) {
+	f, err := os.Open(string(p))
+
+	if err != nil {
+		return Fingerprint{}, err
+	}
+
+	defer f.Close()
+
+	stat, err := f.Stat()
+
+	if err != nil {
+		return Fingerprint{}, err
+	}
+
+	if stat.IsDir() {
+		return Fingerprint{}, fmt.Errorf("Cannot fingerprint %s because it is a directory.", p)
+	}
+
+	h := sha256.New()
+
+	if _, err = io.Copy(h, f); err != nil {
+		return Fingerprint{}, err
+	}
+
+	return Fingerprint{Size: stat.Size(), ModTime: stat.ModTime(), SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// WriteBytesIfUnchanged atomically replaces the contents of the file Path, but only if it still matches the expected Fingerprint taken when it was read; otherwise it returns an error wrapping ErrModified and leaves the file alone.  A zero Fingerprint expects the file not to exist yet.  This guards against lost updates between cooperating editors, though a write landing between the check and the replacement can still be overwritten.
+func (p Path) WriteBytesIfUnchanged(data []byte, expected Fingerprint) error {
+	current, err := p.Fingerprint()
+
+	if os.IsNotExist(err) {
+		if expected != (Fingerprint{}) {
+			return fmt.Errorf("Cannot write %s because it was removed: %w", p, ErrModified)
+		}
+	} else if err != nil {
+		return err
+	} else if !current.Equal(expected) {
+		return fmt.Errorf("Cannot write %s because it changed since it was read: %w", p, ErrModified)
+	}
+
+	return p.writeBytesAtomic(data, 0644)
+}
@@ -0,0 +1,50 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWriteBytesIfUnchanged(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := p.WriteBytesIfUnchanged([]byte("first"), Fingerprint{}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	if err := p.WriteBytesIfUnchanged([]byte("again"), Fingerprint{}); !errors.Is(err, ErrModified) {
+		t.Errorf("Expected ErrModified for an existing file, received %v", err)
+	}
+
+	fingerprint, err := p.Fingerprint()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if fingerprint.Size != 5 || len(fingerprint.SHA256) != 64 {
+		t.Errorf("Unexpected fingerprint %+v", fingerprint)
+	}
+
+	if err = p.WriteBytesIfUnchanged([]byte("second"), fingerprint); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	// the fingerprint is now stale
+	if err = p.WriteBytesIfUnchanged([]byte("third"), fingerprint); !errors.Is(err, ErrModified) {
+		t.Errorf("Expected ErrModified for a stale fingerprint, received %v", err)
+	}
+
+	contents, err := p.ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if string(contents) != "second" {
+		t.Errorf("Expected second, received %q", contents)
+	}
+}
@@ -0,0 +1,262 @@
+package pathlib
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// CopyTree copies the directory FSPath and everything within it to dst, which may be on a different Filesystem.  The CopyOptions work as for Path.CopyTree, except that features dst's Filesystem lacks (symbolic links without Symlinker, PreserveMode without Chmoder, and so on) are skipped rather than failing the copy, and recorded in the report given to ReportUnapplied.  Failing to copy an entry does not stop the rest of the tree being copied; the failures are returned together as a MultiError keyed by the names being copied from.  Symbolic links that cannot be recreated are copied as the files they point to.  Ownership and extended attributes are not available through Filesystems, so they are always skipped.
+func (p FSPath) CopyTree(dst FSPath, opts ...CopyOption) error {
+	return p.copyTree(dst, newCopyOptions(opts), false)
+}
+
+// SyncTo makes dst a copy of the directory FSPath by copying only the files that are missing from dst or that differ in size or are newer than the copy in dst.  Nothing is removed from dst.  As with CopyTree, features dst's Filesystem lacks are skipped and reported rather than failing the sync, and the entries that fail to copy are returned together as a MultiError.
+func (p FSPath) SyncTo(dst FSPath, opts ...CopyOption) error {
+	return p.copyTree(dst, newCopyOptions(append(opts, Overwrite())), true)
+}
+
+// Watch reports changes at or beneath the FSPath, if its Filesystem implements Watcher.  Otherwise an error wrapping errors.ErrUnsupported is returned.
+func (p FSPath) Watch(ctx context.Context) (<-chan string, error) {
+	watcher, ok := p.fsys.(Watcher)
+
+	if !ok {
+		return nil, fmt.Errorf("Cannot watch %s: %w", p, errors.ErrUnsupported)
+	}
+
+	return watcher.Watch(ctx, p.name)
+}
+
+func (p FSPath) copyTree(dst FSPath, o copyOptions, sync bool) error {
+	defer o.timed(time.Now())
+	root, err := p.stat()
+
+	if err != nil {
+		return err
+	}
+
+	if !root.IsDir() {
+		return fmt.Errorf("CopyTree only works on directories: %s", p)
+	}
+
+	if dst.within(p) {
+		return fmt.Errorf("Cannot copy %s to %s because it is within the directory being copied", p, dst)
+	}
+
+	if _, err = dst.stat(); err == nil && !o.overwrite {
+		return fmt.Errorf("Cannot copy %s to %s: %w", p, dst, os.ErrExist)
+	}
+
+	var dirs []FSPath
+	var dirInfos []fs.FileInfo
+	errs := MultiError{}
+
+	err = fs.WalkDir(p.fsys, p.name, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			errs.add(Path(name), err)
+			return nil
+		}
+
+		if o.exclude != nil && name != p.name && o.exclude(Path(name)) {
+			o.count(func(s *CopyStats) { s.Excluded++ })
+
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		rel := strings.TrimPrefix(name, strings.TrimSuffix(p.name, "/")+"/")
+
+		if name == p.name {
+			rel = "."
+		} else if p.name == "." {
+			rel = name
+		}
+
+		src, target := p.with(name), dst.with(path.Join(dst.name, rel))
+
+		if entry.IsDir() {
+			info, err := entry.Info()
+
+			if err == nil {
+				err = target.do(func() error { return target.fsys.MkdirAll(target.name, info.Mode().Perm()|0700) })
+			}
+
+			if err != nil {
+				if name == p.name {
+					return err
+				}
+
+				errs.add(Path(name), err)
+				return fs.SkipDir
+			}
+
+			o.count(func(s *CopyStats) { s.Dirs++ })
+			dirs, dirInfos = append(dirs, target), append(dirInfos, info)
+			return nil
+		}
+
+		if entry.Type()&fs.ModeSymlink != 0 {
+			err = o.copyFSSymlink(src, target, sync)
+		} else {
+			err = o.copyFSFile(src, target, sync)
+		}
+
+		if err != nil {
+			errs.add(Path(name), err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// directories last, since copying into them changes their times
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err = o.applyFSMetadata(dirs[i], dirInfos[i]); err != nil {
+			errs.add(Path(dirs[i].name), err)
+		}
+	}
+
+	if o.verifyManifests {
+		if err = verifyDirManifests(dst.fsys, dst.name, errs); err != nil {
+			return err
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// copyFSSymlink recreates a symbolic link, or copies what it points to if either Filesystem lacks symbolic links.
+func (o copyOptions) copyFSSymlink(src, dst FSPath, sync bool) error {
+	srcLinker, srcOK := src.fsys.(Symlinker)
+	dstLinker, dstOK := dst.fsys.(Symlinker)
+
+	if !srcOK || !dstOK || o.dereference {
+		if !dstOK && !o.dereference {
+			o.skipped(dst, "symlink", errors.ErrUnsupported)
+		}
+
+		return o.copyFSFile(src, dst, sync)
+	}
+
+	link, err := srcLinker.Readlink(src.name)
+
+	if err != nil {
+		return err
+	}
+
+	if existing, err := dstLinker.Readlink(dst.name); err == nil && existing == link {
+		o.count(func(s *CopyStats) { s.Unchanged++ })
+		return nil
+	}
+
+	if _, err = dstLinker.Lstat(dst.name); err == nil {
+		if !o.overwrite {
+			return fmt.Errorf("Cannot copy %s to %s: %w", src, dst, os.ErrExist)
+		}
+
+		if err = dst.fsys.Remove(dst.name); err != nil {
+			return err
+		}
+	}
+
+	if err = dstLinker.Symlink(link, dst.name); err != nil {
+		return err
+	}
+
+	o.count(func(s *CopyStats) { s.Symlinks++ })
+	return nil
+}
+
+func (o copyOptions) copyFSFile(src, dst FSPath, sync bool) error {
+	info, err := src.stat()
+
+	if err != nil {
+		return err
+	}
+
+	// links to directories cannot be copied as files
+	if info.IsDir() {
+		return o.skipped(dst, "symlink", fmt.Errorf("%s links to a directory", src))
+	}
+
+	if existing, err := dst.stat(); err == nil {
+		if sync && existing.Size() == info.Size() && !existing.ModTime().Before(info.ModTime()) {
+			o.count(func(s *CopyStats) { s.Unchanged++ })
+			return nil
+		}
+
+		if !o.overwrite {
+			return fmt.Errorf("Cannot copy %s to %s: %w", src, dst, os.ErrExist)
+		}
+	}
+
+	data, err := src.readFile()
+
+	if err != nil {
+		return err
+	}
+
+	if o.inspect != nil {
+		if err = o.inspect(Path(src.name), bytes.NewReader(data)); err != nil {
+			return o.rejected(Path(src.name), err)
+		}
+	}
+
+	if err = dst.writeFile(data, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	o.count(func(s *CopyStats) { s.Files++; s.Bytes += int64(len(data)) })
+
+	return o.applyFSMetadata(dst, info)
+}
+
+// applyFSMetadata applies the requested metadata that dst's Filesystem supports, and reports the rest.
+func (o copyOptions) applyFSMetadata(dst FSPath, info fs.FileInfo) error {
+	if o.preserveMode {
+		if chmoder, ok := dst.fsys.(Chmoder); !ok {
+			o.skipped(dst, "mode", errors.ErrUnsupported)
+		} else if err := chmoder.Chmod(dst.name, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	if o.preserveTimes {
+		if chtimeser, ok := dst.fsys.(chtimeser); !ok {
+			o.skipped(dst, "times", errors.ErrUnsupported)
+		} else if err := chtimeser.Chtimes(dst.name, info.ModTime(), info.ModTime()); err != nil {
+			return err
+		}
+	}
+
+	if o.preserveOwner {
+		o.skipped(dst, "owner", errors.ErrUnsupported)
+	}
+
+	if o.preserveXattrs {
+		o.skipped(dst, "xattrs", errors.ErrUnsupported)
+	}
+
+	return nil
+}
+
+// skipped records a feature dst's Filesystem lacks, if a report was requested.  Unlike unapplied, it never fails the copy.
+func (o copyOptions) skipped(dst FSPath, attribute string, err error) error {
+	if o.report != nil {
+		o.report.Unapplied = append(o.report.Unapplied, UnappliedAttribute{Path: Path(dst.name), Attribute: attribute, Err: err})
+	}
+
+	return nil
+}
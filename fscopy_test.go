@@ -0,0 +1,146 @@
+package pathlib
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestFSPathCopyTree(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer root.RmdirRecursive()
+
+	err := CreateTree(root, TreeSpec{
+		{Path: "src/a.txt", Content: "a", Perms: 0600},
+		{Path: "src/sub/b.txt", Content: "b"},
+		{Path: "src/link", Symlink: "a.txt"},
+		{Path: "src/skip/c.txt", Content: "c"},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// copying to memory, which has no symbolic links, degrades and reports
+	mem := NewMemFilesystem()
+	var report CopyReport
+	err = PathOn(OS, string(root.JoinPath("src"))).CopyTree(PathOn(mem, "dst"), PreserveMode(), ReportUnapplied(&report), Exclude(func(p Path) bool { return p.Name() == "skip" }))
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if data, _ := mem.ReadFile("dst/link"); string(data) != "a" {
+		t.Errorf("Expected the link to be copied as its target, received %q", data)
+	}
+
+	if info, _ := mem.Stat("dst/a.txt"); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the mode to be preserved, received %s", info.Mode())
+	}
+
+	if _, err = mem.Stat("dst/skip"); err == nil {
+		t.Errorf("Excluded directory was copied")
+	}
+
+	if len(report.Unapplied) != 1 || report.Unapplied[0].Attribute != "symlink" || report.Unapplied[0].Path != "dst/link" {
+		t.Errorf("Expected the symlink to be reported, received %+v", report.Unapplied)
+	}
+
+	// copying back to the OS needs no degradation
+	back := root.JoinPath("back")
+	report = CopyReport{}
+	err = PathOn(mem, "dst").CopyTree(PathOn(OS, string(back)), PreserveMode(), PreserveTimes(), ReportUnapplied(&report))
+
+	if err != nil || len(report.Unapplied) != 0 {
+		t.Errorf("Unexpected error %v or report %+v", err, report.Unapplied)
+	}
+
+	if data, _ := back.JoinPath("sub", "b.txt").ReadBytes(); string(data) != "b" {
+		t.Errorf("Expected b, received %q", data)
+	}
+
+	if err = PathOn(mem, "dst").CopyTree(PathOn(OS, string(back))); err == nil {
+		t.Errorf("Expected an error copying onto an existing directory")
+	}
+
+	// copying a tree into itself must fail before creating anything, rather than recursing
+	for _, src := range []FSPath{PathOn(mem, "dst"), PathOn(OS, string(back))} {
+		inside := src.JoinPath("sub", "copy")
+
+		if err = src.CopyTree(inside, Overwrite()); err == nil || inside.Exists() {
+			t.Errorf("Expected an error copying %s into itself, received %v", src, err)
+		}
+	}
+}
+
+func TestFSPathSyncTo(t *testing.T) {
+	src, dst := NewMemFilesystem(), NewMemFilesystem()
+	src.MkdirAll("sub", 0755)
+	src.WriteFile("sub/same.txt", []byte("same"), 0644)
+	src.WriteFile("new.txt", []byte("new"), 0644)
+	src.WriteFile("changed.txt", []byte("changed"), 0644)
+	dst.MkdirAll("sub", 0755)
+	dst.WriteFile("sub/same.txt", []byte("SAME"), 0644)
+	dst.WriteFile("changed.txt", []byte("old"), 0644)
+	dst.WriteFile("extra.txt", []byte("extra"), 0644)
+
+	// the destination's copy of same.txt is newer and the same size, so it is left alone
+	src.Chtimes("sub/same.txt", time.Time{}, time.Now().Add(-time.Hour))
+
+	var stats CopyStats
+
+	if err := PathOn(src, ".").SyncTo(PathOn(dst, "."), ReportStats(&stats)); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if stats.Files != 2 || stats.Bytes != int64(len("new")+len("changed")) || stats.Unchanged != 1 || stats.Dirs != 2 {
+		t.Errorf("Expected 2 files copied and 1 unchanged, received %+v", stats)
+	}
+
+	tests := map[string]string{
+		"sub/same.txt": "SAME",
+		"new.txt":      "new",
+		"changed.txt":  "changed",
+		"extra.txt":    "extra",
+	}
+
+	for name, target := range tests {
+		if data, _ := dst.ReadFile(name); string(data) != target {
+			t.Errorf("%s: expected %q, received %q", name, target, data)
+		}
+	}
+}
+
+func TestFSPathWatch(t *testing.T) {
+	mem := NewMemFilesystem()
+	mem.MkdirAll("watched", 0755)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := PathOn(mem, "watched").Watch(ctx)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	mem.WriteFile("elsewhere.txt", nil, 0644)
+	mem.WriteFile("watched/file.txt", nil, 0644)
+
+	if name := <-events; name != "watched/file.txt" {
+		t.Errorf("Expected an event for watched/file.txt, received %s", name)
+	}
+
+	cancel()
+
+	for range events {
+	}
+
+	if _, err = PathOn(FromFS(fs.FS(mem)), ".").Watch(ctx); err != nil {
+		t.Errorf("Expected a Filesystem to keep its Watcher through FromFS: %v", err)
+	}
+
+	if _, err = PathOn(OS, "/tmp").Watch(context.Background()); err == nil {
+		t.Errorf("Expected an error watching a Filesystem without Watcher")
+	}
+}
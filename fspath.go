@@ -0,0 +1,325 @@
+package pathlib
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FSPath is a Path bound to a Filesystem backend, with the same methods as Path routed through the backend instead of the os package.  Create one with PathOn.
+type FSPath struct {
+	fsys Filesystem
+	name string
+}
+
+// PathOn returns the Path name on the filesystem.  Any io/fs.FS may be used; ones that do not implement Filesystem are treated as read-only (see FromFS).  For backends other than OS (and those built on it), the name is cleaned into the unrooted io/fs form, so "/a/b/" refers to "a/b".  PathOn(OS, name) behaves like Path(name).
+func PathOn(fsys fs.FS, name string) FSPath {
+	filesystem := FromFS(fsys)
+	name = filepath.ToSlash(name)
+
+	if _, native := filesystem.(osNamer); !native {
+		name = fsName(name)
+	}
+
+	return FSPath{fsys: filesystem, name: name}
+}
+
+// osNamer is implemented by backends that take native OS paths rather than io/fs names.
+type osNamer interface {
+	osNames()
+}
+
+// fsName cleans a slash-separated name into the unrooted form required by io/fs.
+func fsName(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+
+	if name == "" {
+		return "."
+	}
+
+	return name
+}
+
+// Filesystem returns the backend the FSPath is bound to.
+func (p FSPath) Filesystem() Filesystem {
+	return p.fsys
+}
+
+// String returns the name of the FSPath within its Filesystem.
+func (p FSPath) String() string {
+	return p.name
+}
+
+// Path returns the name of the FSPath as a plain Path, which is only meaningful for the OS Filesystem.
+func (p FSPath) Path() Path {
+	return Path(filepath.FromSlash(p.name))
+}
+
+func (p FSPath) with(name string) FSPath {
+	return FSPath{fsys: p.fsys, name: name}
+}
+
+// stat, do, readFile, and writeFile perform operations on the backend, counting them in Stats.
+func (p FSPath) stat() (fs.FileInfo, error) {
+	start := time.Now()
+	info, err := p.fsys.Stat(p.name)
+	record(p.fsys, start, 0, 0, err)
+	return info, err
+}
+
+func (p FSPath) do(op func() error) error {
+	start := time.Now()
+	err := op()
+	record(p.fsys, start, 0, 0, err)
+	return err
+}
+
+func (p FSPath) readFile() ([]byte, error) {
+	start := time.Now()
+	data, err := p.fsys.ReadFile(p.name)
+	record(p.fsys, start, len(data), 0, err)
+	return data, err
+}
+
+func (p FSPath) writeFile(data []byte, perm fs.FileMode) error {
+	start := time.Now()
+	err := p.fsys.WriteFile(p.name, data, perm)
+	written := len(data)
+
+	if err != nil {
+		written = 0
+	}
+
+	record(p.fsys, start, 0, written, err)
+	return err
+}
+
+// Exists returns true if the FSPath exists.
+func (p FSPath) Exists() bool {
+	_, err := p.stat()
+	return err == nil
+}
+
+// IsDir returns true if the FSPath is a directory. Note that false is returned if the FSPath does not exist.
+func (p FSPath) IsDir() bool {
+	stat, err := p.stat()
+	return err == nil && stat.IsDir()
+}
+
+// IsFile returns true if the FSPath is a file. Note that false is returned if the FSPath does not exist.
+func (p FSPath) IsFile() bool {
+	stat, err := p.stat()
+	return err == nil && stat.Mode().IsRegular()
+}
+
+// Permissions returns the FSPath's permissions.
+func (p FSPath) Permissions() (fs.FileMode, error) {
+	stat, err := p.stat()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return stat.Mode().Perm(), nil
+}
+
+// Age returns the time since the last modification of the FSPath, if it exists.
+func (p FSPath) Age(now time.Time) (time.Duration, error) {
+	stat, err := p.stat()
+
+	if err != nil {
+		return time.Duration(0), err
+	}
+
+	return now.Sub(stat.ModTime()), nil
+}
+
+// Open opens the FSPath for reading.
+func (p FSPath) Open() (fs.File, error) {
+	var f fs.File
+	err := p.do(func() (err error) {
+		f, err = p.fsys.Open(p.name)
+		return err
+	})
+	return f, err
+}
+
+// ReadBytes reads all the bytes from a file FSPath.
+func (p FSPath) ReadBytes() ([]byte, error) {
+	return p.readFile()
+}
+
+// WriteBytes writes the bytes to the FSPath.
+func (p FSPath) WriteBytes(data []byte) error {
+	return p.writeFile(data, 0644)
+}
+
+// Touch creates a file at the FSPath if it does not already exist.
+func (p FSPath) Touch() error {
+	if p.Exists() {
+		return nil
+	}
+
+	return p.WriteBytes(nil)
+}
+
+// ReadDir returns the entries of the directory FSPath, sorted by name.
+func (p FSPath) ReadDir() ([]FSPath, error) {
+	var entries []fs.DirEntry
+	err := p.do(func() (err error) {
+		entries, err = p.fsys.ReadDir(p.name)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]FSPath, 0, len(entries))
+
+	for _, entry := range entries {
+		paths = append(paths, p.with(path.Join(p.name, entry.Name())))
+	}
+
+	return paths, nil
+}
+
+// Glob returns a list of FSPaths that match the pattern within the directory.
+func (p FSPath) Glob(pattern string) ([]FSPath, error) {
+	if !p.IsDir() {
+		return nil, fmt.Errorf("Glob only works on directories: %s", p)
+	}
+
+	var matches []string
+	err := p.do(func() (err error) {
+		matches, err = fs.Glob(p.fsys, path.Join(p.name, pattern))
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	matchPaths := make([]FSPath, 0, len(matches))
+
+	for _, match := range matches {
+		matchPaths = append(matchPaths, p.with(filepath.ToSlash(match)))
+	}
+
+	return matchPaths, nil
+}
+
+// Mkdir creates the directory FSPath, including any parent directories that need to be created along the way.
+func (p FSPath) Mkdir() error {
+	if p.Exists() {
+		return fmt.Errorf("Cannot make directory %s because it already exists", p)
+	}
+
+	return p.do(func() error { return p.fsys.MkdirAll(p.name, 0755) })
+}
+
+// Unlink removes a file FSPath, but will return an error if the FSPath is a directory (see Rmdir).
+func (p FSPath) Unlink() error {
+	if p.IsDir() {
+		return fmt.Errorf("%s is a directory.  Use Rmdir() instead.", p)
+	}
+
+	return p.do(func() error { return p.fsys.Remove(p.name) })
+}
+
+// Rmdir removes a directory, but will return an error if there are items within that directory (see RmdirRecursive).
+func (p FSPath) Rmdir() error {
+	if !p.IsDir() {
+		return fmt.Errorf("%s is not a directory.  Use Unlink() instead.", p)
+	}
+
+	return p.do(func() error { return p.fsys.Remove(p.name) })
+}
+
+// RmdirRecursive removes a directory and all items within it.
+func (p FSPath) RmdirRecursive() error {
+	if !p.IsDir() {
+		return fmt.Errorf("%s is not a directory.  Use Unlink() instead.", p)
+	}
+
+	return p.do(func() error { return p.fsys.RemoveAll(p.name) })
+}
+
+// Rename changes the name of the file to the target FSPath, which must be on the same Filesystem.
+func (p FSPath) Rename(target FSPath) error {
+	if !sameFilesystem(p.fsys, target.fsys) {
+		return fmt.Errorf("Cannot rename %s to %s on a different filesystem", p, target)
+	}
+
+	return p.do(func() error { return p.fsys.Rename(p.name, target.name) })
+}
+
+// within reports whether the FSPath is the same as or beneath base on the same storage.  Backends taking OS paths all share the disk, so their names are compared as absolute paths.
+func (p FSPath) within(base FSPath) bool {
+	name, baseName := Path(filepath.FromSlash(p.name)), Path(filepath.FromSlash(base.name))
+	_, native := p.fsys.(osNamer)
+	_, baseNative := base.fsys.(osNamer)
+
+	if native && baseNative {
+		abs, err := filepath.Abs(string(name))
+
+		if err != nil {
+			return false
+		}
+
+		baseAbs, err := filepath.Abs(string(baseName))
+
+		if err != nil {
+			return false
+		}
+
+		return Path(abs).within(Path(baseAbs))
+	}
+
+	return sameFilesystem(p.fsys, base.fsys) && name.within(baseName)
+}
+
+// sameFilesystem reports whether a and b are the same Filesystem.  Backends that cannot be compared, such as those wrapping an fstest.MapFS, would make == panic, so they are never the same.
+func sameFilesystem(a, b Filesystem) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+
+	if !va.IsValid() || !vb.IsValid() {
+		return a == nil && b == nil
+	}
+
+	if va.Type() != vb.Type() || !va.Comparable() || !vb.Comparable() {
+		return false
+	}
+
+	return a == b
+}
+
+// JoinPath returns the FSPath joined with any number of Paths.
+func (p FSPath) JoinPath(paths ...Path) FSPath {
+	elems := []string{p.name}
+
+	for _, elem := range paths {
+		elems = append(elems, filepath.ToSlash(string(elem)))
+	}
+
+	return p.with(path.Join(elems...))
+}
+
+// Name returns only the last portion of the FSPath as a string.
+func (p FSPath) Name() string {
+	return path.Base(p.name)
+}
+
+// Parent returns the directory containing the FSPath.
+func (p FSPath) Parent() FSPath {
+	return p.with(path.Dir(p.name))
+}
+
+// WithSuffix returns a new FSPath with the specified suffix (file extension), as with Path.WithSuffix.
+func (p FSPath) WithSuffix(suffix string) FSPath {
+	return p.with(string(Path(p.name).WithSuffix(suffix)))
+}
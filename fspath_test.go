@@ -0,0 +1,202 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPathOnMemFilesystem(t *testing.T) {
+	mem := NewMemFilesystem()
+	dir := PathOn(mem, "/project/src")
+	err := dir.Mkdir()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if !dir.IsDir() || !dir.Parent().IsDir() {
+		t.Errorf("%s was not created", dir)
+	}
+
+	for _, name := range []Path{"main.go", "util.go", "notes.txt"} {
+		err = dir.JoinPath(name).WriteBytes([]byte(name))
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+	}
+
+	contents, err := dir.JoinPath("main.go").ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if string(contents) != "main.go" {
+		t.Errorf("Read %q", contents)
+	}
+
+	matches, err := dir.Glob("*.go")
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if fmt.Sprint(matches) != "[project/src/main.go project/src/util.go]" {
+		t.Errorf("Glob returned %v", matches)
+	}
+
+	err = dir.JoinPath("notes.txt").Rename(dir.Parent().JoinPath("notes.md"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if dir.JoinPath("notes.txt").Exists() || !PathOn(mem, "project/notes.md").IsFile() {
+		t.Errorf("Rename failed")
+	}
+
+	if err = dir.Rename(dir.JoinPath("sub")); err == nil {
+		t.Errorf("Expected an error renaming a directory into itself")
+	}
+
+	// a directory may only replace an empty one
+	PathOn(mem, "project/full/kept.txt").Parent().Mkdir()
+	PathOn(mem, "project/full/kept.txt").WriteBytes([]byte("kept"))
+
+	if err = dir.Rename(PathOn(mem, "project/full")); err == nil || !PathOn(mem, "project/full/kept.txt").IsFile() {
+		t.Errorf("Expected an error renaming a directory onto one that is not empty")
+	}
+
+	if err = dir.Rename(PathOn(mem, "project/notes.md")); err == nil {
+		t.Errorf("Expected an error renaming a directory onto a file")
+	}
+
+	PathOn(mem, "project/lib").Mkdir()
+
+	if err = dir.Rename(dir.Parent().JoinPath("lib")); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	dir = dir.Parent().JoinPath("lib")
+
+	if !dir.JoinPath("main.go").IsFile() || PathOn(mem, "project/src").Exists() {
+		t.Errorf("Renaming a directory did not move its contents")
+	}
+
+	err = dir.Rmdir()
+
+	if err == nil {
+		t.Errorf("Rmdir should fail for directories that are not empty")
+	}
+
+	err = dir.RmdirRecursive()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if dir.Exists() {
+		t.Errorf("RmdirRecursive failed")
+	}
+
+	if Path("/project").Exists() {
+		t.Errorf("MemFilesystem should not touch the disk")
+	}
+}
+
+func TestPathOnReadOnlyFS(t *testing.T) {
+	assets := fstest.MapFS{
+		"static/index.html": {Data: []byte("<html></html>")},
+		"static/app.js":     {Data: []byte("app")},
+	}
+
+	static := PathOn(assets, "static")
+
+	if !static.IsDir() || !static.JoinPath("index.html").IsFile() {
+		t.Errorf("Assets not found")
+	}
+
+	entries, err := static.ReadDir()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if len(entries) != 2 || entries[0].Name() != "app.js" {
+		t.Errorf("ReadDir returned %v", entries)
+	}
+
+	err = static.JoinPath("new.txt").WriteBytes([]byte("nope"))
+
+	if !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly, received %v", err)
+	}
+
+	if err = static.JoinPath("app.js").Rename(static.JoinPath("main.js")); err == nil {
+		t.Errorf("Expected an error renaming on a read-only filesystem")
+	}
+}
+
+func TestPathOnOS(t *testing.T) {
+	p := PathOn(OS, fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := p.WriteBytes([]byte("os"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	if !p.Path().IsFile() {
+		t.Errorf("%s should exist on disk", p)
+	}
+}
+
+func TestMemFilesystemConformance(t *testing.T) {
+	mem := NewMemFilesystem()
+	mem.MkdirAll("a/b", 0755)
+	mem.WriteFile("a/b/c.txt", []byte("c"), 0644)
+	mem.WriteFile("d.txt", []byte("d"), 0644)
+
+	err := fstest.TestFS(mem, "a/b/c.txt", "d.txt")
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	p.WriteBytes([]byte("production data"))
+	defer p.Unlink()
+
+	readOnly := ReadOnly(p)
+
+	if contents, err := readOnly.ReadBytes(); err != nil || string(contents) != "production data" {
+		t.Errorf("Expected to read the file, received %q: %v", contents, err)
+	}
+
+	if readOnly.Path() != p || !readOnly.IsFile() {
+		t.Errorf("Expected %s to keep its OS path, received %s", p, readOnly)
+	}
+
+	mutations := map[string]error{
+		"WriteBytes": readOnly.WriteBytes([]byte("oops")),
+		"Unlink":     readOnly.Unlink(),
+		"Mkdir":      ReadOnly(p + "-dir").Mkdir(),
+		"Rename":     readOnly.Rename(ReadOnly(p + "-renamed")),
+	}
+
+	for name, err := range mutations {
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("Expected %s to fail with ErrReadOnly, received %v", name, err)
+		}
+	}
+
+	if contents, _ := p.ReadBytes(); string(contents) != "production data" {
+		t.Errorf("The file was modified: %q", contents)
+	}
+}
@@ -0,0 +1,206 @@
+package pathlib
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The fuzz targets check invariants of the pure (filesystem-free) Path operations.  Their seed corpora run with the other tests; run one with, eg. "go test -fuzz FuzzWithSuffix" to search for inputs that break it.
+
+func FuzzWithSuffix(f *testing.F) {
+	for _, seed := range [][2]string{{"a.go", "txt"}, {".bashrc", "bak"}, {"dir/", "go"}, {"a.", "x"}, {"/a.b/c", "d"}, {"", "e"}, {"a.tar.gz", "zip"}} {
+		f.Add(seed[0], seed[1])
+	}
+
+	f.Fuzz(func(t *testing.T, p, suffix string) {
+		suffix = strings.TrimSpace(suffix)
+
+		if len(suffix) == 0 || strings.ContainsAny(suffix, "./\\") {
+			t.Skip()
+		}
+
+		with := Path(p).WithSuffix(suffix)
+
+		if again := with.WithSuffix(suffix); again != with {
+			t.Errorf("WithSuffix is not idempotent: %q then %q", with, again)
+		}
+
+		if filepath.Ext(string(with)) != "."+suffix {
+			t.Errorf("%q.WithSuffix(%q) = %q, which does not end with the suffix", p, suffix, with)
+		}
+
+		if stripped, original := with.WithSuffix(""), Path(p).WithSuffix(""); stripped != original {
+			t.Errorf("Removing the suffix from %q gives %q, but from the original %q gives %q", with, stripped, p, original)
+		}
+	})
+}
+
+func FuzzJoinPath(f *testing.F) {
+	for _, seed := range [][2]string{{"a", "b"}, {"/usr", "bin/go"}, {"a/./b", "c//d"}, {".", "x"}, {"/", "etc"}} {
+		f.Add(seed[0], seed[1])
+	}
+
+	f.Fuzz(func(t *testing.T, a, b string) {
+		joined := Path(a).JoinPath(Path(b))
+
+		if string(joined) != filepath.Clean(string(joined)) && len(joined) > 0 {
+			t.Errorf("JoinPath(%q, %q) = %q, which is not clean", a, b, joined)
+		}
+
+		if Path(a).JoinPath(Path(b), "c") != joined.JoinPath("c") {
+			t.Errorf("JoinPath is not associative for %q, %q", a, b)
+		}
+
+		if len(a) == 0 || len(b) == 0 || filepath.IsAbs(b) || filepath.VolumeName(a+b) != "" || strings.Contains(a+"/"+b, "..") {
+			return
+		}
+
+		if strings.Join(joined.Parts(), "\x00") != strings.Join(append(Path(a).Parts(), Path(b).Parts()...), "\x00") {
+			t.Errorf("Parts of JoinPath(%q, %q) = %q are not the Parts of each: %q and %q", a, b, joined.Parts(), Path(a).Parts(), Path(b).Parts())
+		}
+	})
+}
+
+func FuzzRelativeTo(f *testing.F) {
+	for _, seed := range [][2]string{{"/a/b/c", "/a"}, {"/a", "/a/b/c"}, {"a/b", "c/d"}, {"..", "a"}, {"", ""}, {"/x", "y"}} {
+		f.Add(seed[0], seed[1])
+	}
+
+	f.Fuzz(func(t *testing.T, p, base string) {
+		rel, err := Path(p).RelativeTo(Path(base))
+
+		if err != nil {
+			return
+		}
+
+		if joined := Path(base).JoinPath(rel); joined != Path(filepath.Clean(p)) {
+			t.Errorf("%q.RelativeTo(%q) = %q, but joining it to the base gives %q", p, base, rel, joined)
+		}
+	})
+}
+
+func FuzzParts(f *testing.F) {
+	for _, seed := range []string{"/usr/bin", "a//b/./c/", "../x", ".", "/", "a/../b"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, p string) {
+		parts := Path(p).Parts()
+
+		if len(parts) == 0 {
+			return
+		}
+
+		paths := make([]Path, len(parts)-1)
+
+		for i, part := range parts[1:] {
+			paths[i] = Path(part)
+		}
+
+		if joined := Path(parts[0]).JoinPath(paths...); joined != Path(filepath.Clean(p)) {
+			t.Errorf("Joining the Parts %q of %q gives %q", parts, p, joined)
+		}
+	})
+}
+
+func FuzzCompilePattern(f *testing.F) {
+	for _, seed := range [][2]string{{"*.go", "src/main.go"}, {"/a/**/b", "/a/x/y/b"}, {"[a-c]?", "b1"}, {"**", "x"}, {"a/*/c", "a/b/c"}, {"[^x]", "/"}} {
+		f.Add(seed[0], seed[1])
+	}
+
+	f.Fuzz(func(t *testing.T, pattern, p string) {
+		// without braces, a Pattern matches exactly what Path.Match does, except that Path.Match cleans ".." out of the pattern and lets character classes match the root of an absolute Path
+		if strings.ContainsAny(pattern, "{},!") || strings.Contains(pattern, `\`) || (strings.Contains(pattern, "[") && filepath.IsAbs(p)) || strings.Contains(pattern, "..") || !utf8.ValidString(pattern) || !utf8.ValidString(p) {
+			return
+		}
+
+		compiled, err := CompilePattern(pattern)
+
+		if err != nil {
+			return
+		}
+
+		for _, part := range Path(pattern).Parts() {
+			if _, err := filepath.Match(part, ""); err != nil {
+				return
+			}
+		}
+
+		if compiled.Match(Path(p)) != Path(p).Match(pattern) {
+			t.Errorf("CompilePattern(%q).Match(%q) = %v, but Path.Match gives %v", pattern, p, compiled.Match(Path(p)), Path(p).Match(pattern))
+		}
+	})
+}
+
+func FuzzEncodeName(f *testing.F) {
+	for _, seed := range []string{"plain.txt", "naïve file.txt", "a'b'c", "100%", "=?UTF-8?Q?x?="} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, name string) {
+		if !utf8.ValidString(name) {
+			return
+		}
+
+		if decoded, err := DecodeName(EncodeName(name)); err != nil || decoded != name {
+			t.Errorf("EncodeName(%q) = %q does not round trip: %q (%v)", name, EncodeName(name), decoded, err)
+		}
+	})
+}
+
+func FuzzFromUntrustedName(f *testing.F) {
+	for _, seed := range []string{"report.pdf", "../../etc/passwd", "CON.txt", "a‮b", "\xff\xfe", strings.Repeat("x", 300)} {
+		f.Add(seed)
+	}
+
+	dir := Path("/nonexistent-pathlib-fuzz")
+
+	f.Fuzz(func(t *testing.T, name string) {
+		p, err := FromUntrustedName(dir, name)
+
+		if err != nil {
+			return
+		}
+
+		if p.Parent() != dir || len(p.Name()) == 0 || len(p.Name()) > MaxNameBytes || !utf8.ValidString(string(p)) {
+			t.Errorf("FromUntrustedName(%q) = %q", name, p)
+		}
+
+		if strings.ContainsFunc(p.Name(), func(r rune) bool {
+			return unicode.IsControl(r) || unicode.Is(unicode.Cf, r) || strings.ContainsRune(`/\:*?"<>|`, r)
+		}) {
+			t.Errorf("FromUntrustedName(%q) = %q, which contains an unsafe character", name, p)
+		}
+
+		if again, err := FromUntrustedName(dir, p.Name()); err != nil || again != p {
+			t.Errorf("FromUntrustedName is not idempotent: %q then %q (%v)", p, again, err)
+		}
+	})
+}
+
+func FuzzBspatch(f *testing.F) {
+	var patch bytes.Buffer
+	bsdiff([]byte("hello world"), []byte("hello there world"), &patch)
+	f.Add(patch.Bytes(), int64(17))
+
+	// control values whose sum overflows
+	huge := binary.AppendVarint(binary.AppendVarint(binary.AppendVarint(nil, 1<<62), 1<<62), 0)
+	f.Add(huge, int64(10))
+	f.Add(huge, int64(1<<40))
+
+	f.Fuzz(func(t *testing.T, body []byte, newSize int64) {
+		if newSize < 0 || newSize > 1<<40 {
+			return // rejected by ApplyPatch before bspatch
+		}
+
+		if data, err := bspatch([]byte("hello world"), newSize, bufio.NewReader(bytes.NewReader(body))); err == nil && int64(len(data)) != newSize {
+			t.Errorf("bspatch returned %d bytes rather than %d", len(data), newSize)
+		}
+	})
+}
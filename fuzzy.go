@@ -0,0 +1,179 @@
+package pathlib
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FuzzyMatch is a result of Index.FuzzySearch.
+type FuzzyMatch struct {
+	Path  Path
+	Score int
+}
+
+// Scoring follows fzf: every matched character scores, gaps cost, and matches at the start of a name or word earn bonuses that carry through consecutive matches.
+const (
+	fuzzyScoreMatch        = 16
+	fuzzyGapStart          = -3
+	fuzzyGapExtension      = -1
+	fuzzyBonusBoundary     = fuzzyScoreMatch / 2
+	fuzzyBonusSeparator    = fuzzyBonusBoundary + 1
+	fuzzyBonusCamel        = fuzzyBonusBoundary + fuzzyGapExtension
+	fuzzyBonusConsecutive  = -(fuzzyGapStart + fuzzyGapExtension)
+	fuzzyFirstCharMultiple = 2
+)
+
+// FuzzySearch returns up to limit Paths (or all, if limit is not positive) whose path below the root contains the characters of the query in order, best matches first, using fzf-style scoring that favours matches at the start of names and words and runs of consecutive characters.  Matching ignores case unless the query contains upper case letters.
+func (ix *Index) FuzzySearch(query string, limit int) []FuzzyMatch {
+	if len(query) == 0 {
+		return nil
+	}
+
+	caseSensitive := strings.ToLower(query) != query
+	var matches []FuzzyMatch
+	var walk func(id int32, prefix string)
+
+	walk = func(id int32, prefix string) {
+		for _, child := range ix.nodes[id].children {
+			rel := prefix + ix.nodes[child].name
+
+			if score, ok := fuzzyScore(rel, query, caseSensitive); ok {
+				matches = append(matches, FuzzyMatch{Path: ix.root.JoinPath(Path(rel)), Score: score})
+			}
+
+			if ix.nodes[child].dir {
+				walk(child, rel+string(filepath.Separator))
+			}
+		}
+	}
+
+	walk(0, "")
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+
+		if len(matches[i].Path) != len(matches[j].Path) {
+			return len(matches[i].Path) < len(matches[j].Path)
+		}
+
+		return matches[i].Path < matches[j].Path
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
+
+func fuzzyFold(c byte, caseSensitive bool) byte {
+	if !caseSensitive && 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+
+	return c
+}
+
+// fuzzyScore scores the shortest window of text that contains the pattern in order, found as fzf's v1 algorithm does: forwards to the end of the first full match, then backwards to tighten its start.
+func fuzzyScore(text, pattern string, caseSensitive bool) (int, bool) {
+	p := 0
+	end := -1
+
+	for i := 0; i < len(text); i++ {
+		if fuzzyFold(text[i], caseSensitive) == fuzzyFold(pattern[p], caseSensitive) {
+			p++
+
+			if p == len(pattern) {
+				end = i
+				break
+			}
+		}
+	}
+
+	if end < 0 {
+		return 0, false
+	}
+
+	start := end
+
+	for p = len(pattern) - 1; start >= 0; start-- {
+		if fuzzyFold(text[start], caseSensitive) == fuzzyFold(pattern[p], caseSensitive) {
+			p--
+
+			if p < 0 {
+				break
+			}
+		}
+	}
+
+	score, consecutive, firstBonus := 0, 0, 0
+	inGap := false
+	p = 0
+
+	for i := start; i <= end; i++ {
+		if p < len(pattern) && fuzzyFold(text[i], caseSensitive) == fuzzyFold(pattern[p], caseSensitive) {
+			bonus := fuzzyBonus(text, i)
+
+			if consecutive == 0 {
+				firstBonus = bonus
+			} else {
+				if bonus >= fuzzyBonusBoundary && bonus > firstBonus {
+					firstBonus = bonus
+				}
+
+				bonus = max(bonus, firstBonus, fuzzyBonusConsecutive)
+			}
+
+			if p == 0 {
+				score += fuzzyScoreMatch + bonus*fuzzyFirstCharMultiple
+			} else {
+				score += fuzzyScoreMatch + bonus
+			}
+
+			inGap = false
+			consecutive++
+			p++
+		} else {
+			if inGap {
+				score += fuzzyGapExtension
+			} else {
+				score += fuzzyGapStart
+			}
+
+			inGap = true
+			consecutive = 0
+			firstBonus = 0
+		}
+	}
+
+	return score, true
+}
+
+// fuzzyBonus returns the bonus for matching the character at i, based on the character before it.
+func fuzzyBonus(text string, i int) int {
+	if i == 0 {
+		return fuzzyBonusSeparator
+	}
+
+	prev, c := text[i-1], text[i]
+
+	switch {
+	case prev == '/' || prev == filepath.Separator:
+		return fuzzyBonusSeparator
+	case prev == '_' || prev == '-' || prev == '.' || prev == ' ':
+		return fuzzyBonusBoundary
+	case 'a' <= prev && prev <= 'z' && 'A' <= c && c <= 'Z':
+		return fuzzyBonusCamel
+	case !isWordByte(prev) && isWordByte(c):
+		return fuzzyBonusBoundary
+	}
+
+	return 0
+}
+
+func isWordByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c >= 0x80
+}
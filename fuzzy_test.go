@@ -0,0 +1,70 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFuzzySearch(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	err := CreateTree(root, TreeSpec{
+		{Path: "src/main.go"}, {Path: "src/maintenance/notes.txt"}, {Path: "docs/Makefile"},
+		{Path: "src/components/MainButton.go"}, {Path: "README.md"},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer root.RmdirRecursive()
+
+	ix, err := BuildIndex(root)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	matches := ix.FuzzySearch("main", 0)
+
+	if len(matches) < 3 || matches[0].Path != root.JoinPath("src", "main.go") {
+		t.Errorf("Expected src/main.go first, received %v", matches)
+	}
+
+	for _, match := range matches {
+		if match.Path == root.JoinPath("README.md") {
+			t.Errorf("README.md should not match main")
+		}
+	}
+
+	matches = ix.FuzzySearch("smgo", 1)
+
+	if len(matches) != 1 || matches[0].Path != root.JoinPath("src", "main.go") {
+		t.Errorf("Expected only src/main.go for smgo, received %v", matches)
+	}
+
+	// upper case in the query makes it case sensitive
+	for _, match := range ix.FuzzySearch("MB", 0) {
+		if match.Path != root.JoinPath("src", "components", "MainButton.go") {
+			t.Errorf("Unexpected case-sensitive match %s", match.Path)
+		}
+	}
+
+	if matches = ix.FuzzySearch("zzz", 10); len(matches) != 0 {
+		t.Errorf("Expected no matches, received %v", matches)
+	}
+}
+
+func TestFuzzyScore(t *testing.T) {
+	better, _ := fuzzyScore("src/main.go", "main", false)
+	worse, _ := fuzzyScore("src/maintenance/notes.txt", "mnt", false)
+	scattered, _ := fuzzyScore("xmxaxixn", "main", false)
+
+	if better <= scattered || worse <= 0 {
+		t.Errorf("Unexpected scores: %d %d %d", better, worse, scattered)
+	}
+
+	if _, ok := fuzzyScore("abc", "abd", false); ok {
+		t.Errorf("Expected no match")
+	}
+}
@@ -0,0 +1,120 @@
+package pathlib
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// GCOptions controls the behavior of GC.
+type GCOptions struct {
+	// GracePeriod protects files younger than this (by Age) from deletion, so that files which are still being written or not yet referenced survive.
+	GracePeriod time.Duration
+
+	// DryRun reports what would be deleted without deleting anything.
+	DryRun bool
+
+	// RemoveEmptyDirs removes directories left empty by the collection.
+	RemoveEmptyDirs bool
+}
+
+// GCReport describes the result of GC.
+type GCReport struct {
+	// Deleted lists the files that were deleted (or would be, for a dry run).
+	Deleted []Path
+
+	// Young lists unreferenced files that were kept because of the grace period.
+	Young []Path
+
+	// Live is the number of files that were kept because they are referenced.
+	Live int
+
+	// BytesFreed is the total size of the deleted files.
+	BytesFreed int64
+}
+
+// GC deletes the files beneath root that are not referenced by the live set, returning a report of what was done.  A live directory protects everything beneath it.  Files younger than the grace period are kept.
+func GC(root Path, live []Path, opts GCOptions) (GCReport, error) {
+	var report GCReport
+	now := time.Now()
+	keep := make(map[Path]bool, len(live))
+	var liveDirs []Path
+
+	for _, p := range live {
+		abs, err := filepath.Abs(string(p))
+
+		if err != nil {
+			return report, err
+		}
+
+		keep[Path(abs)] = true
+
+		if Path(abs).IsDir() {
+			liveDirs = append(liveDirs, Path(abs))
+		}
+	}
+
+	absRoot, err := filepath.Abs(string(root))
+
+	if err != nil {
+		return report, err
+	}
+
+	var dirs []Path
+
+	err = filepath.Walk(absRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		p := Path(path)
+
+		if info.IsDir() {
+			for _, dir := range liveDirs {
+				if p.within(dir) {
+					return filepath.SkipDir
+				}
+			}
+
+			if path != absRoot {
+				dirs = append(dirs, p)
+			}
+
+			return nil
+		}
+
+		if keep[p] {
+			report.Live++
+			return nil
+		}
+
+		if now.Sub(info.ModTime()) < opts.GracePeriod {
+			report.Young = append(report.Young, p)
+			return nil
+		}
+
+		if !opts.DryRun {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+
+		report.Deleted = append(report.Deleted, p)
+		report.BytesFreed += info.Size()
+		return nil
+	})
+
+	if err != nil || !opts.RemoveEmptyDirs || opts.DryRun {
+		return report, err
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if count, err := dirs[i].CountEntries(false); err == nil && count == 0 {
+			if err = dirs[i].Rmdir(); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,63 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestGC(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(root, TreeSpec{
+		{Path: "live", Content: "1"},
+		{Path: "dead", Content: "22"},
+		{Path: "young", Content: "333"},
+		{Path: "sub/dead", Content: "4444"},
+		{Path: "kept/a", Content: "5"},
+	})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer root.RmdirRecursive()
+
+	old := time.Now().Add(-2 * time.Hour)
+
+	for _, name := range []Path{"live", "dead", "sub/dead", "kept/a"} {
+		os.Chtimes(string(root.JoinPath(name)), old, old)
+	}
+
+	live := []Path{root.JoinPath("live"), root.JoinPath("kept")}
+	opts := GCOptions{GracePeriod: time.Hour, DryRun: true}
+	report, err := GC(root, live, opts)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if len(report.Deleted) != 2 || !root.JoinPath("dead").Exists() {
+		t.Errorf("Dry run report incorrect: %+v", report)
+	}
+
+	opts.DryRun = false
+	opts.RemoveEmptyDirs = true
+	report, err = GC(root, live, opts)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if len(report.Deleted) != 2 || report.BytesFreed != 6 || len(report.Young) != 1 || report.Live != 1 {
+		t.Errorf("Report incorrect: %+v", report)
+	}
+
+	if root.JoinPath("dead").Exists() || root.JoinPath("sub").Exists() {
+		t.Errorf("Unreferenced files were not collected")
+	}
+
+	if !root.JoinPath("live").Exists() || !root.JoinPath("young").Exists() || !root.JoinPath("kept/a").Exists() {
+		t.Errorf("Referenced or young files were collected")
+	}
+}
@@ -0,0 +1,86 @@
+package pathlib
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GitRoot returns the top directory of the git repository (or worktree) containing the Path, found by looking upwards for ".git" as ProjectRoot does, so git need not be installed.
+func (p Path) GitRoot() (Path, error) {
+	return p.ProjectRoot(".git")
+}
+
+// IsGitIgnored returns true if the Path is ignored by the .gitignore rules (and other exclude files) of the git repository containing it, as reported by git check-ignore.  git must be installed.
+func (p Path) IsGitIgnored() (bool, error) {
+	_, err := p.gitDir().git("check-ignore", "-q", "--", string(p.absolute()))
+
+	var exitErr *exec.ExitError
+
+	// check-ignore exits with 1 when the path is not ignored
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// GitTrackedFiles returns the files beneath the directory Path that are tracked by its git repository, as reported by git ls-files.  git must be installed.
+func (p Path) GitTrackedFiles() ([]Path, error) {
+	if !p.IsDir() {
+		return nil, fmt.Errorf("GitTrackedFiles only works on directories: %s", p)
+	}
+
+	out, err := p.git("ls-files", "-z")
+
+	if err != nil {
+		return nil, err
+	}
+
+	var files []Path
+
+	for _, name := range strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00") {
+		if len(name) > 0 {
+			files = append(files, p.JoinPath(Path(name)))
+		}
+	}
+
+	return files, nil
+}
+
+// gitDir returns the Path if it is a directory, or the directory containing it, for running git from.
+func (p Path) gitDir() Path {
+	if p.IsDir() {
+		return p
+	}
+
+	return p.absolute().Parent()
+}
+
+// absolute returns the absolute form of the Path, or the Path itself if the working directory is unknown.
+func (p Path) absolute() Path {
+	abs, err := filepath.Abs(string(p))
+
+	if err != nil {
+		return p
+	}
+
+	return Path(abs)
+}
+
+// git runs git with the arguments in the directory Path, returning its output.  Errors include what git wrote to stderr.
+func (p Path) git(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", string(p)}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+
+	if err != nil && stderr.Len() > 0 {
+		return out, fmt.Errorf("git %s in %s: %s: %w", args[0], p, strings.TrimSpace(stderr.String()), err)
+	}
+
+	return out, err
+}
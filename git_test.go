@@ -0,0 +1,70 @@
+package pathlib
+
+import (
+	"fmt"
+	"os/exec"
+	"testing"
+)
+
+func TestGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	err := CreateTree(dir, TreeSpec{
+		{Path: ".gitignore", Content: "*.log\nbuild/\n"},
+		{Path: "main.go", Content: "package main"},
+		{Path: "sub/util.go", Content: "package sub"},
+		{Path: "sub/debug.log", Content: ""},
+		{Path: "build/out", Content: ""},
+		{Path: "untracked.go", Content: ""},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if _, err = dir.git("init", "-q"); err == nil {
+		_, err = dir.git("add", ".gitignore", "main.go", "sub/util.go")
+	}
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if root, err := dir.JoinPath("sub", "util.go").GitRoot(); err != nil || root != dir {
+		t.Errorf("Expected the git root to be %s but was %s (%v)", dir, root, err)
+	}
+
+	ignored := map[Path]bool{
+		"sub/debug.log": true,
+		"build/out":     true,
+		"build":         true,
+		"main.go":       false,
+		"untracked.go":  false,
+		"sub":           false,
+	}
+
+	for name, expected := range ignored {
+		if actual, err := dir.JoinPath(name).IsGitIgnored(); err != nil || actual != expected {
+			t.Errorf("Expected %s to be ignored: %v but was %v (%v)", name, expected, actual, err)
+		}
+	}
+
+	tracked, err := dir.JoinPath("sub").GitTrackedFiles()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if fmt.Sprint(tracked) != fmt.Sprint([]Path{dir.JoinPath("sub", "util.go")}) {
+		t.Errorf("Unexpected tracked files %v", tracked)
+	}
+
+	if _, err = Path("/").IsGitIgnored(); err == nil {
+		t.Errorf("Expected an error outside a repository")
+	}
+}
@@ -0,0 +1,119 @@
+package pathlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotGrowing is returned by EstimateTimeToFull when the growth rate is not positive, so the filesystem will never fill.
+var ErrNotGrowing = errors.New("not growing")
+
+// DiskUsage describes the space on the filesystem holding a Path.
+type DiskUsage struct {
+	Total     int64 // the size of the filesystem
+	Free      int64 // the space not in use
+	Available int64 // the space available to unprivileged users, which may be less than Free
+}
+
+// SizeSample is the size of a tree at a point in time.
+type SizeSample struct {
+	Time time.Time
+	Size int64
+}
+
+// GrowthReport describes how the size of a tree changed while GrowthRate sampled it.
+type GrowthReport struct {
+	// Samples are the sizes measured, oldest first.
+	Samples []SizeSample
+
+	// BytesPerHour is the least-squares fit of the growth across the samples.  It is negative if the tree shrank.
+	BytesPerHour float64
+}
+
+// GrowthRate samples the TreeSize of the Path the given number of times (at least two), evenly spaced across the window, and reports how fast it grew.  It blocks for the window, returning early with ctx's error if ctx is done first.
+func (p Path) GrowthRate(ctx context.Context, window time.Duration, samples int) (GrowthReport, error) {
+	var report GrowthReport
+
+	if samples < 2 {
+		return report, fmt.Errorf("GrowthRate needs at least 2 samples, not %d", samples)
+	}
+
+	interval := window / time.Duration(samples-1)
+
+	if interval <= 0 {
+		return report, fmt.Errorf("GrowthRate window %s is too short for %d samples", window, samples)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		size, err := p.TreeSize()
+
+		if err != nil {
+			return report, err
+		}
+
+		report.Samples = append(report.Samples, SizeSample{Time: time.Now(), Size: size})
+
+		if len(report.Samples) == samples {
+			break
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return report, ctx.Err()
+		}
+	}
+
+	report.BytesPerHour = bytesPerHour(report.Samples)
+	return report, nil
+}
+
+// bytesPerHour returns the slope of the least-squares line through the samples.  It is computed from the deviations from the means, so samples that do not change give exactly zero.
+func bytesPerHour(samples []SizeSample) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	var meanX, meanY float64
+	n := float64(len(samples))
+
+	for _, sample := range samples {
+		meanX += sample.Time.Sub(samples[0].Time).Hours()
+		meanY += float64(sample.Size)
+	}
+
+	meanX, meanY = meanX/n, meanY/n
+	var covariance, variance float64
+
+	for _, sample := range samples {
+		dx := sample.Time.Sub(samples[0].Time).Hours() - meanX
+		covariance += dx * (float64(sample.Size) - meanY)
+		variance += dx * dx
+	}
+
+	if variance == 0 {
+		return 0
+	}
+
+	return covariance / variance
+}
+
+// EstimateTimeToFull estimates how long until the filesystem holding the Path runs out of the space available to unprivileged users, if it keeps growing at the rate, such as one measured by GrowthRate.  If the rate is not positive, an error wrapping ErrNotGrowing is returned.
+func (p Path) EstimateTimeToFull(bytesPerHour float64) (time.Duration, error) {
+	if bytesPerHour <= 0 {
+		return 0, fmt.Errorf("Cannot estimate when %s will be full: %w", p, ErrNotGrowing)
+	}
+
+	usage, err := p.DiskUsage()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(float64(usage.Available) / bytesPerHour * float64(time.Hour)), nil
+}
@@ -0,0 +1,98 @@
+package pathlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGrowthRate(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{{Path: "a", Content: "hello"}})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	report, err := dir.GrowthRate(context.Background(), 20*time.Millisecond, 3)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if len(report.Samples) != 3 || report.Samples[2].Size != 5 || report.BytesPerHour != 0 {
+		t.Errorf("Expected 3 samples of an unchanging tree, received %+v", report)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err = dir.GrowthRate(ctx, time.Hour, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected GrowthRate to stop when the context is done, received %v", err)
+	}
+
+	if _, err = dir.GrowthRate(context.Background(), time.Second, 1); err == nil {
+		t.Errorf("Expected an error for a single sample")
+	}
+
+	for _, window := range []time.Duration{0, 2} {
+		if _, err = dir.GrowthRate(context.Background(), window, 4); err == nil {
+			t.Errorf("Expected an error for a window of %s", window)
+		}
+	}
+}
+
+func TestBytesPerHour(t *testing.T) {
+	start := time.Now()
+	samples := []SizeSample{
+		{Time: start, Size: 1000},
+		{Time: start.Add(30 * time.Minute), Size: 1600},
+		{Time: start.Add(time.Hour), Size: 2000},
+	}
+
+	// the least-squares line through (0, 1000), (0.5, 1600), (1, 2000)
+	if rate := bytesPerHour(samples); math.Abs(rate-1000) > 1e-6 {
+		t.Errorf("Expected 1000 bytes per hour, received %f", rate)
+	}
+
+	flat := []SizeSample{
+		{Time: start, Size: 123456789},
+		{Time: start.Add(20*time.Millisecond + 337*time.Microsecond), Size: 123456789},
+		{Time: start.Add(41*time.Millisecond + 9*time.Microsecond), Size: 123456789},
+	}
+
+	if rate := bytesPerHour(flat); rate != 0 {
+		t.Errorf("Expected exactly 0 bytes per hour for unchanging sizes, received %g", rate)
+	}
+}
+
+func TestEstimateTimeToFull(t *testing.T) {
+	usage, err := Path("/tmp").DiskUsage()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if usage.Total <= 0 || usage.Available > usage.Total {
+		t.Errorf("Unexpected disk usage %+v", usage)
+	}
+
+	estimate, err := Path("/tmp").EstimateTimeToFull(float64(usage.Available))
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if estimate < 59*time.Minute || estimate > 61*time.Minute {
+		t.Errorf("Growing by the available space each hour should fill it in an hour, not %s", estimate)
+	}
+
+	if _, err = Path("/tmp").EstimateTimeToFull(0); !errors.Is(err, ErrNotGrowing) {
+		t.Errorf("Expected ErrNotGrowing, received %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// HeartbeatFile is a file kept fresh by Heartbeat until it is stopped.
+type HeartbeatFile struct {
+	path Path
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+	mu   sync.Mutex
+	err  error
+}
+
+// Heartbeat creates the file Path, holding the host name and process ID, and touches it every interval until Stop is called, so that other processes sharing the filesystem can tell with IsAlive that this one is still running.  For cron-style jobs where only one should run at a time, check IsAlive before starting a Heartbeat, and give up if another is alive.  The file is recreated if it is removed while the Heartbeat runs.
+func Heartbeat(p Path, interval time.Duration) (*HeartbeatFile, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("Heartbeat interval must be positive, not %s", interval)
+	}
+
+	h := &HeartbeatFile{path: p, stop: make(chan struct{}), done: make(chan struct{})}
+
+	if err := h.write(); err != nil {
+		return nil, err
+	}
+
+	go h.run(interval)
+	return h, nil
+}
+
+// write replaces the file with the host name and process ID.
+func (h *HeartbeatFile) write() error {
+	host, _ := os.Hostname()
+	return h.path.writeBytesAtomic([]byte(fmt.Sprintf("%s %d\n", host, os.Getpid())), 0644)
+}
+
+func (h *HeartbeatFile) run(interval time.Duration) {
+	defer close(h.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-h.stop:
+			return
+		}
+
+		now := time.Now()
+		err := os.Chtimes(string(h.path), now, now)
+
+		if os.IsNotExist(err) {
+			err = h.write()
+		}
+
+		h.mu.Lock()
+		h.err = err
+		h.mu.Unlock()
+	}
+}
+
+// Err returns the error from the latest attempt to touch the file, or nil if it succeeded.
+func (h *HeartbeatFile) Err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.err
+}
+
+// Stop stops touching the file and removes it, so IsAlive immediately reports false.
+func (h *HeartbeatFile) Stop() error {
+	h.once.Do(func() { close(h.stop) })
+	<-h.done
+
+	if err := os.Remove(string(h.path)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// IsAlive returns true if the Path was modified within staleness, such as a file kept by a Heartbeat whose process is still running.  Staleness should allow for a few missed intervals, and for clock differences between hosts sharing a network filesystem.  A missing file is not alive.
+func (p Path) IsAlive(staleness time.Duration) (bool, error) {
+	stat, err := os.Stat(string(p))
+
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return time.Since(stat.ModTime()) <= staleness, nil
+}
@@ -0,0 +1,63 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestHeartbeat(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := dir.Mkdir(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	p := dir.JoinPath("job.alive")
+
+	if alive, err := p.IsAlive(time.Minute); err != nil || alive {
+		t.Errorf("A missing file should not be alive: %v", err)
+	}
+
+	h, err := Heartbeat(p, 10*time.Millisecond)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// backdate the file and remove it, and the heartbeat should restore both
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(string(p), past, past)
+
+	if alive, _ := p.IsAlive(time.Minute); alive {
+		t.Errorf("A file untouched for an hour should not be alive after a minute")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if alive, _ := p.IsAlive(time.Minute); !alive {
+		t.Errorf("The heartbeat should have touched the file")
+	}
+
+	os.Remove(string(p))
+	time.Sleep(50 * time.Millisecond)
+
+	if alive, _ := p.IsAlive(time.Minute); !alive || h.Err() != nil {
+		t.Errorf("The heartbeat should have recreated the file: %v", h.Err())
+	}
+
+	if err = h.Stop(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if alive, _ := p.IsAlive(time.Minute); alive {
+		t.Errorf("The file should not be alive once the heartbeat stops")
+	}
+
+	if _, err = Heartbeat(p, 0); err == nil {
+		t.Errorf("Expected an error for a zero interval")
+	}
+}
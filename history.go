@@ -0,0 +1,243 @@
+package pathlib
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// historyTimeFormat names the versions kept by a FileHistory, so that their names sort chronologically.
+const historyTimeFormat = "20060102T150405.000000000Z"
+
+// HistoryOptions configures a FileHistory.
+type HistoryOptions struct {
+	// Dir is the shadow directory the versions are kept in.  If empty, ".NAME.history" next to the file is used.
+	Dir Path
+
+	// MaxVersions limits how many versions are kept, oldest removed first.  Zero keeps any number.
+	MaxVersions int
+
+	// MaxAge removes versions older than this.  Zero keeps versions of any age.  The latest version is always kept.
+	MaxAge time.Duration
+}
+
+// FileVersion is a copy of a file kept by a FileHistory.
+type FileVersion struct {
+	Path Path      // the copy within the shadow directory
+	Time time.Time // when it was recorded
+	Size int64
+}
+
+// FileHistory keeps timestamped copies of a file as it changes, for lightweight local versioning of files such as configuration.  Versions are recorded by calling Record, or automatically by Track.  It is safe for concurrent use.
+type FileHistory struct {
+	file Path
+	dir  Path
+	opts HistoryOptions
+	mu   sync.Mutex
+}
+
+// History returns a FileHistory for the file Path.  Nothing is recorded until Record or Track is called.
+func (p Path) History(opts HistoryOptions) *FileHistory {
+	dir := opts.Dir
+
+	if len(dir) == 0 {
+		dir = p.Parent().JoinPath(Path("." + p.Name() + ".history"))
+	}
+
+	return &FileHistory{file: p, dir: dir, opts: opts}
+}
+
+// Dir returns the shadow directory the versions are kept in.
+func (h *FileHistory) Dir() Path {
+	return h.dir
+}
+
+// Record copies the file into the history if its contents differ from the latest version, then removes the versions that the retention limits no longer allow.  It returns whether a version was recorded.
+func (h *FileHistory) Record() (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	info, err := os.Stat(string(h.file))
+
+	if err != nil {
+		return false, err
+	}
+
+	if !info.Mode().IsRegular() {
+		return false, fmt.Errorf("Cannot record the history of %s because it is not a regular file", h.file)
+	}
+
+	versions, err := h.versions()
+
+	if err != nil {
+		return false, err
+	}
+
+	if len(versions) > 0 {
+		latest := versions[len(versions)-1]
+
+		if latest.Size == info.Size() {
+			current, err := h.file.sha256Hex()
+
+			if err != nil {
+				return false, err
+			}
+
+			if previous, err := latest.Path.sha256Hex(); err == nil && previous == current {
+				return false, nil
+			}
+		}
+	}
+
+	if err = os.MkdirAll(string(h.dir), 0700); err != nil {
+		return false, err
+	}
+
+	name := time.Now().UTC().Format(historyTimeFormat) + h.file.Suffix()
+	tmp := h.dir.JoinPath(Path(".tmp-" + name))
+
+	if err = copyFile(h.file, tmp, info.Mode().Perm()); err != nil {
+		os.Remove(string(tmp))
+		return false, err
+	}
+
+	if err = os.Rename(string(tmp), string(h.dir.JoinPath(Path(name)))); err != nil {
+		os.Remove(string(tmp))
+		return false, err
+	}
+
+	return true, h.prune()
+}
+
+// prune removes the versions beyond the retention limits, always keeping the latest.
+func (h *FileHistory) prune() error {
+	versions, err := h.versions()
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for i, version := range versions[:len(versions)-1] {
+		tooMany := h.opts.MaxVersions > 0 && len(versions)-i > h.opts.MaxVersions
+		tooOld := h.opts.MaxAge > 0 && now.Sub(version.Time) > h.opts.MaxAge
+
+		if tooMany || tooOld {
+			if err = os.Remove(string(version.Path)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Versions returns the recorded versions of the file, oldest first.
+func (h *FileHistory) Versions() ([]FileVersion, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.versions()
+}
+
+func (h *FileHistory) versions() ([]FileVersion, error) {
+	entries, err := os.ReadDir(string(h.dir))
+
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var versions []FileVersion
+
+	for _, entry := range entries {
+		t, err := time.Parse(historyTimeFormat, strings.TrimSuffix(entry.Name(), h.file.Suffix()))
+
+		if err != nil || !entry.Type().IsRegular() {
+			continue
+		}
+
+		info, err := entry.Info()
+
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, FileVersion{Path: h.dir.JoinPath(Path(entry.Name())), Time: t, Size: info.Size()})
+	}
+
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Time.Before(versions[j].Time) })
+	return versions, nil
+}
+
+// Revert atomically replaces the contents of the file with the version.  The current contents are recorded first, so a revert can itself be undone.
+func (h *FileHistory) Revert(version FileVersion) error {
+	// read first, since recording the current contents may prune the version
+	data, err := version.Path.ReadBytes()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = h.Record(); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return h.file.writeBytesAtomic(data, 0644)
+}
+
+// Track records the file now and then again whenever its size or modification time changes, checking every interval.  Each recorded version is sent on the channel, which is closed when ctx is done.  Errors after the first recording, such as the file being briefly missing while an editor replaces it, are skipped.
+func (h *FileHistory) Track(ctx context.Context, interval time.Duration) (<-chan FileVersion, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("Track interval must be positive, not %s", interval)
+	}
+
+	if _, err := h.Record(); err != nil {
+		return nil, err
+	}
+
+	previous, _ := os.Stat(string(h.file))
+	versions := make(chan FileVersion)
+
+	go func() {
+		defer close(versions)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			current, err := os.Stat(string(h.file))
+
+			if err != nil || (previous != nil && current.Size() == previous.Size() && current.ModTime().Equal(previous.ModTime())) {
+				continue
+			}
+
+			previous = current
+
+			if recorded, err := h.Record(); err != nil || !recorded {
+				continue
+			}
+
+			if all, err := h.Versions(); err == nil && len(all) > 0 {
+				select {
+				case versions <- all[len(all)-1]:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return versions, nil
+}
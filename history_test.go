@@ -0,0 +1,120 @@
+package pathlib
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFileHistory(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+	file := dir.JoinPath("app.conf")
+
+	if err := CreateTree(dir, TreeSpec{{Path: "app.conf", Content: "v1"}}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	history := file.History(HistoryOptions{MaxVersions: 2})
+
+	if history.Dir() != dir.JoinPath(".app.conf.history") {
+		t.Errorf("Unexpected history directory %s", history.Dir())
+	}
+
+	for i, content := range []string{"v1", "v1", "v2", "v3"} {
+		if err := file.WriteBytes([]byte(content)); err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		recorded, err := history.Record()
+
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		if recorded != (i != 1) {
+			t.Errorf("Expected recording %q (%d) to be %v", content, i, i != 1)
+		}
+	}
+
+	versions, err := history.Versions()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if len(versions) != 2 || versions[0].Path.Suffix() != ".conf" {
+		t.Fatalf("Expected the 2 latest versions but found %v", versions)
+	}
+
+	if data, _ := versions[0].Path.ReadBytes(); string(data) != "v2" {
+		t.Errorf("Expected the oldest kept version to be v2 but was %q", data)
+	}
+
+	if err = history.Revert(versions[0]); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if data, _ := file.ReadBytes(); string(data) != "v2" {
+		t.Errorf("Expected the file to be reverted to v2 but was %q", data)
+	}
+
+	// with MaxVersions reached, recording the changed file prunes the oldest version, which must still be restored
+	if err = file.WriteBytes([]byte("v4")); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if err = history.Revert(versions[0]); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if data, _ := file.ReadBytes(); string(data) != "v2" {
+		t.Errorf("Expected the file to be reverted to the pruned v2 but was %q", data)
+	}
+}
+
+func TestFileHistoryTrack(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+	file := dir.JoinPath("app.conf")
+
+	if err := CreateTree(dir, TreeSpec{{Path: "app.conf", Content: "v1"}}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	history := file.History(HistoryOptions{Dir: dir.JoinPath("shadow")})
+	if _, err := history.Track(ctx, 0); err == nil {
+		t.Errorf("Expected an error for a zero interval")
+	}
+
+	versions, err := history.Track(ctx, 5*time.Millisecond)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if err = file.writeBytesAtomic([]byte("version two"), 0644); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	select {
+	case version := <-versions:
+		if data, _ := version.Path.ReadBytes(); string(data) != "version two" {
+			t.Errorf("Expected the new version to be recorded but found %q", data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for a version")
+	}
+
+	if all, _ := history.Versions(); len(all) != 2 {
+		t.Errorf("Expected 2 versions but found %v", all)
+	}
+
+	cancel()
+
+	for range versions {
+	}
+}
@@ -0,0 +1,272 @@
+package pathlib
+
+import (
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Index is an in-memory trie of every Path below a root directory, built once by BuildIndex so that membership tests, prefix queries, and glob matching can be answered without walking the filesystem again.  It can be saved with Save, reloaded with LoadIndex, and brought up to date with Refresh.  An Index is safe for concurrent reads, but not during Refresh.
+type Index struct {
+	root  Path
+	built time.Time
+	nodes []indexNode
+}
+
+// indexNode is one entry of the trie.  Node 0 is the root.
+type indexNode struct {
+	name     string
+	parent   int32
+	children []int32 // sorted by name
+	dir      bool
+	modTime  int64 // of directories, in Unix nanoseconds
+}
+
+// BuildIndex walks the directory root and indexes every file and directory below it.  Symbolic links are indexed but not followed.
+func BuildIndex(root Path) (*Index, error) {
+	if !root.IsDir() {
+		return nil, fmt.Errorf("BuildIndex only works on directories: %s", root)
+	}
+
+	ix := &Index{root: root, built: time.Now(), nodes: []indexNode{{parent: -1, dir: true}}}
+
+	if err := ix.scan(0, root, nil, -1); err != nil {
+		return nil, err
+	}
+
+	return ix, nil
+}
+
+// Refresh brings the Index up to date with the filesystem.  Only directories whose modification times have changed since the Index was built are read again; the rest are just checked with stat, so refreshing a large, mostly unchanged tree is much faster than rebuilding it.
+func (ix *Index) Refresh() error {
+	fresh := &Index{root: ix.root, built: time.Now(), nodes: []indexNode{{parent: -1, dir: true}}}
+
+	if err := fresh.scan(0, ix.root, ix, 0); err != nil {
+		return err
+	}
+
+	*ix = *fresh
+	return nil
+}
+
+// scan adds the entries of dir below the node id.  When old has an unchanged node for dir, its entries are copied rather than read again.
+func (ix *Index) scan(id int32, dir Path, old *Index, oldID int32) error {
+	stat, err := os.Stat(string(dir))
+
+	if err != nil {
+		return err
+	}
+
+	modTime := stat.ModTime().UnixNano()
+	ix.nodes[id].modTime = modTime
+
+	type entry struct {
+		name string
+		dir  bool
+	}
+
+	var entries []entry
+
+	// a directory modified as (or after) the old index was built may have changed again within the same timestamp
+	if old != nil && oldID >= 0 && old.nodes[oldID].modTime == modTime && modTime < old.built.UnixNano() {
+		for _, child := range old.nodes[oldID].children {
+			entries = append(entries, entry{old.nodes[child].name, old.nodes[child].dir})
+		}
+	} else {
+		dirEntries, err := os.ReadDir(string(dir))
+
+		if err != nil {
+			return err
+		}
+
+		for _, dirEntry := range dirEntries {
+			entries = append(entries, entry{dirEntry.Name(), dirEntry.IsDir()})
+		}
+	}
+
+	for _, e := range entries {
+		child := int32(len(ix.nodes))
+		ix.nodes = append(ix.nodes, indexNode{name: e.name, parent: id, dir: e.dir})
+		ix.nodes[id].children = append(ix.nodes[id].children, child)
+
+		if e.dir {
+			oldChild := int32(-1)
+
+			if old != nil && oldID >= 0 {
+				oldChild = old.child(oldID, e.name)
+			}
+
+			if err = ix.scan(child, dir.JoinPath(Path(e.name)), old, oldChild); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Root returns the directory the Index was built from.
+func (ix *Index) Root() Path {
+	return ix.root
+}
+
+// Len returns the number of Paths in the Index, not counting the root.
+func (ix *Index) Len() int {
+	return len(ix.nodes) - 1
+}
+
+// path rebuilds the Path of the node.
+func (ix *Index) path(id int32) Path {
+	var parts []string
+
+	for ; id > 0; id = ix.nodes[id].parent {
+		parts = append(parts, ix.nodes[id].name)
+	}
+
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+
+	return Path(filepath.Join(append([]string{string(ix.root)}, parts...)...))
+}
+
+// child returns the child of the node with the name, or -1.
+func (ix *Index) child(id int32, name string) int32 {
+	children := ix.nodes[id].children
+	i := sort.Search(len(children), func(i int) bool { return ix.nodes[children[i]].name >= name })
+
+	if i < len(children) && ix.nodes[children[i]].name == name {
+		return children[i]
+	}
+
+	return -1
+}
+
+// find returns the node for the Path within the root, or -1.
+func (ix *Index) find(p Path) int32 {
+	rel, err := p.RelativeTo(ix.root)
+
+	if err != nil {
+		return -1
+	}
+
+	id := int32(0)
+
+	for _, part := range rel.Parts() {
+		if part == ".." || id < 0 {
+			return -1
+		}
+
+		id = ix.child(id, part)
+	}
+
+	return id
+}
+
+// Contains returns true if the Path was found when the Index was built.  Like the Paths the Index returns, it must include the root.
+func (ix *Index) Contains(p Path) bool {
+	return ix.find(p) > 0
+}
+
+// IsDir returns true if the Path was indexed as a directory.
+func (ix *Index) IsDir(p Path) bool {
+	id := ix.find(p)
+	return id > 0 && ix.nodes[id].dir
+}
+
+// All returns an iterator over every Path in the Index, in lexical order.
+func (ix *Index) All() iter.Seq[Path] {
+	return func(yield func(Path) bool) {
+		ix.descend(0, yield)
+	}
+}
+
+// descend yields every Path below the node, returning false once the consumer stops.
+func (ix *Index) descend(id int32, yield func(Path) bool) bool {
+	for _, child := range ix.nodes[id].children {
+		if !yield(ix.path(child)) || !ix.descend(child, yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Prefix returns an iterator over the Paths that start with the prefix (which includes the root, as the Paths it returns do), along with everything below them.  A prefix ending in a separator, or naming the root, lists the contents of that directory; otherwise its last component matches the start of names, so "/srv/src/comp" finds "/srv/src/components" and "/srv/src/compat.go".
+func (ix *Index) Prefix(prefix string) iter.Seq[Path] {
+	return func(yield func(Path) bool) {
+		dir, partial := filepath.Split(prefix)
+
+		if filepath.Clean(prefix) == filepath.Clean(string(ix.root)) {
+			dir, partial = prefix, ""
+		}
+
+		id := ix.find(Path(dir))
+
+		if id < 0 {
+			return
+		}
+
+		for _, child := range ix.nodes[id].children {
+			if strings.HasPrefix(ix.nodes[child].name, partial) {
+				if !yield(ix.path(child)) || !ix.descend(child, yield) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Glob returns the indexed Paths matching the pattern, which is relative to the root and may use "**" to match any number of directories as in RGlob.  Unlike RGlob, the pattern is anchored at the root, so use "**/*.go" to search everywhere.
+func (ix *Index) Glob(pattern string) ([]Path, error) {
+	parts := Path(pattern).Parts()
+
+	for _, part := range parts {
+		if _, err := filepath.Match(part, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	seen := map[int32]bool{}
+	var matches []int32
+	ix.glob(0, parts, seen, &matches)
+	paths := make([]Path, 0, len(matches))
+
+	for _, id := range matches {
+		paths = append(paths, ix.path(id))
+	}
+
+	sort.Slice(paths, func(i, j int) bool { return compareWalkOrder(paths[i], paths[j]) < 0 })
+	return paths, nil
+}
+
+func (ix *Index) glob(id int32, pattern []string, seen map[int32]bool, matches *[]int32) {
+	if len(pattern) == 0 {
+		if id > 0 && !seen[id] {
+			seen[id] = true
+			*matches = append(*matches, id)
+		}
+
+		return
+	}
+
+	if pattern[0] == "**" {
+		ix.glob(id, pattern[1:], seen, matches)
+
+		for _, child := range ix.nodes[id].children {
+			ix.glob(child, pattern, seen, matches)
+		}
+
+		return
+	}
+
+	for _, child := range ix.nodes[id].children {
+		if matched, _ := filepath.Match(pattern[0], ix.nodes[child].name); matched {
+			ix.glob(child, pattern[1:], seen, matches)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func indexTestTree(t *testing.T) Path {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	err := CreateTree(root, TreeSpec{
+		{Path: "README.md"}, {Path: "src/components/button.go"}, {Path: "src/compat.go"},
+		{Path: "src/main.go"}, {Path: "docs/guide.md"}, {Path: "empty", Dir: true},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	return root
+}
+
+func TestIndex(t *testing.T) {
+	root := indexTestTree(t)
+	defer root.RmdirRecursive()
+
+	ix, err := BuildIndex(root)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if ix.Len() != 9 || ix.Root() != root {
+		t.Errorf("Expected 9 Paths under %s, received %d under %s", root, ix.Len(), ix.Root())
+	}
+
+	if !ix.Contains(root.JoinPath("src", "main.go")) || ix.Contains(root.JoinPath("src", "other.go")) || ix.Contains(root) || ix.Contains("/etc/passwd") {
+		t.Errorf("Contains gave the wrong answer")
+	}
+
+	if !ix.IsDir(root.JoinPath("empty")) || ix.IsDir(root.JoinPath("README.md")) {
+		t.Errorf("IsDir gave the wrong answer")
+	}
+
+	all := slices.Collect(ix.All())
+
+	if len(all) != 9 || all[0] != root.JoinPath("README.md") {
+		t.Errorf("Unexpected iteration: %v", all)
+	}
+
+	prefixes := map[string][]Path{
+		string(root.JoinPath("src", "comp")): {root.JoinPath("src", "compat.go"), root.JoinPath("src", "components"), root.JoinPath("src", "components", "button.go")},
+		string(root.JoinPath("docs")) + "/":  {root.JoinPath("docs", "guide.md")},
+		string(root.JoinPath("nothing")):     nil,
+		"/elsewhere/":                        nil,
+	}
+
+	for prefix, expected := range prefixes {
+		if matches := slices.Collect(ix.Prefix(prefix)); fmt.Sprint(matches) != fmt.Sprint(expected) {
+			t.Errorf("Prefix %s: expected %v, received %v", prefix, expected, matches)
+		}
+	}
+
+	if n := len(slices.Collect(ix.Prefix(string(root)))); n != 9 {
+		t.Errorf("Expected the root prefix to list everything, received %d", n)
+	}
+
+	globs := map[string][]Path{
+		"**/*.go": {root.JoinPath("src", "compat.go"), root.JoinPath("src", "components", "button.go"), root.JoinPath("src", "main.go")},
+		"*.md":    {root.JoinPath("README.md")},
+		"*/*.md":  {root.JoinPath("docs", "guide.md")},
+		"src/**":  {root.JoinPath("src"), root.JoinPath("src", "compat.go"), root.JoinPath("src", "components"), root.JoinPath("src", "components", "button.go"), root.JoinPath("src", "main.go")},
+	}
+
+	for pattern, expected := range globs {
+		matches, err := ix.Glob(pattern)
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+
+		if fmt.Sprint(matches) != fmt.Sprint(expected) {
+			t.Errorf("Glob %s: expected %v, received %v", pattern, expected, matches)
+		}
+	}
+
+	if _, err = ix.Glob("[x"); err == nil {
+		t.Errorf("Expected an error for a malformed pattern")
+	}
+}
+
+func TestIndexRefresh(t *testing.T) {
+	root := indexTestTree(t)
+	defer root.RmdirRecursive()
+
+	ix, err := BuildIndex(root)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// make the existing directories look old so Refresh can trust their modification times
+	past := time.Now().Add(-time.Hour)
+
+	for p := range ix.All() {
+		if ix.IsDir(p) {
+			os.Chtimes(string(p), past, past)
+		}
+	}
+
+	os.Chtimes(string(root), past, past)
+
+	if err = ix.Refresh(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if ix.Len() != 9 {
+		t.Errorf("Refresh changed an unchanged index to %d Paths", ix.Len())
+	}
+
+	root.JoinPath("src", "main.go").Unlink()
+	root.JoinPath("docs", "new.md").Touch()
+	root.JoinPath("src", "components").RmdirRecursive()
+
+	if err = ix.Refresh(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	expected := []Path{
+		root.JoinPath("README.md"), root.JoinPath("docs"), root.JoinPath("docs", "guide.md"), root.JoinPath("docs", "new.md"),
+		root.JoinPath("empty"), root.JoinPath("src"), root.JoinPath("src", "compat.go"),
+	}
+
+	if all := slices.Collect(ix.All()); fmt.Sprint(all) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v after refreshing, received %v", expected, all)
+	}
+}
@@ -0,0 +1,197 @@
+package pathlib
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// indexMagic starts every file written by Index.Save.
+const indexMagic = "PLINDEX1"
+
+// Save writes the Index to the file Path, so it can be reused by a later run with LoadIndex.  The format is compact: the trie is stored in depth-first order, with each name sharing its common prefix with the previous sibling, and the whole is compressed with DEFLATE.
+func (ix *Index) Save(p Path) error {
+	var buf bytes.Buffer
+	buf.WriteString(indexMagic)
+	compressor, err := flate.NewWriter(&buf, flate.BestSpeed)
+
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(compressor)
+	writeIndexString(w, string(ix.root))
+	writeIndexVarint(w, ix.built.UnixNano())
+	writeIndexUvarint(w, uint64(len(ix.nodes)))
+	ix.writeNode(w, 0)
+
+	if err = w.Flush(); err != nil {
+		return err
+	}
+
+	if err = compressor.Close(); err != nil {
+		return err
+	}
+
+	return p.writeBytesAtomic(buf.Bytes(), 0644)
+}
+
+// writeNode writes the modification time and children of the directory node, recursively.
+func (ix *Index) writeNode(w *bufio.Writer, id int32) {
+	writeIndexVarint(w, ix.nodes[id].modTime)
+	writeIndexUvarint(w, uint64(len(ix.nodes[id].children)))
+	previous := ""
+
+	for _, child := range ix.nodes[id].children {
+		node := ix.nodes[child]
+		shared := 0
+
+		for shared < len(previous) && shared < len(node.name) && previous[shared] == node.name[shared] {
+			shared++
+		}
+
+		flags := uint64(shared) << 1
+
+		if node.dir {
+			flags |= 1
+		}
+
+		writeIndexUvarint(w, flags)
+		writeIndexString(w, node.name[shared:])
+		previous = node.name
+
+		if node.dir {
+			ix.writeNode(w, child)
+		}
+	}
+}
+
+func writeIndexUvarint(w *bufio.Writer, value uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	w.Write(buf[:binary.PutUvarint(buf[:], value)])
+}
+
+func writeIndexVarint(w *bufio.Writer, value int64) {
+	var buf [binary.MaxVarintLen64]byte
+	w.Write(buf[:binary.PutVarint(buf[:], value)])
+}
+
+func writeIndexString(w *bufio.Writer, s string) {
+	writeIndexUvarint(w, uint64(len(s)))
+	w.WriteString(s)
+}
+
+// LoadIndex reads an Index written by Save.
+func LoadIndex(p Path) (*Index, error) {
+	f, err := os.Open(string(p))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	magic := make([]byte, len(indexMagic))
+
+	if _, err = io.ReadFull(f, magic); err != nil || string(magic) != indexMagic {
+		return nil, fmt.Errorf("Not an index file: %s", p)
+	}
+
+	r := bufio.NewReader(flate.NewReader(bufio.NewReader(f)))
+	ix := &Index{}
+	root, err := readIndexString(r)
+	var built int64
+	var count uint64
+
+	if err == nil {
+		if built, err = binary.ReadVarint(r); err == nil {
+			count, err = binary.ReadUvarint(r)
+		}
+	}
+
+	if err == nil {
+		ix.root = Path(root)
+		ix.built = time.Unix(0, built)
+		ix.nodes = make([]indexNode, 1, min(count, 1<<24))
+		ix.nodes[0] = indexNode{parent: -1, dir: true}
+		err = ix.readNode(r, 0)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Corrupt index %s: %w", p, err)
+	}
+
+	return ix, nil
+}
+
+func (ix *Index) readNode(r *bufio.Reader, id int32) error {
+	modTime, err := binary.ReadVarint(r)
+
+	if err != nil {
+		return err
+	}
+
+	count, err := binary.ReadUvarint(r)
+
+	if err != nil {
+		return err
+	}
+
+	ix.nodes[id].modTime = modTime
+	previous := ""
+
+	for i := uint64(0); i < count; i++ {
+		flags, err := binary.ReadUvarint(r)
+
+		if err != nil {
+			return err
+		}
+
+		suffix, err := readIndexString(r)
+
+		if err != nil {
+			return err
+		}
+
+		shared := flags >> 1
+
+		if shared > uint64(len(previous)) {
+			return fmt.Errorf("invalid name prefix")
+		}
+
+		child := int32(len(ix.nodes))
+		name := previous[:shared] + suffix
+		ix.nodes = append(ix.nodes, indexNode{name: name, parent: id, dir: flags&1 != 0})
+		ix.nodes[id].children = append(ix.nodes[id].children, child)
+		previous = name
+
+		if flags&1 != 0 {
+			if err = ix.readNode(r, child); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func readIndexString(r *bufio.Reader) (string, error) {
+	length, err := binary.ReadUvarint(r)
+
+	if err != nil {
+		return "", err
+	}
+
+	if length > 1<<16 {
+		return "", fmt.Errorf("name too long")
+	}
+
+	buf := make([]byte, length)
+	_, err = io.ReadFull(r, buf)
+	return string(buf), err
+}
@@ -0,0 +1,39 @@
+package pathlib
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestIndexSave(t *testing.T) {
+	root := indexTestTree(t)
+	defer root.RmdirRecursive()
+
+	ix, err := BuildIndex(root)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	saved := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer saved.Unlink()
+
+	if err = ix.Save(saved); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	loaded, err := LoadIndex(saved)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if fmt.Sprint(slices.Collect(loaded.All())) != fmt.Sprint(slices.Collect(ix.All())) || loaded.Root() != root {
+		t.Errorf("Loaded index does not match the saved one")
+	}
+
+	if _, err = LoadIndex(root.JoinPath("README.md")); err == nil {
+		t.Errorf("Expected an error loading a file that is not an index")
+	}
+}
@@ -0,0 +1,154 @@
+package pathlib
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+)
+
+// IngestOptions configures Ingest.  The zero value uses the defaults described for each field.
+type IngestOptions struct {
+	// PollInterval is how often the directory is scanned.  Zero means a second.
+	PollInterval time.Duration
+
+	// StableFor is how long a file's size and modification time must stay the same before it is considered fully written.  Zero means two seconds.
+	StableFor time.Duration
+
+	// DoneDir and FailedDir are where files are moved after the handler succeeds or fails.  Empty means "done" and "failed" within the directory.
+	DoneDir   Path
+	FailedDir Path
+}
+
+// ingestState tracks a file waiting to become stable.
+type ingestState struct {
+	size    int64
+	modTime time.Time
+	since   time.Time
+}
+
+// Ingest watches the directory Path as a hot folder: each regular file dropped into it is passed to the handler once it is fully written, then moved to DoneDir if the handler succeeds, or to FailedDir if it fails, along with a NAME.error file holding the error.  A file counts as fully written once its size and modification time have not changed for StableFor, and no other process holds it locked or open, as for WaitUntilStable.  Names starting with "." are ignored, so writers can upload under a hidden name and rename the file when done.  Files are handled one at a time, in the order they become stable, and a name already taken in the destination gets a numeric suffix.  Ingest blocks until ctx is done, returning its error, or until files cannot be listed or moved.
+func Ingest(ctx context.Context, dir Path, handler func(Path) error, opts IngestOptions) error {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+
+	if opts.StableFor <= 0 {
+		opts.StableFor = 2 * time.Second
+	}
+
+	if len(opts.DoneDir) == 0 {
+		opts.DoneDir = dir.JoinPath("done")
+	}
+
+	if len(opts.FailedDir) == 0 {
+		opts.FailedDir = dir.JoinPath("failed")
+	}
+
+	for _, d := range []Path{opts.DoneDir, opts.FailedDir} {
+		if err := os.MkdirAll(string(d), 0755); err != nil {
+			return err
+		}
+	}
+
+	pending := map[string]ingestState{}
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := ingestOnce(ctx, dir, handler, opts, pending); err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// ingestOnce scans the directory, handling the files that have become stable.
+func ingestOnce(ctx context.Context, dir Path, handler func(Path) error, opts IngestOptions, pending map[string]ingestState) error {
+	entries, err := os.ReadDir(string(dir))
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	present := map[string]bool{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if strings.HasPrefix(name, ".") || !entry.Type().IsRegular() {
+			continue
+		}
+
+		info, err := entry.Info()
+
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		present[name] = true
+		state, seen := pending[name]
+
+		if !seen || state.size != info.Size() || !state.modTime.Equal(info.ModTime()) {
+			pending[name] = ingestState{size: info.Size(), modTime: info.ModTime(), since: now}
+			continue
+		}
+
+		p := dir.JoinPath(Path(name))
+
+		if now.Sub(state.since) < opts.StableFor {
+			continue
+		}
+
+		if p.inUse() {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		delete(pending, name)
+
+		if err = ingestFile(p, handler, opts); err != nil {
+			return err
+		}
+	}
+
+	for name := range pending {
+		if !present[name] {
+			delete(pending, name)
+		}
+	}
+
+	return nil
+}
+
+// ingestFile passes the file to the handler and moves it according to the result.
+func ingestFile(p Path, handler func(Path) error, opts IngestOptions) error {
+	handlerErr := handler(p)
+
+	if !p.lexists() {
+		return nil // the handler moved or removed it itself
+	}
+
+	if handlerErr == nil {
+		return os.Rename(string(p), string(uniquePath(opts.DoneDir.JoinPath(Path(p.Name())))))
+	}
+
+	failed := uniquePath(opts.FailedDir.JoinPath(Path(p.Name())))
+
+	if err := os.Rename(string(p), string(failed)); err != nil {
+		return err
+	}
+
+	return (failed + ".error").writeBytesAtomic([]byte(handlerErr.Error()+"\n"), 0644)
+}
@@ -0,0 +1,86 @@
+package pathlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIngest(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{{Path: "good.txt", Content: "good"}, {Path: "bad.txt", Content: "bad"}, {Path: ".partial", Content: "..."}})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	var mu sync.Mutex
+	var handled []string
+
+	handler := func(p Path) error {
+		mu.Lock()
+		handled = append(handled, p.Name())
+		mu.Unlock()
+
+		if data, _ := p.ReadBytes(); string(data) == "bad" {
+			return errors.New("cannot handle bad files")
+		}
+
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	opts := IngestOptions{PollInterval: 10 * time.Millisecond, StableFor: 30 * time.Millisecond}
+
+	if err = Ingest(ctx, dir, handler, opts); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Ingest to run until the context is done, received %v", err)
+	}
+
+	if len(handled) != 2 {
+		t.Errorf("Expected the two visible files to be handled once each, received %v", handled)
+	}
+
+	if !dir.JoinPath("done/good.txt").Exists() || !dir.JoinPath("failed/bad.txt").Exists() || !dir.JoinPath(".partial").Exists() {
+		t.Errorf("Expected good.txt in done, bad.txt in failed, and .partial left alone")
+	}
+
+	if data, _ := dir.JoinPath("failed/bad.txt.error").ReadBytes(); !strings.Contains(string(data), "cannot handle") {
+		t.Errorf("Expected the handler's error to be recorded, received %q", data)
+	}
+}
+
+func TestIngestWaitsForWrites(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := dir.Mkdir(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	p := dir.JoinPath("growing")
+	pending := map[string]ingestState{}
+	opts := IngestOptions{StableFor: time.Hour, DoneDir: dir.JoinPath("done"), FailedDir: dir.JoinPath("failed")}
+	handled := 0
+	handler := func(Path) error { handled++; return nil }
+
+	for i := 0; i < 3; i++ {
+		p.WriteBytes([]byte(strings.Repeat("x", i+1)))
+
+		if err := ingestOnce(context.Background(), dir, handler, opts, pending); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	if handled != 0 {
+		t.Errorf("A file still being written should not be handled")
+	}
+}
@@ -0,0 +1,154 @@
+package pathlib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// INI is an ordered representation of an INI file.  Keys that appear before the first section header belong to the section with an empty Name.
+type INI struct {
+	Sections []*INISection
+}
+
+// INISection is a named section of an INI file with its keys in file order.
+type INISection struct {
+	Name string
+	Keys []INIKey
+}
+
+// INIKey is a single key and value within an INISection.
+type INIKey struct {
+	Name  string
+	Value string
+}
+
+// Section returns the section with the given name, or nil if there is no such section.
+func (ini *INI) Section(name string) *INISection {
+	for _, section := range ini.Sections {
+		if section.Name == name {
+			return section
+		}
+	}
+
+	return nil
+}
+
+// AddSection returns the section with the given name, appending a new one if it does not already exist.
+func (ini *INI) AddSection(name string) *INISection {
+	if section := ini.Section(name); section != nil {
+		return section
+	}
+
+	section := &INISection{Name: name}
+	ini.Sections = append(ini.Sections, section)
+	return section
+}
+
+// Get returns the value of the key within the section, and whether it was found.
+func (s *INISection) Get(key string) (string, bool) {
+	for _, k := range s.Keys {
+		if k.Name == key {
+			return k.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Set updates the value of the key within the section, appending it if it does not already exist.
+func (s *INISection) Set(key, value string) {
+	for i := range s.Keys {
+		if s.Keys[i].Name == key {
+			s.Keys[i].Value = value
+			return
+		}
+	}
+
+	s.Keys = append(s.Keys, INIKey{Name: key, Value: value})
+}
+
+// Delete removes the key from the section, if present.
+func (s *INISection) Delete(key string) {
+	for i := range s.Keys {
+		if s.Keys[i].Name == key {
+			s.Keys = append(s.Keys[:i], s.Keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// ReadINI parses the INI file Path.  Lines starting with ";" or "#" are comments, keys may be separated from values by "=" or ":", and values may be wrapped in double quotes.
+func (p Path) ReadINI() (*INI, error) {
+	contents, err := p.ReadBytes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	ini := &INI{}
+	section := ini.AddSection("")
+
+	for i, line := range strings.Split(string(contents), "\n") {
+		line = strings.TrimSpace(line)
+
+		if len(line) == 0 || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("%s line %d: unterminated section header", p, i+1)
+			}
+
+			section = ini.AddSection(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+
+		sep := strings.IndexAny(line, "=:")
+
+		if sep <= 0 {
+			return nil, fmt.Errorf("%s line %d: expected key = value", p, i+1)
+		}
+
+		value := strings.TrimSpace(line[sep+1:])
+
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = value[1 : len(value)-1]
+		}
+
+		section.Set(strings.TrimSpace(line[:sep]), value)
+	}
+
+	if len(ini.Sections[0].Keys) == 0 {
+		ini.Sections = ini.Sections[1:]
+	}
+
+	return ini, nil
+}
+
+// WriteINI writes the INI structure to the Path, in order.
+func (p Path) WriteINI(ini *INI) error {
+	var builder strings.Builder
+
+	for i, section := range ini.Sections {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		if len(section.Name) > 0 {
+			builder.WriteString("[" + section.Name + "]\n")
+		}
+
+		for _, key := range section.Keys {
+			value := key.Value
+
+			if value != strings.TrimSpace(value) || strings.ContainsAny(value, ";#") {
+				value = `"` + value + `"`
+			}
+
+			builder.WriteString(key.Name + " = " + value + "\n")
+		}
+	}
+
+	return p.WriteBytes([]byte(builder.String()))
+}
@@ -0,0 +1,70 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestINIRoundTrip(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s.ini", randomString(20)))
+	err := p.WriteBytes([]byte("; global settings\nname = app\n\n[server]\nport: 8080\nhost = \" padded \"\n\n[client]\n# comment\nretries=3\n"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	ini, err := p.ReadINI()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if len(ini.Sections) != 3 || ini.Sections[1].Name != "server" || ini.Sections[2].Name != "client" {
+		t.Errorf("Sections parsed incorrectly: %+v", ini.Sections)
+	}
+
+	if host, _ := ini.Section("server").Get("host"); host != " padded " {
+		t.Errorf("Quoted value parsed incorrectly: %q", host)
+	}
+
+	ini.Section("server").Set("port", "9090")
+	ini.AddSection("extra").Set("key", "value")
+	ini.Section("client").Delete("retries")
+
+	err = p.WriteINI(ini)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	contents, err := p.ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	target := "name = app\n\n[server]\nport = 9090\nhost = \" padded \"\n\n[client]\n\n[extra]\nkey = value\n"
+
+	if string(contents) != target {
+		t.Errorf("WriteINI wrote %q, expected %q", contents, target)
+	}
+}
+
+func TestReadINIInvalid(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s.ini", randomString(20)))
+	err := p.WriteBytes([]byte("[broken\n"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	_, err = p.ReadINI()
+
+	if err == nil {
+		t.Errorf("ReadINI should fail for unterminated section headers")
+	}
+}
@@ -0,0 +1,9 @@
+//go:build linux && !(mips || mipsle || mips64 || mips64le || ppc64 || ppc64le)
+
+package pathlib
+
+// The direction bits of ioctl request numbers, as encoded by asm-generic/ioctl.h.
+const (
+	iocWrite = 0x40000000
+	iocRead  = 0x80000000
+)
@@ -0,0 +1,9 @@
+//go:build linux && (mips || mipsle || mips64 || mips64le || ppc64 || ppc64le)
+
+package pathlib
+
+// The direction bits of ioctl request numbers, which MIPS and PowerPC encode differently from asm-generic/ioctl.h.
+const (
+	iocRead  = 0x40000000
+	iocWrite = 0x80000000
+)
@@ -0,0 +1,357 @@
+//go:build linux && pathlib_iouring
+
+package pathlib
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	sysIOUringSetup = 425
+	sysIOUringEnter = 426
+
+	ioringOffSQRing     = 0
+	ioringOffCQRing     = 0x8000000
+	ioringOffSQEs       = 0x10000000
+	ioringFeatSingleMap = 1
+	ioringEnterGetEvent = 1
+	ioringOpRead        = 22
+	ioringOpWrite       = 23
+
+	uringSQESize   = 64
+	uringCQESize   = 16
+	uringChunkSize = 128 * 1024
+)
+
+// uringParams mirrors struct io_uring_params.
+type uringParams struct {
+	sqEntries    uint32
+	cqEntries    uint32
+	flags        uint32
+	sqThreadCPU  uint32
+	sqThreadIdle uint32
+	features     uint32
+	wqFD         uint32
+	resv         [3]uint32
+	sqOff        struct {
+		head, tail, ringMask, ringEntries, flags, dropped, array, resv1 uint32
+		userAddr                                                        uint64
+	}
+	cqOff struct {
+		head, tail, ringMask, ringEntries, overflow, cqes, flags, resv1 uint32
+		userAddr                                                        uint64
+	}
+}
+
+// IOUring is an experimental Filesystem for Linux that services ReadFile, WriteFile, and Copy through io_uring, submitting the reads and writes for many chunks of a file in a single system call.  All other operations are passed through to the OS.  It is only built with the pathlib_iouring build tag, and requires Linux 5.6 or newer.  Use it with PathOn, and Close it when done.
+type IOUring struct {
+	osFilesystem
+
+	mu     sync.Mutex
+	fd     int
+	params uringParams
+	rings  []byte
+	cqRing []byte
+	sqes   []byte
+}
+
+// uringOp is a single read or write submitted to the ring.
+type uringOp struct {
+	opcode uint8
+	fd     int
+	offset int64
+	buf    []byte
+	result int32
+}
+
+// NewIOUring creates an io_uring with room for entries operations per submission.
+func NewIOUring(entries uint32) (*IOUring, error) {
+	u := &IOUring{}
+	fd, _, errno := syscall.Syscall(sysIOUringSetup, uintptr(entries), uintptr(unsafe.Pointer(&u.params)), 0)
+
+	if errno != 0 {
+		return nil, os.NewSyscallError("io_uring_setup", errno)
+	}
+
+	u.fd = int(fd)
+	sqSize := int(u.params.sqOff.array + u.params.sqEntries*4)
+	cqSize := int(u.params.cqOff.cqes + u.params.cqEntries*uringCQESize)
+
+	if u.params.features&ioringFeatSingleMap != 0 && cqSize > sqSize {
+		sqSize = cqSize
+	}
+
+	var err error
+
+	if u.rings, err = syscall.Mmap(u.fd, ioringOffSQRing, sqSize, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED|syscall.MAP_POPULATE); err != nil {
+		u.Close()
+		return nil, os.NewSyscallError("mmap", err)
+	}
+
+	u.cqRing = u.rings
+
+	if u.params.features&ioringFeatSingleMap == 0 {
+		if u.cqRing, err = syscall.Mmap(u.fd, ioringOffCQRing, cqSize, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED|syscall.MAP_POPULATE); err != nil {
+			u.Close()
+			return nil, os.NewSyscallError("mmap", err)
+		}
+	}
+
+	if u.sqes, err = syscall.Mmap(u.fd, ioringOffSQEs, int(u.params.sqEntries)*uringSQESize, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED|syscall.MAP_POPULATE); err != nil {
+		u.Close()
+		return nil, os.NewSyscallError("mmap", err)
+	}
+
+	return u, nil
+}
+
+// Close releases the ring.
+func (u *IOUring) Close() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.sqes != nil {
+		syscall.Munmap(u.sqes)
+		u.sqes = nil
+	}
+
+	if u.cqRing != nil && &u.cqRing[0] != &u.rings[0] {
+		syscall.Munmap(u.cqRing)
+	}
+
+	u.cqRing = nil
+
+	if u.rings != nil {
+		syscall.Munmap(u.rings)
+		u.rings = nil
+	}
+
+	if u.fd > 0 {
+		err := syscall.Close(u.fd)
+		u.fd = -1
+		return err
+	}
+
+	return nil
+}
+
+func uint32At(ring []byte, offset uint32) *uint32 {
+	return (*uint32)(unsafe.Pointer(&ring[offset]))
+}
+
+// submit queues the operations, which must not outnumber the ring entries, and waits for all of them to complete.
+func (u *IOUring) submit(ops []uringOp) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.fd < 0 {
+		return fmt.Errorf("io_uring is closed")
+	}
+
+	sqMask := *uint32At(u.rings, u.params.sqOff.ringMask)
+	tail := atomic.LoadUint32(uint32At(u.rings, u.params.sqOff.tail))
+
+	for i := range ops {
+		index := (tail + uint32(i)) & sqMask
+		sqe := u.sqes[index*uringSQESize : (index+1)*uringSQESize]
+
+		for j := range sqe {
+			sqe[j] = 0
+		}
+
+		sqe[0] = ops[i].opcode
+		*(*int32)(unsafe.Pointer(&sqe[4])) = int32(ops[i].fd)
+		*(*uint64)(unsafe.Pointer(&sqe[8])) = uint64(ops[i].offset)
+		*(*uint64)(unsafe.Pointer(&sqe[16])) = uint64(uintptr(unsafe.Pointer(&ops[i].buf[0])))
+		*(*uint32)(unsafe.Pointer(&sqe[24])) = uint32(len(ops[i].buf))
+		*(*uint64)(unsafe.Pointer(&sqe[32])) = uint64(i)
+		*uint32At(u.rings, u.params.sqOff.array+index*4) = index
+	}
+
+	atomic.StoreUint32(uint32At(u.rings, u.params.sqOff.tail), tail+uint32(len(ops)))
+
+	cqMask := *uint32At(u.cqRing, u.params.cqOff.ringMask)
+	submitted := len(ops)
+
+	for completed := 0; completed < len(ops); {
+		_, _, errno := syscall.Syscall6(sysIOUringEnter, uintptr(u.fd), uintptr(submitted), 1, ioringEnterGetEvent, 0, 0)
+
+		if errno == syscall.EINTR {
+			continue
+		}
+
+		if errno != 0 {
+			return os.NewSyscallError("io_uring_enter", errno)
+		}
+
+		submitted = 0
+		head := atomic.LoadUint32(uint32At(u.cqRing, u.params.cqOff.head))
+		cqTail := atomic.LoadUint32(uint32At(u.cqRing, u.params.cqOff.tail))
+
+		for ; head != cqTail; head++ {
+			cqe := u.cqRing[u.params.cqOff.cqes+(head&cqMask)*uringCQESize:]
+			i := *(*uint64)(unsafe.Pointer(&cqe[0]))
+			ops[i].result = *(*int32)(unsafe.Pointer(&cqe[8]))
+			completed++
+		}
+
+		atomic.StoreUint32(uint32At(u.cqRing, u.params.cqOff.head), head)
+	}
+
+	runtime.KeepAlive(ops)
+	return nil
+}
+
+// batch returns how many chunk operations can be submitted at once.
+func (u *IOUring) batch() int {
+	return int(u.params.sqEntries)
+}
+
+// transfer reads or writes buf at offset through the ring in batches of chunks, finishing any short transfers synchronously.
+func (u *IOUring) transfer(opcode uint8, f *os.File, buf []byte, offset int64) error {
+	ops := make([]uringOp, 0, u.batch())
+
+	flush := func() error {
+		if len(ops) == 0 {
+			return nil
+		}
+
+		if err := u.submit(ops); err != nil {
+			return err
+		}
+
+		for _, op := range ops {
+			if op.result < 0 {
+				return &os.PathError{Op: "io_uring", Path: f.Name(), Err: syscall.Errno(-op.result)}
+			}
+
+			if rest := op.buf[op.result:]; len(rest) > 0 {
+				var err error
+
+				if opcode == ioringOpRead {
+					_, err = f.ReadAt(rest, op.offset+int64(op.result))
+				} else {
+					_, err = f.WriteAt(rest, op.offset+int64(op.result))
+				}
+
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		ops = ops[:0]
+		return nil
+	}
+
+	for start := 0; start < len(buf); start += uringChunkSize {
+		end := start + uringChunkSize
+
+		if end > len(buf) {
+			end = len(buf)
+		}
+
+		ops = append(ops, uringOp{opcode: opcode, fd: int(f.Fd()), offset: offset + int64(start), buf: buf[start:end]})
+
+		if len(ops) == cap(ops) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush()
+}
+
+// ReadFile reads the named file through the ring.
+func (u *IOUring) ReadFile(name string) ([]byte, error) {
+	f, err := os.Open(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	stat, err := f.Stat()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if stat.Size() == 0 || !stat.Mode().IsRegular() {
+		return os.ReadFile(name) // sizes of special files (eg. in /proc) are not known up front
+	}
+
+	buf := make([]byte, stat.Size())
+	return buf, u.transfer(ioringOpRead, f, buf, 0)
+}
+
+// WriteFile writes the data to the named file through the ring, creating it with perm if needed.
+func (u *IOUring) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+
+	if err != nil {
+		return err
+	}
+
+	if len(data) > 0 {
+		err = u.transfer(ioringOpWrite, f, data, 0)
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// Copy copies the file src to dst through the ring, reading and writing a batch of chunks per submission.
+func (u *IOUring) Copy(src, dst string) error {
+	in, err := os.Open(src)
+
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	stat, err := in.Stat()
+
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, stat.Mode().Perm())
+
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, u.batch()*uringChunkSize)
+
+	for offset := int64(0); offset < stat.Size() && err == nil; offset += int64(len(buf)) {
+		chunk := buf
+
+		if remaining := stat.Size() - offset; remaining < int64(len(chunk)) {
+			chunk = chunk[:remaining]
+		}
+
+		if err = u.transfer(ioringOpRead, in, chunk, offset); err == nil {
+			err = u.transfer(ioringOpWrite, out, chunk, offset)
+		}
+	}
+
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,63 @@
+//go:build linux && pathlib_iouring
+
+package pathlib
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestIOUring(t *testing.T) {
+	u, err := NewIOUring(8)
+
+	if err != nil {
+		t.Skip("io_uring is not available: " + err.Error())
+	}
+
+	defer u.Close()
+
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err = dir.Mkdir()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	// large enough to need several batches
+	data := bytes.Repeat([]byte("0123456789abcdef"), 200000)
+	src := PathOn(u, string(dir.JoinPath("src")))
+	err = src.WriteBytes(data)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	err = u.Copy(string(dir.JoinPath("src")), string(dir.JoinPath("dst")))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	read, err := PathOn(u, string(dir.JoinPath("dst"))).ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if !bytes.Equal(read, data) {
+		t.Errorf("Data read through io_uring does not match (%d bytes read)", len(read))
+	}
+
+	direct, err := dir.JoinPath("dst").ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if !bytes.Equal(direct, data) {
+		t.Errorf("Data written through io_uring does not match")
+	}
+}
@@ -0,0 +1,343 @@
+package pathlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	journalFile  = "journal.json"
+	progressFile = "progress"
+)
+
+// journalFiles matches the names of the files a Journal keeps in its directory (see file and marker).
+var journalFiles = regexp.MustCompile(`^(journal\.json|progress|(data|backup)-[0-9]+|backup-[0-9]+\.(tmp|existed|created))$`)
+
+// journalOp is a single recorded operation within a Journal.
+type journalOp struct {
+	Op     string      `json:"op"`
+	Path   Path        `json:"path"`
+	Target Path        `json:"target,omitempty"`
+	Perms  os.FileMode `json:"perms,omitempty"`
+	data   []byte
+}
+
+// Journal is a write-ahead log for multi-step filesystem edits.  Operations are queued with WriteBytes, Rename, Unlink, and Mkdir, then applied together by Commit.  The operations and a backup of everything they change are persisted in the journal directory before anything is modified, so if the process crashes part way through, the next NewJournal on the same directory reports it as Pending and the edit can be finished with Replay or undone with Rollback.
+type Journal struct {
+	dir     Path
+	ops     []journalOp
+	pending bool
+}
+
+// NewJournal opens the journal stored in dir, creating dir if needed.  If a previous commit was interrupted, the returned Journal is Pending.
+func NewJournal(dir Path) (*Journal, error) {
+	if !dir.Exists() {
+		if err := dir.Mkdir(); err != nil {
+			return nil, err
+		}
+	}
+
+	j := &Journal{dir: dir}
+	journal := dir.JoinPath(journalFile)
+
+	if !journal.Exists() {
+		return j, nil
+	}
+
+	contents, err := journal.ReadBytes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(contents, &j.ops); err != nil {
+		return nil, fmt.Errorf("Corrupt journal %s: %w", journal, err)
+	}
+
+	j.pending = true
+	return j, nil
+}
+
+// Pending returns true if the journal holds an interrupted commit that must be resolved with Replay or Rollback.
+func (j *Journal) Pending() bool {
+	return j.pending
+}
+
+// WriteBytes queues writing the data to the Path, with perms if the file is created.
+func (j *Journal) WriteBytes(p Path, data []byte, perms os.FileMode) error {
+	return j.queue(journalOp{Op: "write", Path: p, Perms: perms, data: data})
+}
+
+// Rename queues renaming the src Path to dst.
+func (j *Journal) Rename(src, dst Path) error {
+	return j.queue(journalOp{Op: "rename", Path: src, Target: dst})
+}
+
+// Unlink queues removing the file Path.
+func (j *Journal) Unlink(p Path) error {
+	return j.queue(journalOp{Op: "unlink", Path: p})
+}
+
+// Mkdir queues creating the directory Path.
+func (j *Journal) Mkdir(p Path) error {
+	return j.queue(journalOp{Op: "mkdir", Path: p})
+}
+
+func (j *Journal) queue(op journalOp) error {
+	if j.pending {
+		return fmt.Errorf("Journal %s has a pending commit; Replay or Rollback first", j.dir)
+	}
+
+	j.ops = append(j.ops, op)
+	return nil
+}
+
+// Commit persists the queued operations and applies them in order.  If an operation fails, the ones already applied are rolled back and the error is returned.
+func (j *Journal) Commit() error {
+	if j.pending {
+		return fmt.Errorf("Journal %s has a pending commit; Replay or Rollback first", j.dir)
+	}
+
+	for i, op := range j.ops {
+		if op.Op == "write" {
+			if err := j.file("data", i).writeBytesAtomic(op.data, 0600); err != nil {
+				return err
+			}
+		}
+	}
+
+	encoded, err := json.Marshal(j.ops)
+
+	if err != nil {
+		return err
+	}
+
+	// writing the journal file is the commit point
+	if err = j.dir.JoinPath(journalFile).writeBytesAtomic(encoded, 0600); err != nil {
+		return err
+	}
+
+	j.pending = true
+
+	if err = j.apply(0); err != nil {
+		if rollbackErr := j.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%v (rollback also failed: %v)", err, rollbackErr)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Replay finishes applying an interrupted commit.
+func (j *Journal) Replay() error {
+	if !j.pending {
+		return nil
+	}
+
+	return j.apply(j.progress())
+}
+
+// Rollback undoes every operation of an interrupted commit that may have been applied, restoring the backed up state.
+func (j *Journal) Rollback() error {
+	if !j.pending {
+		return nil
+	}
+
+	for i := j.progress(); i >= 0; i-- {
+		if i >= len(j.ops) {
+			continue
+		}
+
+		if err := j.undo(i); err != nil {
+			return err
+		}
+	}
+
+	return j.clear()
+}
+
+func (j *Journal) apply(start int) error {
+	for i := start; i < len(j.ops); i++ {
+		if err := j.setProgress(i); err != nil {
+			return err
+		}
+
+		if err := j.backup(i); err != nil {
+			return err
+		}
+
+		if err := j.redo(i); err != nil {
+			return err
+		}
+	}
+
+	return j.clear()
+}
+
+// backup saves the state the operation will change, unless a previous attempt already did.  Regular files are copied, and marker files record whether the target already existed or is about to be created.
+func (j *Journal) backup(i int) error {
+	op := j.ops[i]
+	backup := j.file("backup", i)
+
+	if backup.lexists() || j.marker(i, "existed").lexists() || j.marker(i, "created").lexists() {
+		return nil
+	}
+
+	target := op.target()
+
+	switch {
+	case op.Op != "mkdir" && target.IsFile():
+		perms, err := target.Permissions()
+
+		if err != nil {
+			return err
+		}
+
+		tmp := Path(string(backup) + ".tmp")
+
+		if err = copyFile(target, tmp, perms); err != nil {
+			return err
+		}
+
+		return tmp.Rename(backup)
+	case target.lexists():
+		return j.marker(i, "existed").Touch()
+	default:
+		return j.marker(i, "created").Touch()
+	}
+}
+
+// redo applies the operation, tolerating it having already been applied.
+func (j *Journal) redo(i int) error {
+	op := j.ops[i]
+
+	switch op.Op {
+	case "write":
+		data, err := j.file("data", i).ReadBytes()
+
+		if err != nil {
+			return err
+		}
+
+		return op.Path.writeBytesAtomic(data, op.Perms)
+	case "rename":
+		if !op.Path.lexists() && op.Target.lexists() {
+			return nil
+		}
+
+		return op.Path.Rename(op.Target)
+	case "unlink":
+		if !op.Path.lexists() {
+			return nil
+		}
+
+		return op.Path.Unlink()
+	case "mkdir":
+		if op.Path.IsDir() {
+			return nil
+		}
+
+		return op.Path.Mkdir()
+	}
+
+	return fmt.Errorf("Unknown journal operation %q", op.Op)
+}
+
+// undo reverses the operation, tolerating it never having been applied.
+func (j *Journal) undo(i int) error {
+	op := j.ops[i]
+	target := op.target()
+
+	if op.Op == "rename" && !op.Path.lexists() && op.Target.lexists() {
+		if err := op.Target.Rename(op.Path); err != nil {
+			return err
+		}
+	}
+
+	if backup := j.file("backup", i); backup.lexists() {
+		return backup.Rename(target)
+	}
+
+	if !j.marker(i, "created").lexists() {
+		return nil
+	}
+
+	switch {
+	case op.Op == "mkdir" && target.IsDir():
+		return target.Rmdir()
+	case op.Op == "write" && target.lexists():
+		return target.Unlink()
+	}
+
+	return nil
+}
+
+// target returns the Path whose previous state must be backed up before the operation.
+func (op journalOp) target() Path {
+	if op.Op == "rename" {
+		return op.Target
+	}
+
+	return op.Path
+}
+
+func (j *Journal) file(kind string, i int) Path {
+	return j.dir.JoinPath(Path(fmt.Sprintf("%s-%d", kind, i)))
+}
+
+func (j *Journal) marker(i int, kind string) Path {
+	return Path(fmt.Sprintf("%s.%s", j.file("backup", i), kind))
+}
+
+func (j *Journal) progress() int {
+	contents, err := j.dir.JoinPath(progressFile).ReadBytes()
+
+	if err != nil {
+		return 0
+	}
+
+	i, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+
+	if err != nil {
+		return 0
+	}
+
+	return i
+}
+
+func (j *Journal) setProgress(i int) error {
+	return j.dir.JoinPath(progressFile).writeBytesAtomic([]byte(strconv.Itoa(i)), 0600)
+}
+
+// clear removes the journal and its supporting files, leaving the Journal empty and ready for reuse.  Anything else in the directory is left alone.
+func (j *Journal) clear() error {
+	if err := j.dir.JoinPath(journalFile).Unlink(); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	entries, err := os.ReadDir(string(j.dir))
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !journalFiles.MatchString(entry.Name()) {
+			continue
+		}
+
+		if err := j.dir.JoinPath(Path(entry.Name())).Unlink(); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	j.ops = nil
+	j.pending = false
+	return nil
+}
@@ -0,0 +1,170 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func journalTestDir(t *testing.T) (Path, *Journal) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := dir.JoinPath("data").Mkdir()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	err = dir.JoinPath("data", "config").WriteBytes([]byte("old"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	j, err := NewJournal(dir.JoinPath("journal"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	return dir, j
+}
+
+func TestJournalCommit(t *testing.T) {
+	dir, j := journalTestDir(t)
+	defer dir.RmdirRecursive()
+
+	data := dir.JoinPath("data")
+	j.WriteBytes(data.JoinPath("config.new"), []byte("new"), 0644)
+	j.Rename(data.JoinPath("config.new"), data.JoinPath("config"))
+	j.Mkdir(data.JoinPath("sub"))
+
+	// files in the journal directory that it did not write are left alone
+	unrelated := dir.JoinPath("journal", "README")
+
+	if err := unrelated.WriteBytes([]byte("keep")); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	err := j.Commit()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	contents, err := data.JoinPath("config").ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if string(contents) != "new" || !data.JoinPath("sub").IsDir() || data.JoinPath("config.new").Exists() {
+		t.Errorf("Journal was not applied correctly")
+	}
+
+	count, err := dir.JoinPath("journal").CountEntries(false)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if count != 1 || !unrelated.Exists() || j.Pending() {
+		t.Errorf("Journal was not cleared after commit")
+	}
+}
+
+func TestJournalRollbackAfterFailure(t *testing.T) {
+	dir, j := journalTestDir(t)
+	defer dir.RmdirRecursive()
+
+	data := dir.JoinPath("data")
+	j.WriteBytes(data.JoinPath("config"), []byte("new"), 0644)
+	j.WriteBytes(data.JoinPath("created"), []byte("created"), 0644)
+	j.Rename(data.JoinPath("missing"), data.JoinPath("other")) // fails
+
+	err := j.Commit()
+
+	if err == nil {
+		t.Errorf("Commit should fail when an operation fails")
+	}
+
+	contents, err := data.JoinPath("config").ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if string(contents) != "old" || data.JoinPath("created").Exists() {
+		t.Errorf("Failed commit was not rolled back")
+	}
+}
+
+func TestJournalRecoverInterrupted(t *testing.T) {
+	dir, j := journalTestDir(t)
+	defer dir.RmdirRecursive()
+
+	data := dir.JoinPath("data")
+	config := string(data.JoinPath("config"))
+	sub := string(data.JoinPath("sub"))
+
+	// simulate a crash after the first operation was applied
+	j.dir.JoinPath(journalFile).WriteBytes([]byte(`[{"op":"write","path":"` + config + `"},{"op":"unlink","path":"` + config + `"},{"op":"mkdir","path":"` + sub + `"}]`))
+	j.file("backup", 0).WriteBytes([]byte("old"))
+	data.JoinPath("config").WriteBytes([]byte("new"))
+	j.setProgress(1)
+
+	recovered, err := NewJournal(j.dir)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if !recovered.Pending() {
+		t.Errorf("Interrupted journal should be pending")
+	}
+
+	err = recovered.Mkdir(data.JoinPath("other"))
+
+	if err == nil {
+		t.Errorf("Queueing operations should fail while a commit is pending")
+	}
+
+	err = recovered.Rollback()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	contents, err := data.JoinPath("config").ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if string(contents) != "old" || data.JoinPath("sub").Exists() || recovered.Pending() {
+		t.Errorf("Rollback did not restore the original state")
+	}
+}
+
+func TestJournalReplay(t *testing.T) {
+	dir, j := journalTestDir(t)
+	defer dir.RmdirRecursive()
+
+	data := dir.JoinPath("data")
+	j.dir.JoinPath(journalFile).WriteBytes([]byte(`[{"op":"mkdir","path":"` + string(data.JoinPath("sub")) + `"},{"op":"unlink","path":"` + string(data.JoinPath("config")) + `"}]`))
+	j.setProgress(1)
+
+	recovered, err := NewJournal(j.dir)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	err = recovered.Replay()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if data.JoinPath("config").Exists() || recovered.Pending() {
+		t.Errorf("Replay did not finish the interrupted commit")
+	}
+}
@@ -0,0 +1,100 @@
+package pathlib
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"iter"
+	"os"
+)
+
+// ReadJSONLines returns an iterator over the newline-delimited JSON values in the file Path.  Each line is decoded into v (unless v is nil) before it is yielded along with its raw form, so v can be reused as the target for every record.  Blank lines are skipped.  An error opening or reading the file, or decoding a line, is yielded once, and iteration stops after read errors but continues after decoding errors.
+func (p Path) ReadJSONLines(v any) iter.Seq2[json.RawMessage, error] {
+	return func(yield func(json.RawMessage, error) bool) {
+		f, err := os.Open(string(p))
+
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		defer f.Close()
+
+		reader := bufio.NewReader(f)
+
+		for {
+			line, err := reader.ReadBytes('\n')
+			line = bytes.TrimSpace(line)
+
+			if len(line) > 0 {
+				raw := json.RawMessage(line)
+				var decodeErr error
+
+				if v != nil {
+					decodeErr = json.Unmarshal(raw, v)
+				} else if !json.Valid(raw) {
+					decodeErr = json.Unmarshal(raw, new(any))
+				}
+
+				if !yield(raw, decodeErr) {
+					return
+				}
+			}
+
+			if err == io.EOF {
+				return
+			}
+
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
+
+// AppendJSONLine appends v to the file Path as a single line of JSON, creating the file if needed.  The line is written with a single append so that concurrent appenders do not interleave, and if the file does not end with a newline (eg. after an interrupted write), one is added first so the new record starts on its own line.
+func (p Path) AppendJSONLine(v any) error {
+	encoded, err := json.Marshal(v)
+
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(string(p), os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644)
+
+	if err != nil {
+		return err
+	}
+
+	stat, err := f.Stat()
+
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	line := append(encoded, '\n')
+
+	if stat.Size() > 0 {
+		last := make([]byte, 1)
+
+		if _, err = f.ReadAt(last, stat.Size()-1); err != nil {
+			f.Close()
+			return err
+		}
+
+		if last[0] != '\n' {
+			line = append([]byte{'\n'}, line...)
+		}
+	}
+
+	_, err = f.Write(line)
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,77 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+type testEvent struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestJSONLines(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s.jsonl", randomString(20)))
+	defer p.Unlink()
+
+	for i := 1; i <= 2; i++ {
+		err := p.AppendJSONLine(testEvent{ID: i, Name: fmt.Sprintf("event %d", i)})
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+	}
+
+	// simulate an interrupted write, which should not corrupt the next record
+	f, err := p.Open("w+")
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	f.Write([]byte(`{"id": 3, "na`))
+	f.Close()
+
+	err = p.AppendJSONLine(testEvent{ID: 4, Name: "event 4"})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	var event testEvent
+	var ids []int
+	errors := 0
+
+	for raw, err := range p.ReadJSONLines(&event) {
+		if err != nil {
+			errors++
+			continue
+		}
+
+		if len(raw) == 0 {
+			t.Errorf("Received empty raw message")
+		}
+
+		ids = append(ids, event.ID)
+	}
+
+	if fmt.Sprint(ids) != "[1 2 4]" || errors != 1 {
+		t.Errorf("Read ids %v with %d errors", ids, errors)
+	}
+}
+
+func TestReadJSONLinesMissing(t *testing.T) {
+	count := 0
+
+	for _, err := range Path("/foo/bar/baz/fjkdsalfjaklrejakfdsa").ReadJSONLines(nil) {
+		count++
+
+		if err == nil {
+			t.Errorf("Reading a missing file should yield an error")
+		}
+	}
+
+	if count != 1 {
+		t.Errorf("Expected a single error, received %d values", count)
+	}
+}
@@ -0,0 +1,82 @@
+package pathlib
+
+import (
+	"strings"
+)
+
+// KeyValueOptions controls how SetKeyValue reads and writes lines.  The zero value edits KEY=VALUE files with "#" comments.
+type KeyValueOptions struct {
+	// Separator is written between the key and value, eg. "=" (the default), " = " for sysctl.conf, or " " for sshd_config.  When reading, keys are always split at the first "=" or whitespace.
+	Separator string
+
+	// CommentPrefix marks comment lines, which are never modified.  Defaults to "#".
+	CommentPrefix string
+
+	// CaseInsensitive matches keys without regard to case, as sshd_config does.
+	CaseInsensitive bool
+}
+
+// SetKeyValue sets key to value within a line-based KEY=VALUE file, creating the file if needed.  Every existing line for the key is updated in place; if there is none, the setting is appended.  Comments and unrecognized lines are preserved, and the file is replaced atomically.
+func (p Path) SetKeyValue(key, value string, opts KeyValueOptions) error {
+	if len(opts.Separator) == 0 {
+		opts.Separator = "="
+	}
+
+	if len(opts.CommentPrefix) == 0 {
+		opts.CommentPrefix = "#"
+	}
+
+	var lines []string
+
+	if p.Exists() {
+		contents, err := p.ReadBytes()
+
+		if err != nil {
+			return err
+		}
+
+		if len(contents) > 0 {
+			lines = strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n")
+		}
+	}
+
+	setting := key + opts.Separator + value
+	found := false
+
+	for i, line := range lines {
+		existing, ok := opts.lineKey(line)
+
+		if !ok {
+			continue
+		}
+
+		if existing == key || (opts.CaseInsensitive && strings.EqualFold(existing, key)) {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			lines[i] = indent + setting
+			found = true
+		}
+	}
+
+	if !found {
+		lines = append(lines, setting)
+	}
+
+	return p.writeBytesAtomic([]byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// lineKey returns the key of a setting line, or false if the line is blank or a comment.
+func (opts KeyValueOptions) lineKey(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+
+	if len(line) == 0 || strings.HasPrefix(line, opts.CommentPrefix) {
+		return "", false
+	}
+
+	end := strings.IndexAny(line, "= \t")
+
+	if end < 0 {
+		return line, true
+	}
+
+	return line[:end], end > 0
+}
@@ -0,0 +1,86 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestSetKeyValue(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := p.WriteBytes([]byte("# sshd_config\nPort 22\n  permitrootlogin yes\n#PermitRootLogin no\nUnknown line here\n"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	err = os.Chmod(string(p), 0600)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	opts := KeyValueOptions{Separator: " ", CaseInsensitive: true}
+	err = p.SetKeyValue("PermitRootLogin", "no", opts)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	err = p.SetKeyValue("PasswordAuthentication", "no", opts)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	contents, err := p.ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	target := "# sshd_config\nPort 22\n  PermitRootLogin no\n#PermitRootLogin no\nUnknown line here\nPasswordAuthentication no\n"
+
+	if string(contents) != target {
+		t.Errorf("SetKeyValue wrote %q, expected %q", contents, target)
+	}
+
+	perms, err := p.Permissions()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if perms != 0600 {
+		t.Errorf("Permissions were not preserved: %s", perms)
+	}
+}
+
+func TestSetKeyValueNewFile(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s.conf", randomString(20)))
+	defer p.Unlink()
+
+	err := p.SetKeyValue("net.ipv4.ip_forward", "1", KeyValueOptions{Separator: " = "})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	err = p.SetKeyValue("net.ipv4.ip_forward", "0", KeyValueOptions{Separator: " = "})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	contents, err := p.ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if string(contents) != "net.ipv4.ip_forward = 0\n" {
+		t.Errorf("SetKeyValue wrote %q", contents)
+	}
+}
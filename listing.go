@@ -0,0 +1,175 @@
+package pathlib
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ListingFormat is a format ExportListing can write.
+type ListingFormat string
+
+const (
+	// ListingCSV writes a header row of field names followed by a row per file.
+	ListingCSV ListingFormat = "csv"
+
+	// ListingJSON writes a JSON object per file, one per line.
+	ListingJSON ListingFormat = "json"
+)
+
+// ListingField is an attribute ExportListing can write for each file.
+type ListingField string
+
+const (
+	FieldPath    ListingField = "path"
+	FieldName    ListingField = "name"
+	FieldType    ListingField = "type" // "file", "dir", "symlink", or "other"
+	FieldSize    ListingField = "size"
+	FieldMode    ListingField = "mode"  // eg. "-rw-r--r--"
+	FieldModTime ListingField = "mtime" // in RFC 3339 format
+	FieldSHA256  ListingField = "sha256"
+)
+
+// DefaultListingFields are written by ExportListing when no fields are given.
+var DefaultListingFields = []ListingField{FieldPath, FieldSize, FieldModTime}
+
+// ExportListing walks the directory Path and streams a record for everything beneath it to w, with the selected fields in order, for inventory jobs.  Symbolic links are not followed.  FieldSHA256 hashes the contents of regular files, and is empty for everything else.
+func (p Path) ExportListing(w io.Writer, format ListingFormat, fields ...ListingField) error {
+	if len(fields) == 0 {
+		fields = DefaultListingFields
+	}
+
+	for _, field := range fields {
+		switch field {
+		case FieldPath, FieldName, FieldType, FieldSize, FieldMode, FieldModTime, FieldSHA256:
+		default:
+			return fmt.Errorf("Unknown listing field %q", field)
+		}
+	}
+
+	var write func(values []any) error
+	var flush func() error
+
+	switch format {
+	case ListingCSV:
+		writer := csv.NewWriter(w)
+		header := make([]string, len(fields))
+
+		for i, field := range fields {
+			header[i] = string(field)
+		}
+
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+
+		write = func(values []any) error {
+			record := make([]string, len(values))
+
+			for i, value := range values {
+				record[i] = fmt.Sprint(value)
+			}
+
+			return writer.Write(record)
+		}
+
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	case ListingJSON:
+		writer := bufio.NewWriter(w)
+
+		write = func(values []any) error {
+			writer.WriteByte('{')
+
+			for i, value := range values {
+				if i > 0 {
+					writer.WriteByte(',')
+				}
+
+				key, _ := json.Marshal(string(fields[i]))
+				encoded, err := json.Marshal(value)
+
+				if err != nil {
+					return err
+				}
+
+				writer.Write(key)
+				writer.WriteByte(':')
+				writer.Write(encoded)
+			}
+
+			_, err := writer.WriteString("}\n")
+			return err
+		}
+
+		flush = writer.Flush
+	default:
+		return fmt.Errorf("Unknown listing format %q", format)
+	}
+
+	if !p.IsDir() {
+		return fmt.Errorf("ExportListing only works on directories: %s", p)
+	}
+
+	err := p.Walk(func(file Path, info os.FileInfo, err error) error {
+		if err != nil || file == p {
+			return err
+		}
+
+		values := make([]any, len(fields))
+
+		for i, field := range fields {
+			if values[i], err = listingValue(file, info, field); err != nil {
+				return err
+			}
+		}
+
+		return write(values)
+	})
+
+	if flushErr := flush(); err == nil {
+		err = flushErr
+	}
+
+	return err
+}
+
+func listingValue(p Path, info os.FileInfo, field ListingField) (any, error) {
+	switch field {
+	case FieldPath:
+		return string(p), nil
+	case FieldName:
+		return info.Name(), nil
+	case FieldType:
+		switch {
+		case info.Mode().IsRegular():
+			return "file", nil
+		case info.IsDir():
+			return "dir", nil
+		case info.Mode()&os.ModeSymlink != 0:
+			return "symlink", nil
+		}
+
+		return "other", nil
+	case FieldSize:
+		return info.Size(), nil
+	case FieldMode:
+		return info.Mode().String(), nil
+	case FieldModTime:
+		return info.ModTime().Format(time.RFC3339Nano), nil
+	case FieldSHA256:
+		if !info.Mode().IsRegular() {
+			return "", nil
+		}
+
+		return p.sha256Hex()
+	}
+
+	return nil, fmt.Errorf("Unknown listing field %q", field)
+}
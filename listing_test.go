@@ -0,0 +1,70 @@
+package pathlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExportListing(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	err := CreateTree(dir, TreeSpec{
+		{Path: "a,b.txt", Content: "hello"},
+		{Path: "sub/c.txt", Content: ""},
+		{Path: "link", Symlink: "sub"},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var csvOut bytes.Buffer
+
+	if err = dir.ExportListing(&csvOut, ListingCSV, FieldName, FieldType, FieldSize); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	expected := "name,type,size\n\"a,b.txt\",file,5\nlink,symlink,3\nsub,dir,"
+
+	if !strings.HasPrefix(csvOut.String(), expected) || !strings.HasSuffix(csvOut.String(), "c.txt,file,0\n") {
+		t.Errorf("Unexpected CSV listing %q", csvOut.String())
+	}
+
+	var jsonOut bytes.Buffer
+
+	if err = dir.ExportListing(&jsonOut, ListingJSON, FieldPath, FieldSHA256, FieldSize); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	lines := strings.Split(strings.TrimSpace(jsonOut.String()), "\n")
+
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], `{"path":`) {
+		t.Fatalf("Unexpected JSON listing %q", jsonOut.String())
+	}
+
+	var record struct {
+		Path   string `json:"path"`
+		SHA256 string `json:"sha256"`
+		Size   int64  `json:"size"`
+	}
+
+	if err = json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if record.Path != string(dir.JoinPath("a,b.txt")) || record.Size != 5 || record.SHA256 != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("Unexpected record %+v", record)
+	}
+
+	if err = dir.ExportListing(&jsonOut, "xml"); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+
+	if err = dir.ExportListing(&jsonOut, ListingCSV, "owner"); err == nil {
+		t.Errorf("Expected an error for an unknown field")
+	}
+}
@@ -0,0 +1,16 @@
+package pathlib
+
+import (
+	"errors"
+)
+
+// IsSharingViolation reports whether err came from a file being held open or locked by another process, such as ERROR_SHARING_VIOLATION on Windows or EWOULDBLOCK from a non-blocking lock on Unix.  Such errors are usually temporary, so callers can wait and retry.
+func IsSharingViolation(err error) bool {
+	for _, target := range sharingViolations {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
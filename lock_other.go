@@ -0,0 +1,13 @@
+//go:build !windows && !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package pathlib
+
+import "fmt"
+
+// sharingViolations is empty, since IsLocked is not supported here and not every such platform defines EWOULDBLOCK.
+var sharingViolations []error
+
+// IsLocked is not supported on this platform.
+func (p Path) IsLocked() (bool, error) {
+	return false, fmt.Errorf("IsLocked is not supported on this platform: %s", p)
+}
@@ -0,0 +1,33 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+var sharingViolations = []error{syscall.EWOULDBLOCK}
+
+// IsLocked returns true if another open file holds a flock(2) lock on the file Path.  Unix does not stop other processes from using open files, so only advisory flock locks are detected; POSIX fcntl locks are not.
+func (p Path) IsLocked() (bool, error) {
+	f, err := os.Open(string(p))
+
+	if err != nil {
+		return false, err
+	}
+
+	defer f.Close()
+
+	err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+
+	if err == syscall.EWOULDBLOCK {
+		return true, nil
+	}
+
+	if err != nil {
+		return false, &os.PathError{Op: "flock", Path: string(p), Err: err}
+	}
+
+	return false, syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,58 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestIsLocked(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := p.WriteBytes([]byte("data")); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	locked, err := p.IsLocked()
+
+	if err != nil || locked {
+		t.Errorf("Expected an unlocked file, received %v %v", locked, err)
+	}
+
+	f, err := os.Open(string(p))
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer f.Close()
+
+	if err = syscall.Flock(int(f.Fd()), syscall.LOCK_SH); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	locked, err = p.IsLocked()
+
+	if err != nil || !locked {
+		t.Errorf("Expected a locked file, received %v %v", locked, err)
+	}
+
+	if _, err = Path(string(p) + "-missing").IsLocked(); err == nil || IsSharingViolation(err) {
+		t.Errorf("Expected a not-found error, received %v", err)
+	}
+}
+
+func TestIsSharingViolation(t *testing.T) {
+	if !IsSharingViolation(&os.PathError{Op: "flock", Path: "x", Err: syscall.EWOULDBLOCK}) {
+		t.Errorf("EWOULDBLOCK should be a sharing violation")
+	}
+
+	if IsSharingViolation(os.ErrNotExist) {
+		t.Errorf("os.ErrNotExist should not be a sharing violation")
+	}
+}
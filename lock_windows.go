@@ -0,0 +1,32 @@
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+var sharingViolations = []error{
+	syscall.Errno(32), // ERROR_SHARING_VIOLATION
+	syscall.Errno(33), // ERROR_LOCK_VIOLATION
+}
+
+// IsLocked returns true if another process has the file Path open in a way that prevents opening it exclusively, which is what makes Windows refuse to delete, rename, or write to files that are in use.
+func (p Path) IsLocked() (bool, error) {
+	name, err := syscall.UTF16PtrFromString(string(p))
+
+	if err != nil {
+		return false, err
+	}
+
+	handle, err := syscall.CreateFile(name, syscall.GENERIC_READ, 0, nil, syscall.OPEN_EXISTING, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+
+	if err != nil {
+		if IsSharingViolation(err) {
+			return true, nil
+		}
+
+		return false, &os.PathError{Op: "open", Path: string(p), Err: err}
+	}
+
+	return false, syscall.CloseHandle(handle)
+}
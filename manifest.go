@@ -0,0 +1,221 @@
+package pathlib
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ManifestName is the name of the checksum manifest within a directory, in the format of sha256sum.
+const ManifestName = "MANIFEST.sha256"
+
+// ManifestSignatureName is the name of the detached signature of the manifest.
+const ManifestSignatureName = ManifestName + ".sig"
+
+// ErrManifestMismatch is wrapped by the errors returned when a directory does not match its manifest or signature.
+var ErrManifestMismatch = errors.New("directory does not match manifest")
+
+// WriteManifest writes a manifest of the SHA-256 checksums of every regular file within the directory Path (other than the manifest and its signature) to ManifestName in the directory.  The format cannot describe symbolic links or other special files, so the directory must not contain any.
+func (p Path) WriteManifest() error {
+	manifest, err := p.buildManifestOfFiles()
+
+	if err != nil {
+		return err
+	}
+
+	return p.JoinPath(ManifestName).writeBytesAtomic(manifest, 0644)
+}
+
+// WriteSignedManifest writes the manifest like WriteManifest, along with a detached ed25519 signature of it in ManifestSignatureName.
+func (p Path) WriteSignedManifest(key ed25519.PrivateKey) error {
+	manifest, err := p.buildManifestOfFiles()
+
+	if err != nil {
+		return err
+	}
+
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, manifest)) + "\n"
+
+	if err = p.JoinPath(ManifestName).writeBytesAtomic(manifest, 0644); err != nil {
+		return err
+	}
+
+	return p.JoinPath(ManifestSignatureName).writeBytesAtomic([]byte(signature), 0644)
+}
+
+// VerifyManifest checks the files within the directory Path against its manifest.  Files that are missing, modified, or not listed, and symbolic links or other special files, make it return an error wrapping ErrManifestMismatch.
+func (p Path) VerifyManifest() error {
+	manifest, err := p.JoinPath(ManifestName).ReadBytes()
+
+	if err != nil {
+		return err
+	}
+
+	return p.verifyManifest(manifest)
+}
+
+// VerifySignedManifest checks the manifest's detached signature against the public key, and then the files against the manifest, so the directory is known to be exactly what the key holder signed.
+func (p Path) VerifySignedManifest(pub ed25519.PublicKey) error {
+	manifest, err := p.JoinPath(ManifestName).ReadBytes()
+
+	if err != nil {
+		return err
+	}
+
+	encoded, err := p.JoinPath(ManifestSignatureName).ReadBytes()
+
+	if err != nil {
+		return err
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+
+	if err != nil || !ed25519.Verify(pub, manifest, signature) {
+		return fmt.Errorf("Invalid manifest signature for %s: %w", p, ErrManifestMismatch)
+	}
+
+	return p.verifyManifest(manifest)
+}
+
+// buildManifest returns the manifest of the regular files within the directory Path, along with the symbolic links and other special files, which it cannot describe.
+func (p Path) buildManifest() ([]byte, []Path, error) {
+	var manifest bytes.Buffer
+	var special []Path
+
+	err := p.Walk(func(file Path, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := file.RelativeTo(p)
+
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || rel == ManifestName || rel == ManifestSignatureName {
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			special = append(special, rel)
+			return nil
+		}
+
+		if strings.ContainsAny(string(rel), "\r\n") {
+			return fmt.Errorf("Cannot list %s in a manifest because its name contains a line break", file)
+		}
+
+		sum, err := file.sha256Hex()
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&manifest, "%s  %s\n", sum, filepath.ToSlash(string(rel)))
+		return nil
+	})
+
+	return manifest.Bytes(), special, err
+}
+
+// buildManifestOfFiles returns the manifest of the directory Path, or an error if it contains symbolic links or other special files, since a manifest that left them out would let them be swapped freely.
+func (p Path) buildManifestOfFiles() ([]byte, error) {
+	manifest, special, err := p.buildManifest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(special) > 0 {
+		return nil, fmt.Errorf("Cannot list %s in a manifest because it is not a regular file", p.JoinPath(special[0]))
+	}
+
+	return manifest, nil
+}
+
+func (p Path) verifyManifest(manifest []byte) error {
+	expected := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(manifest))
+
+	for scanner.Scan() {
+		sum, name, ok := strings.Cut(scanner.Text(), "  ")
+
+		if !ok || len(sum) != sha256.Size*2 {
+			return fmt.Errorf("Malformed manifest line in %s: %q", p.JoinPath(ManifestName), scanner.Text())
+		}
+
+		expected[name] = sum
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Malformed manifest %s: %w", p.JoinPath(ManifestName), err)
+	}
+
+	actual, special, err := p.buildManifest()
+
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+
+	for _, rel := range special {
+		problems = append(problems, "not a regular file "+filepath.ToSlash(string(rel)))
+	}
+
+	scanner = bufio.NewScanner(bytes.NewReader(actual))
+
+	for scanner.Scan() {
+		sum, name, _ := strings.Cut(scanner.Text(), "  ")
+
+		if want, ok := expected[name]; !ok {
+			problems = append(problems, "unlisted "+name)
+		} else if want != sum {
+			problems = append(problems, "modified "+name)
+		}
+
+		delete(expected, name)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	for name := range expected {
+		problems = append(problems, "missing "+name)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s (%s): %w", p, strings.Join(problems, ", "), ErrManifestMismatch)
+	}
+
+	return nil
+}
+
+func (p Path) sha256Hex() (string, error) {
+	f, err := os.Open(string(p))
+
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	h := sha256.New()
+
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
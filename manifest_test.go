@@ -0,0 +1,105 @@
+package pathlib
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := CreateTree(dir, TreeSpec{{Path: "a", Content: "a"}, {Path: "sub/b", Content: "b"}}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	if err := dir.WriteManifest(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	contents, err := dir.JoinPath(ManifestName).ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if !strings.HasSuffix(string(contents), "  sub/b\n") {
+		t.Errorf("Unexpected manifest:\n%s", contents)
+	}
+
+	if err = dir.VerifyManifest(); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	dir.JoinPath("a").WriteBytes([]byte("changed"))
+	dir.JoinPath("sub", "b").Unlink()
+	dir.JoinPath("c").Touch()
+	err = dir.VerifyManifest()
+
+	if !errors.Is(err, ErrManifestMismatch) {
+		t.Fatalf("Expected ErrManifestMismatch, received %v", err)
+	}
+
+	for _, problem := range []string{"modified a", "missing sub/b", "unlisted c"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("Expected %q in %v", problem, err)
+		}
+	}
+}
+
+func TestSignedManifest(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := CreateTree(dir, TreeSpec{{Path: "a", Content: "a"}}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	pub, key, err := ed25519.GenerateKey(nil)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if err = dir.WriteSignedManifest(key); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if err = dir.VerifySignedManifest(pub); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	// links cannot be described by the manifest, so one added to a signed tree must not pass
+	if err = os.Symlink("/etc/passwd", string(dir.JoinPath("link"))); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if err = dir.VerifySignedManifest(pub); !errors.Is(err, ErrManifestMismatch) {
+		t.Errorf("Expected a mismatch for an added symbolic link, received %v", err)
+	}
+
+	if err = dir.WriteSignedManifest(key); err == nil {
+		t.Errorf("Expected an error writing a manifest of a tree with a symbolic link")
+	}
+
+	dir.JoinPath("link").Unlink()
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+
+	if err = dir.VerifySignedManifest(otherPub); !errors.Is(err, ErrManifestMismatch) {
+		t.Errorf("Expected a signature error for the wrong key, received %v", err)
+	}
+
+	// rewriting the manifest without re-signing invalidates it
+	dir.JoinPath("b").Touch()
+	dir.WriteManifest()
+
+	if err = dir.VerifySignedManifest(pub); !errors.Is(err, ErrManifestMismatch) {
+		t.Errorf("Expected a signature error for an unsigned manifest, received %v", err)
+	}
+}
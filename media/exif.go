@@ -0,0 +1,169 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrNoExifTime is returned by ExifTime when no date is recorded in the EXIF data.
+var ErrNoExifTime = errors.New("no EXIF date")
+
+const (
+	exifTagDateTime         = 0x0132
+	exifTagExifIFD          = 0x8769
+	exifTagDateTimeOriginal = 0x9003
+	exifTimeLayout          = "2006:01:02 15:04:05"
+)
+
+// ExifTime returns the time a photo was taken from its EXIF data, preferring DateTimeOriginal over DateTime.  JPEG files and TIFF-based files (including most camera raw formats) are supported.  EXIF times carry no zone, so the time is returned in the local time zone.
+func ExifTime(r io.ReadSeeker) (time.Time, error) {
+	tiff, err := exifTIFF(r)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if len(tiff) < 8 {
+		return time.Time{}, ErrNoExifTime
+	}
+
+	var order binary.ByteOrder
+
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return time.Time{}, errors.New("invalid TIFF header")
+	}
+
+	ifd0 := readIFD(tiff, order, order.Uint32(tiff[4:8]))
+	value := ifd0[exifTagDateTime]
+
+	if offset, ok := ifd0[exifTagExifIFD]; ok && len(offset) == 4 {
+		exif := readIFD(tiff, order, order.Uint32(offset))
+
+		if original, ok := exif[exifTagDateTimeOriginal]; ok {
+			value = original
+		}
+	}
+
+	text := strings.TrimRight(string(value), "\x00 ")
+
+	if len(text) == 0 {
+		return time.Time{}, ErrNoExifTime
+	}
+
+	return time.ParseInLocation(exifTimeLayout, text, time.Local)
+}
+
+// exifTIFF returns the TIFF structure holding the EXIF data, which is the whole file for TIFF-based formats and the APP1 segment for JPEG.
+func exifTIFF(r io.ReadSeeker) ([]byte, error) {
+	header := make([]byte, 4)
+
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	if bytes.Equal(header, []byte("II*\x00")) || bytes.Equal(header, []byte("MM\x00*")) {
+		return io.ReadAll(io.LimitReader(r, 16<<20))
+	}
+
+	if !bytes.HasPrefix(header, []byte("\xff\xd8")) {
+		return nil, ErrUnknownFormat
+	}
+
+	if _, err := r.Seek(2, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	marker := make([]byte, 4)
+
+	for {
+		if _, err := io.ReadFull(r, marker); err != nil {
+			return nil, ErrNoExifTime
+		}
+
+		if marker[0] != 0xff || marker[1] == 0xda { // start of scan, no more metadata
+			return nil, ErrNoExifTime
+		}
+
+		size := int64(binary.BigEndian.Uint16(marker[2:4])) - 2
+
+		if size < 0 {
+			return nil, errors.New("invalid JPEG segment")
+		}
+
+		if marker[1] != 0xe1 {
+			if _, err := r.Seek(size, io.SeekCurrent); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		segment := make([]byte, size)
+
+		if _, err := io.ReadFull(r, segment); err != nil {
+			return nil, err
+		}
+
+		if bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:], nil
+		}
+	}
+}
+
+// readIFD returns the raw values of the ASCII and LONG entries in the IFD at offset, keyed by tag.
+func readIFD(tiff []byte, order binary.ByteOrder, offset uint32) map[uint16][]byte {
+	entries := make(map[uint16][]byte)
+
+	// bounds are checked in uint64, since the uint32 values can overflow int on 32-bit platforms
+	size := uint64(len(tiff))
+
+	if uint64(offset)+2 > size {
+		return entries
+	}
+
+	count := uint64(order.Uint16(tiff[offset:]))
+
+	for i := uint64(0); i < count; i++ {
+		start := uint64(offset) + 2 + i*12
+
+		if start+12 > size {
+			break
+		}
+
+		entry := tiff[start : start+12]
+		tag := order.Uint16(entry[0:2])
+		kind := order.Uint16(entry[2:4])
+		n := uint64(order.Uint32(entry[4:8]))
+
+		switch kind {
+		case 2: // ASCII
+			if n <= 4 {
+				entries[tag] = entry[8 : 8+n]
+				continue
+			}
+
+			valueOffset := uint64(order.Uint32(entry[8:12]))
+
+			if valueOffset+n <= size {
+				entries[tag] = tiff[valueOffset : valueOffset+n]
+			}
+		case 4: // LONG
+			entries[tag] = entry[8:12]
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,87 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// exifJPEG builds a minimal JPEG whose EXIF data records the DateTime and DateTimeOriginal.
+func exifJPEG(dateTime, original string) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("MM\x00*")
+	binary.Write(&tiff, binary.BigEndian, uint32(8))
+
+	// IFD0 at 8: DateTime and the Exif IFD pointer
+	binary.Write(&tiff, binary.BigEndian, uint16(2))
+	binary.Write(&tiff, binary.BigEndian, []uint16{exifTagDateTime, 2})
+	binary.Write(&tiff, binary.BigEndian, []uint32{20, 56})
+	binary.Write(&tiff, binary.BigEndian, []uint16{exifTagExifIFD, 4})
+	binary.Write(&tiff, binary.BigEndian, []uint32{1, 38})
+	binary.Write(&tiff, binary.BigEndian, uint32(0))
+
+	// Exif IFD at 38: DateTimeOriginal
+	binary.Write(&tiff, binary.BigEndian, uint16(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{exifTagDateTimeOriginal, 2})
+	binary.Write(&tiff, binary.BigEndian, []uint32{20, 76})
+	binary.Write(&tiff, binary.BigEndian, uint32(0))
+
+	tiff.WriteString(dateTime + "\x00")
+	tiff.WriteString(original + "\x00")
+
+	var jpeg bytes.Buffer
+	jpeg.WriteString("\xff\xd8\xff\xe1")
+	binary.Write(&jpeg, binary.BigEndian, uint16(2+6+tiff.Len()))
+	jpeg.WriteString("Exif\x00\x00")
+	jpeg.Write(tiff.Bytes())
+	jpeg.WriteString("\xff\xda\x00\x02\xff\xd9")
+	return jpeg.Bytes()
+}
+
+func TestExifTime(t *testing.T) {
+	data := exifJPEG("2021:01:01 00:00:00", "2020:06:15 13:45:30")
+	taken, err := ExifTime(bytes.NewReader(data))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	target := time.Date(2020, 6, 15, 13, 45, 30, 0, time.Local)
+
+	if !taken.Equal(target) {
+		t.Errorf("ExifTime returned %s, expected %s", taken, target)
+	}
+}
+
+func TestExifTimeMissing(t *testing.T) {
+	_, err := ExifTime(bytes.NewReader([]byte("\xff\xd8\xff\xda\x00\x02")))
+
+	if err != ErrNoExifTime {
+		t.Errorf("Expected ErrNoExifTime, received %v", err)
+	}
+}
+
+func TestExifTimeCorrupt(t *testing.T) {
+	var tiff bytes.Buffer
+	tiff.WriteString("MM\x00*")
+	binary.Write(&tiff, binary.BigEndian, uint32(8))
+
+	// DateTime with a count and offset far past the end, which would overflow int on 32-bit platforms
+	binary.Write(&tiff, binary.BigEndian, uint16(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{exifTagDateTime, 2})
+	binary.Write(&tiff, binary.BigEndian, []uint32{0xfffffff0, 0xfffffff0})
+	binary.Write(&tiff, binary.BigEndian, uint32(0))
+
+	if _, err := ExifTime(bytes.NewReader(tiff.Bytes())); err != ErrNoExifTime {
+		t.Errorf("Expected ErrNoExifTime, received %v", err)
+	}
+
+	// an IFD offset past the end
+	data := tiff.Bytes()
+	binary.BigEndian.PutUint32(data[4:8], 0xffffffff)
+
+	if _, err := ExifTime(bytes.NewReader(data)); err != ErrNoExifTime {
+		t.Errorf("Expected ErrNoExifTime, received %v", err)
+	}
+}
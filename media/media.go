@@ -0,0 +1,344 @@
+// Package media probes basic metadata (image dimensions and audio/video durations) by parsing file headers, without decoding the media or shelling out to external tools.
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	_ "image/gif"  // register GIF for image.DecodeConfig
+	_ "image/jpeg" // register JPEG for image.DecodeConfig
+	_ "image/png"  // register PNG for image.DecodeConfig
+	"io"
+	"time"
+)
+
+// Kind is the broad class of a media file.
+type Kind int
+
+const (
+	// Unknown is returned for files whose format is not recognized.
+	Unknown Kind = iota
+	// Image is a still image.
+	Image
+	// Audio is an audio-only file.
+	Audio
+	// Video is a file with a video track.
+	Video
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Image:
+		return "image"
+	case Audio:
+		return "audio"
+	case Video:
+		return "video"
+	}
+
+	return "unknown"
+}
+
+// Info is the metadata returned by Probe.  Fields that do not apply to the Kind, or that could not be determined, are left as zero.
+type Info struct {
+	Kind     Kind
+	Format   string
+	Width    int
+	Height   int
+	Duration time.Duration
+}
+
+// ErrUnknownFormat is returned by Probe when the format is not recognized.
+var ErrUnknownFormat = errors.New("unknown media format")
+
+// Probe reads the headers of r to determine its format and basic metadata.  Images in PNG, GIF, JPEG, BMP, and WebP formats, WAV and FLAC audio, and MP4/MOV (including M4A) containers are supported.
+func Probe(r io.ReadSeeker) (Info, error) {
+	header := make([]byte, 32)
+	n, err := io.ReadFull(r, header)
+
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return Info{}, err
+	}
+
+	header = header[:n]
+
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return Info{}, err
+	}
+
+	switch {
+	case bytes.HasPrefix(header, []byte("\x89PNG")), bytes.HasPrefix(header, []byte("GIF8")), bytes.HasPrefix(header, []byte("\xff\xd8")):
+		config, format, err := image.DecodeConfig(r)
+
+		if err != nil {
+			return Info{}, err
+		}
+
+		return Info{Kind: Image, Format: format, Width: config.Width, Height: config.Height}, nil
+	case bytes.HasPrefix(header, []byte("BM")) && len(header) >= 26:
+		width := int32(binary.LittleEndian.Uint32(header[18:22]))
+		height := int32(binary.LittleEndian.Uint32(header[22:26]))
+
+		if height < 0 {
+			height = -height // top-down bitmap
+		}
+
+		return Info{Kind: Image, Format: "bmp", Width: int(width), Height: int(height)}, nil
+	case len(header) >= 12 && string(header[0:4]) == "RIFF" && string(header[8:12]) == "WEBP":
+		return probeWebP(r)
+	case len(header) >= 12 && string(header[0:4]) == "RIFF" && string(header[8:12]) == "WAVE":
+		return probeWAV(r)
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return probeFLAC(header)
+	case len(header) >= 8 && string(header[4:8]) == "ftyp":
+		return probeMP4(r)
+	}
+
+	return Info{}, ErrUnknownFormat
+}
+
+func probeWAV(r io.ReadSeeker) (Info, error) {
+	info := Info{Kind: Audio, Format: "wav"}
+	var byteRate uint32
+	var dataSize uint32
+
+	_, err := r.Seek(12, io.SeekStart)
+
+	if err != nil {
+		return info, err
+	}
+
+	header := make([]byte, 8)
+
+	for byteRate == 0 || dataSize == 0 {
+		if _, err = io.ReadFull(r, header); err != nil {
+			break
+		}
+
+		size := binary.LittleEndian.Uint32(header[4:8])
+
+		switch string(header[0:4]) {
+		case "fmt ":
+			// only the start of the chunk is needed, and its size cannot be trusted to allocate
+			format := make([]byte, 12)
+
+			if size < 12 {
+				return info, errors.New("truncated WAV fmt chunk")
+			}
+
+			if _, err = io.ReadFull(r, format); err != nil {
+				return info, errors.New("truncated WAV fmt chunk")
+			}
+
+			byteRate = binary.LittleEndian.Uint32(format[8:12])
+			size -= 12
+		case "data":
+			dataSize = size
+		}
+
+		// chunks are padded to an even size
+		if _, err = r.Seek(int64(size)+int64(size%2), io.SeekCurrent); err != nil {
+			return info, err
+		}
+	}
+
+	if byteRate > 0 {
+		info.Duration = time.Duration(float64(dataSize) / float64(byteRate) * float64(time.Second))
+	}
+
+	return info, nil
+}
+
+func probeFLAC(header []byte) (Info, error) {
+	info := Info{Kind: Audio, Format: "flac"}
+
+	// the STREAMINFO block must come first: 4 byte marker, 4 byte block header, then 10 bytes before the sample fields
+	if len(header) < 26 || header[4]&0x7f != 0 {
+		return info, errors.New("missing FLAC STREAMINFO block")
+	}
+
+	fields := binary.BigEndian.Uint64(header[18:26])
+	sampleRate := fields >> 44
+	totalSamples := fields & 0xfffffffff
+
+	if sampleRate > 0 {
+		info.Duration = time.Duration(float64(totalSamples) / float64(sampleRate) * float64(time.Second))
+	}
+
+	return info, nil
+}
+
+func probeWebP(r io.ReadSeeker) (Info, error) {
+	info := Info{Kind: Image, Format: "webp"}
+	header := make([]byte, 30)
+
+	if _, err := io.ReadFull(r, header); err != nil {
+		return info, err
+	}
+
+	data := header[20:]
+
+	switch string(header[12:16]) {
+	case "VP8X":
+		info.Width = int(uint32(data[4])|uint32(data[5])<<8|uint32(data[6])<<16) + 1
+		info.Height = int(uint32(data[7])|uint32(data[8])<<8|uint32(data[9])<<16) + 1
+	case "VP8L":
+		bits := binary.LittleEndian.Uint32(data[1:5])
+		info.Width = int(bits&0x3fff) + 1
+		info.Height = int((bits>>14)&0x3fff) + 1
+	case "VP8 ":
+		info.Width = int(binary.LittleEndian.Uint16(data[6:8]) & 0x3fff)
+		info.Height = int(binary.LittleEndian.Uint16(data[8:10]) & 0x3fff)
+	}
+
+	return info, nil
+}
+
+// mp4Boxes calls fn with the type, offset, and size of the contents of each box between start and end.
+func mp4Boxes(r io.ReadSeeker, start, end int64, fn func(boxType string, offset, size int64) error) error {
+	header := make([]byte, 16)
+
+	for offset := start; offset+8 <= end; {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+
+		if _, err := io.ReadFull(r, header[:8]); err != nil {
+			return err
+		}
+
+		size := int64(binary.BigEndian.Uint32(header[0:4]))
+		headerSize := int64(8)
+
+		switch size {
+		case 0:
+			size = end - offset
+		case 1:
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return err
+			}
+
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+
+		if size < headerSize {
+			return errors.New("invalid MP4 box size")
+		}
+
+		if err := fn(string(header[4:8]), offset+headerSize, size-headerSize); err != nil {
+			return err
+		}
+
+		offset += size
+	}
+
+	return nil
+}
+
+func probeMP4(r io.ReadSeeker) (Info, error) {
+	info := Info{Kind: Audio, Format: "mp4"}
+	end, err := r.Seek(0, io.SeekEnd)
+
+	if err != nil {
+		return info, err
+	}
+
+	// read reads n bytes at offset within a box of size bytes, failing if they do not fit rather than reading past it
+	read := func(offset, size int64, n int) ([]byte, error) {
+		if int64(n) > size {
+			return nil, errors.New("truncated MP4 box")
+		}
+
+		data := make([]byte, n)
+
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		_, err := io.ReadFull(r, data)
+		return data, err
+	}
+
+	err = mp4Boxes(r, 0, end, func(boxType string, offset, size int64) error {
+		switch boxType {
+		case "ftyp":
+			brand, err := read(offset, size, 4)
+
+			if err != nil {
+				return err
+			}
+
+			if string(brand) == "qt  " {
+				info.Format = "mov"
+			}
+		case "moov":
+			return mp4Boxes(r, offset, offset+size, func(boxType string, offset, size int64) error {
+				switch boxType {
+				case "mvhd":
+					data, err := read(offset, size, 32)
+
+					if err != nil {
+						return err
+					}
+
+					var timescale, duration uint64
+
+					if data[0] == 1 {
+						timescale = uint64(binary.BigEndian.Uint32(data[20:24]))
+						duration = binary.BigEndian.Uint64(data[24:32])
+					} else {
+						timescale = uint64(binary.BigEndian.Uint32(data[12:16]))
+						duration = uint64(binary.BigEndian.Uint32(data[16:20]))
+					}
+
+					if timescale > 0 {
+						info.Duration = time.Duration(float64(duration) / float64(timescale) * float64(time.Second))
+					}
+				case "trak":
+					return mp4Boxes(r, offset, offset+size, func(boxType string, offset, size int64) error {
+						if boxType != "tkhd" {
+							return nil
+						}
+
+						version, err := read(offset, size, 1)
+
+						if err != nil {
+							return err
+						}
+
+						dimensions := offset + 76
+
+						if version[0] == 1 {
+							dimensions = offset + 88
+						}
+
+						data, err := read(dimensions, offset+size-dimensions, 8)
+
+						if err != nil {
+							return err
+						}
+
+						width := int(binary.BigEndian.Uint32(data[0:4]) >> 16)
+						height := int(binary.BigEndian.Uint32(data[4:8]) >> 16)
+
+						if width > 0 && height > 0 {
+							info.Kind = Video
+							info.Width = width
+							info.Height = height
+						}
+
+						return nil
+					})
+				}
+
+				return nil
+			})
+		}
+
+		return nil
+	})
+
+	return info, err
+}
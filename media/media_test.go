@@ -0,0 +1,108 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestProbePNG(t *testing.T) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 64, 48)))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	info, err := Probe(bytes.NewReader(buf.Bytes()))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if info.Kind != Image || info.Format != "png" || info.Width != 64 || info.Height != 48 {
+		t.Errorf("Incorrect PNG info: %+v", info)
+	}
+}
+
+func TestProbeWAV(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+16000))
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, []uint16{1, 1})       // PCM, mono
+	binary.Write(&buf, binary.LittleEndian, []uint32{8000, 8000}) // sample rate, byte rate
+	binary.Write(&buf, binary.LittleEndian, []uint16{1, 8})       // block align, bits per sample
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(16000))
+	buf.Write(make([]byte, 16000))
+
+	info, err := Probe(bytes.NewReader(buf.Bytes()))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if info.Kind != Audio || info.Format != "wav" || info.Duration != 2*time.Second {
+		t.Errorf("Incorrect WAV info: %+v", info)
+	}
+}
+
+func TestProbeWAVHostileChunkSize(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("RIFFxxxxWAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(0xffffffff))
+	binary.Write(&buf, binary.LittleEndian, []uint16{1, 1})
+	binary.Write(&buf, binary.LittleEndian, []uint32{8000, 8000})
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	info, _ := Probe(bytes.NewReader(buf.Bytes()))
+	runtime.ReadMemStats(&after)
+
+	if info.Format != "wav" || after.TotalAlloc-before.TotalAlloc > 1<<20 {
+		t.Errorf("Probing a chunk claiming 4GB allocated %d bytes: %+v", after.TotalAlloc-before.TotalAlloc, info)
+	}
+}
+
+func mp4Box(boxType string, contents ...[]byte) []byte {
+	body := bytes.Join(contents, nil)
+	box := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(box[0:4], uint32(8+len(body)))
+	copy(box[4:8], boxType)
+	return append(box, body...)
+}
+
+func TestProbeMP4(t *testing.T) {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)  // timescale
+	binary.BigEndian.PutUint32(mvhd[16:20], 90500) // duration
+
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:80], 1920<<16)
+	binary.BigEndian.PutUint32(tkhd[80:84], 1080<<16)
+
+	file := append(mp4Box("ftyp", []byte("isom\x00\x00\x00\x00")), mp4Box("moov", mp4Box("mvhd", mvhd), mp4Box("trak", mp4Box("tkhd", tkhd)))...)
+	info, err := Probe(bytes.NewReader(file))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if info.Kind != Video || info.Width != 1920 || info.Height != 1080 || info.Duration != 90500*time.Millisecond {
+		t.Errorf("Incorrect MP4 info: %+v", info)
+	}
+}
+
+func TestProbeUnknown(t *testing.T) {
+	_, err := Probe(bytes.NewReader([]byte("plain text")))
+
+	if err != ErrUnknownFormat {
+		t.Errorf("Expected ErrUnknownFormat, received %v", err)
+	}
+}
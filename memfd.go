@@ -0,0 +1,16 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+)
+
+// MemFile is an anonymous file held in memory, created by Memfd.  It never touches a disk and is freed once every descriptor for it is closed.
+type MemFile struct {
+	*os.File
+}
+
+// Path returns a Path through which the MemFile can be opened while it is open, including by child processes that are passed it as an argument, since it names this process's descriptor under /proc.
+func (m *MemFile) Path() Path {
+	return Path(fmt.Sprintf("/proc/%d/fd/%d", os.Getpid(), m.Fd()))
+}
@@ -0,0 +1,58 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// memfd_create is numbered differently on each architecture
+var sysMemfdCreate = map[string]uintptr{
+	"386":      356,
+	"amd64":    319,
+	"arm":      385,
+	"arm64":    279,
+	"loong64":  279,
+	"mips":     4354,
+	"mipsle":   4354,
+	"mips64":   5314,
+	"mips64le": 5314,
+	"ppc64":    360,
+	"ppc64le":  360,
+	"riscv64":  279,
+	"s390x":    350,
+}
+
+const mfdCloexec = 0x1
+
+// Memfd creates an anonymous in-memory file with memfd_create, sized to size bytes (which may be zero).  The name is only a label, shown in /proc; it need not be unique.
+func Memfd(name string, size int64) (*MemFile, error) {
+	trap, ok := sysMemfdCreate[runtime.GOARCH]
+
+	if !ok {
+		return nil, fmt.Errorf("Memfd is not supported on %s", runtime.GOARCH)
+	}
+
+	label, err := syscall.BytePtrFromString(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fd, _, errno := syscall.Syscall(trap, uintptr(unsafe.Pointer(label)), mfdCloexec, 0)
+
+	if errno != 0 {
+		return nil, os.NewSyscallError("memfd_create", errno)
+	}
+
+	f := os.NewFile(fd, "memfd:"+name)
+
+	if err = f.Truncate(size); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &MemFile{f}, nil
+}
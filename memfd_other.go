@@ -0,0 +1,12 @@
+//go:build !linux
+
+package pathlib
+
+import (
+	"fmt"
+)
+
+// Memfd is only supported on Linux.
+func Memfd(name string, size int64) (*MemFile, error) {
+	return nil, fmt.Errorf("Memfd is not supported on this platform: %s", name)
+}
@@ -0,0 +1,47 @@
+package pathlib
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+func TestMemfd(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Memfd is only supported on Linux")
+	}
+
+	m, err := Memfd("pathlib-test", 4)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer m.Close()
+
+	if info, _ := m.Stat(); info.Size() != 4 {
+		t.Errorf("Expected a size of 4, received %d", info.Size())
+	}
+
+	if _, err = m.WriteAt([]byte("memory"), 0); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	contents, err := m.Path().ReadBytes()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if string(contents) != "memory" {
+		t.Errorf("Expected %q, received %q", "memory", contents)
+	}
+
+	if _, err = exec.LookPath("cat"); err == nil {
+		output, err := exec.Command("cat", string(m.Path())).Output()
+
+		if err != nil || string(output) != "memory" {
+			t.Errorf("A child process could not read %s: %q %v", m.Path(), output, err)
+		}
+	}
+}
@@ -0,0 +1,422 @@
+package pathlib
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemFilesystem is a Filesystem held entirely in memory, for unit testing code that uses Paths without touching the disk.  Names follow the io/fs rules (unrooted, slash-separated, and clean).  It is safe for concurrent use.
+type MemFilesystem struct {
+	mu       sync.RWMutex
+	files    map[string]*memFile
+	watchers map[*memWatcher]bool
+}
+
+type memWatcher struct {
+	name   string
+	events chan string
+}
+
+type memFile struct {
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// NewMemFilesystem returns an empty in-memory Filesystem.
+func NewMemFilesystem() *MemFilesystem {
+	return &MemFilesystem{files: map[string]*memFile{
+		".": {mode: fs.ModeDir | 0755, modTime: time.Now()},
+	}}
+}
+
+func (m *MemFilesystem) lookup(op, name string) (string, *memFile, error) {
+	if !fs.ValidPath(name) {
+		return name, nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	f, ok := m.files[name]
+
+	if !ok {
+		return name, nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	return name, f, nil
+}
+
+// Open opens the named file or directory for reading.
+func (m *MemFilesystem) Open(name string) (fs.File, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name, f, err := m.lookup("open", name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	info := memFileInfo{name: path.Base(name), file: *f}
+
+	if f.mode.IsDir() {
+		entries, _ := m.readDir(name)
+		return &memDir{info: info, entries: entries}, nil
+	}
+
+	return &memOpenFile{info: info, Reader: bytes.NewReader(f.data)}, nil
+}
+
+// Stat returns the FileInfo of the named file.
+func (m *MemFilesystem) Stat(name string) (fs.FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name, f, err := m.lookup("stat", name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return memFileInfo{name: path.Base(name), file: *f}, nil
+}
+
+// ReadDir returns the entries of the named directory, sorted by name.
+func (m *MemFilesystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name, f, err := m.lookup("readdir", name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !f.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return m.readDir(name)
+}
+
+func (m *MemFilesystem) readDir(name string) ([]fs.DirEntry, error) {
+	var entries []fs.DirEntry
+
+	for child, f := range m.files {
+		if child != "." && path.Dir(child) == name {
+			entries = append(entries, fs.FileInfoToDirEntry(memFileInfo{name: path.Base(child), file: *f}))
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// ReadFile returns the contents of the named file.
+func (m *MemFilesystem) ReadFile(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name, f, err := m.lookup("read", name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if f.mode.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return append([]byte(nil), f.data...), nil
+}
+
+// WriteFile writes the data to the named file, creating it with perm if needed.  The parent directory must exist.
+func (m *MemFilesystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if _, parent, err := m.lookup("write", path.Dir(name)); err != nil || !parent.mode.IsDir() {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrNotExist}
+	}
+
+	if f, ok := m.files[name]; ok {
+		if f.mode.IsDir() {
+			return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+		}
+
+		perm = f.mode.Perm()
+	}
+
+	m.files[name] = &memFile{data: append([]byte(nil), data...), mode: perm.Perm(), modTime: time.Now()}
+	m.notify(name)
+	return nil
+}
+
+// MkdirAll creates the named directory along with any missing parents.
+func (m *MemFilesystem) MkdirAll(name string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	for dir := name; dir != "."; dir = path.Dir(dir) {
+		if f, ok := m.files[dir]; ok {
+			if !f.mode.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: dir, Err: fs.ErrExist}
+			}
+
+			continue
+		}
+
+		m.files[dir] = &memFile{mode: fs.ModeDir | perm.Perm(), modTime: time.Now()}
+		m.notify(dir)
+	}
+
+	return nil
+}
+
+// Remove removes the named file or empty directory.
+func (m *MemFilesystem) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name, f, err := m.lookup("remove", name)
+
+	if err != nil {
+		return err
+	}
+
+	if f.mode.IsDir() {
+		if entries, _ := m.readDir(name); len(entries) > 0 {
+			return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrExist}
+		}
+	}
+
+	delete(m.files, name)
+	m.notify(name)
+	return nil
+}
+
+// RemoveAll removes the named file or directory and everything within it.
+func (m *MemFilesystem) RemoveAll(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	}
+
+	for child := range m.files {
+		if child == name || strings.HasPrefix(child, name+"/") {
+			delete(m.files, child)
+			m.notify(child)
+		}
+	}
+
+	return nil
+}
+
+// Rename moves the named file or directory (with its contents) to newname.  An existing file at newname is replaced, as is an empty directory when moving a directory.
+func (m *MemFilesystem) Rename(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	oldname, source, err := m.lookup("rename", oldname)
+
+	if err != nil {
+		return err
+	}
+
+	if !fs.ValidPath(newname) || oldname == "." || newname == "." {
+		return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrInvalid}
+	}
+
+	if _, parent, err := m.lookup("rename", path.Dir(newname)); err != nil || !parent.mode.IsDir() {
+		return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrNotExist}
+	}
+
+	if strings.HasPrefix(newname, oldname+"/") {
+		return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrInvalid} // within itself
+	}
+
+	// as with os.Rename, an existing target is replaced only by the same kind of entry, and only if it is a file or an empty directory
+	if target, ok := m.files[newname]; ok && newname != oldname {
+		if target.mode.IsDir() != source.mode.IsDir() {
+			return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrExist}
+		}
+
+		if entries, _ := m.readDir(newname); target.mode.IsDir() && len(entries) > 0 {
+			return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrExist}
+		}
+
+		delete(m.files, newname)
+		m.notify(newname)
+	}
+
+	// collected first, since adding to a map while ranging over it may visit the new keys
+	var moving []string
+
+	for child := range m.files {
+		if child == oldname || strings.HasPrefix(child, oldname+"/") {
+			moving = append(moving, child)
+		}
+	}
+
+	moved := map[string]*memFile{}
+
+	for _, child := range moving {
+		moved[newname+strings.TrimPrefix(child, oldname)] = m.files[child]
+		delete(m.files, child)
+		m.notify(child)
+	}
+
+	for name, f := range moved {
+		m.files[name] = f
+		m.notify(name)
+	}
+
+	return nil
+}
+
+// Chmod changes the permissions of the named file or directory.
+func (m *MemFilesystem) Chmod(name string, mode fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name, f, err := m.lookup("chmod", name)
+
+	if err != nil {
+		return err
+	}
+
+	f.mode = f.mode&fs.ModeType | mode.Perm()
+	m.notify(name)
+	return nil
+}
+
+// Chtimes changes the modification time of the named file or directory.  Access times are not tracked.
+func (m *MemFilesystem) Chtimes(name string, atime, mtime time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name, f, err := m.lookup("chtimes", name)
+
+	if err != nil {
+		return err
+	}
+
+	f.modTime = mtime
+	return nil
+}
+
+// Watch reports changes at or beneath the named file or directory.  Events are buffered, but dropped if the receiver falls far behind.  The channel is closed when ctx is done.
+func (m *MemFilesystem) Watch(ctx context.Context, name string) (<-chan string, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "watch", Path: name, Err: fs.ErrInvalid}
+	}
+
+	w := &memWatcher{name: name, events: make(chan string, 64)}
+	m.mu.Lock()
+
+	if m.watchers == nil {
+		m.watchers = make(map[*memWatcher]bool)
+	}
+
+	m.watchers[w] = true
+	m.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		m.mu.Lock()
+		delete(m.watchers, w)
+		close(w.events)
+		m.mu.Unlock()
+	}()
+
+	return w.events, nil
+}
+
+// notify sends the name to every watcher that covers it.  The lock must be held.
+func (m *MemFilesystem) notify(name string) {
+	for w := range m.watchers {
+		if w.name == "." || name == w.name || strings.HasPrefix(name, w.name+"/") {
+			select {
+			case w.events <- name:
+			default:
+			}
+		}
+	}
+}
+
+// Glob returns the names of the files matching the pattern.
+func (m *MemFilesystem) Glob(pattern string) ([]string, error) {
+	return fs.Glob(fsOnly{m}, pattern)
+}
+
+// fsOnly hides the Glob method of a filesystem so fs.Glob does not recurse into it.
+type fsOnly struct {
+	fs.ReadDirFS
+}
+
+type memFileInfo struct {
+	name string
+	file memFile
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return int64(len(i.file.data)) }
+func (i memFileInfo) Mode() fs.FileMode  { return i.file.mode }
+func (i memFileInfo) ModTime() time.Time { return i.file.modTime }
+func (i memFileInfo) IsDir() bool        { return i.file.mode.IsDir() }
+func (i memFileInfo) Sys() any           { return nil }
+
+type memOpenFile struct {
+	*bytes.Reader
+	info memFileInfo
+}
+
+func (f *memOpenFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memOpenFile) Close() error               { return nil }
+
+type memDir struct {
+	info    memFileInfo
+	entries []fs.DirEntry
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > len(d.entries) {
+		n = len(d.entries)
+	}
+
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
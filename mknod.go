@@ -0,0 +1,24 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+)
+
+// File type bits for mknod, which share the same values on Linux, macOS, and the BSDs.
+const (
+	modeCharDevice  = 0020000
+	modeBlockDevice = 0060000
+)
+
+// mknodMode converts an os.FileMode into the mode expected by mknod.  Character devices are os.ModeDevice|os.ModeCharDevice and block devices are os.ModeDevice.
+func mknodMode(mode os.FileMode) (uint32, error) {
+	switch {
+	case mode&os.ModeDevice != 0 && mode&os.ModeCharDevice != 0:
+		return modeCharDevice | uint32(mode.Perm()), nil
+	case mode&os.ModeDevice != 0:
+		return modeBlockDevice | uint32(mode.Perm()), nil
+	}
+
+	return 0, fmt.Errorf("Mknod mode %s is not a character or block device", mode)
+}
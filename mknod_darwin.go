@@ -0,0 +1,35 @@
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+// Mknod creates a device node at the Path.  The mode must include os.ModeDevice, plus os.ModeCharDevice for a character device, along with the permissions.  Creating device nodes usually requires root privileges.
+func (p Path) Mknod(mode os.FileMode, major, minor uint32) error {
+	m, err := mknodMode(mode)
+
+	if err != nil {
+		return err
+	}
+
+	dev := int(major<<24 | minor&0xffffff)
+
+	if err = syscall.Mknod(string(p), m, dev); err != nil {
+		return &os.PathError{Op: "mknod", Path: string(p), Err: err}
+	}
+
+	return nil
+}
+
+// deviceNumbers returns the major and minor numbers of the device node the FileInfo describes.
+func deviceNumbers(info os.FileInfo) (uint32, uint32, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+
+	if !ok {
+		return 0, 0, false
+	}
+
+	dev := uint32(stat.Rdev)
+	return dev >> 24, dev & 0xffffff, true
+}
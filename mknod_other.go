@@ -0,0 +1,22 @@
+//go:build !linux && !darwin
+
+package pathlib
+
+import (
+	"fmt"
+	"os"
+)
+
+// Mknod is not supported on this platform.
+func (p Path) Mknod(mode os.FileMode, major, minor uint32) error {
+	if _, err := mknodMode(mode); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("Mknod is not supported on this platform: %s", p)
+}
+
+// deviceNumbers reports false, since device nodes cannot be created on this platform.
+func deviceNumbers(info os.FileInfo) (uint32, uint32, bool) {
+	return 0, 0, false
+}
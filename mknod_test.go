@@ -0,0 +1,41 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestMknod(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := p.Mknod(os.ModeDevice|os.ModeCharDevice|0600, 1, 3) // /dev/null
+
+	if os.IsPermission(err) {
+		t.Skip("Creating device nodes requires privileges")
+	}
+
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	defer p.Unlink()
+
+	stat, err := os.Stat(string(p))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if stat.Mode()&os.ModeCharDevice == 0 {
+		t.Errorf("%s is not a character device: %s", p, stat.Mode())
+	}
+}
+
+func TestMknodInvalidMode(t *testing.T) {
+	err := Path("/tmp/pathlib-invalid").Mknod(0600, 1, 3)
+
+	if err == nil {
+		t.Errorf("Mknod should fail for modes that are not devices")
+	}
+}
@@ -0,0 +1,97 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MultiError collects the errors of a bulk operation that carries on past failures, such as CopyTree, SyncTo, or RmdirRecursive, keyed by the Path each happened at.  Use errors.As to get it from their results.  Like the errors.Join result, it matches any error it contains with errors.Is and errors.As.
+type MultiError map[Path]error
+
+func (m MultiError) Error() string {
+	paths := m.Paths()
+
+	if len(paths) == 1 {
+		return m[paths[0]].Error()
+	}
+
+	messages := make([]string, len(paths))
+
+	for i, p := range paths {
+		messages[i] = m[p].Error()
+	}
+
+	return fmt.Sprintf("%d errors: %s", len(paths), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors, ordered by Path.
+func (m MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(m))
+
+	for _, p := range m.Paths() {
+		errs = append(errs, m[p])
+	}
+
+	return errs
+}
+
+// Paths returns the Paths that had errors, sorted.
+func (m MultiError) Paths() []Path {
+	paths := make([]Path, 0, len(m))
+
+	for p := range m {
+		paths = append(paths, p)
+	}
+
+	sort.Slice(paths, func(i, j int) bool { return paths[i] < paths[j] })
+	return paths
+}
+
+// Filter returns the errors that match target with errors.Is, such as os.ErrPermission or syscall.ENOSPC.
+func (m MultiError) Filter(target error) MultiError {
+	return m.FilterFunc(func(err error) bool { return errors.Is(err, target) })
+}
+
+// Exclude returns the errors that do not match target with errors.Is, such as all but the os.ErrNotExist errors of files removed during the operation.
+func (m MultiError) Exclude(target error) MultiError {
+	return m.FilterFunc(func(err error) bool { return !errors.Is(err, target) })
+}
+
+// FilterFunc returns the errors for which fn returns true.
+func (m MultiError) FilterFunc(fn func(error) bool) MultiError {
+	filtered := MultiError{}
+
+	for p, err := range m {
+		if fn(err) {
+			filtered[p] = err
+		}
+	}
+
+	return filtered
+}
+
+// ErrorOrNil returns the MultiError, or nil if it is empty, so that a filtered MultiError can be returned as an error.
+func (m MultiError) ErrorOrNil() error {
+	if len(m) == 0 {
+		return nil
+	}
+
+	return m
+}
+
+// add records the error at the Path, merging in the errors of a nested MultiError.
+func (m MultiError) add(p Path, err error) {
+	var nested MultiError
+
+	if errors.As(err, &nested) {
+		for q, e := range nested {
+			m[q] = e
+		}
+
+		return
+	}
+
+	m[p] = err
+}
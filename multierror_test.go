@@ -0,0 +1,88 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestMultiError(t *testing.T) {
+	errs := MultiError{
+		"/b": &os.PathError{Op: "open", Path: "/b", Err: os.ErrPermission},
+		"/a": &os.PathError{Op: "open", Path: "/a", Err: errNoSpace},
+		"/c": &os.PathError{Op: "open", Path: "/c", Err: os.ErrPermission},
+	}
+
+	if paths := errs.Paths(); len(paths) != 3 || paths[0] != "/a" || paths[2] != "/c" {
+		t.Errorf("Expected the Paths in order, received %v", paths)
+	}
+
+	if !errors.Is(errs, errNoSpace) || !errors.Is(errs, os.ErrPermission) || errors.Is(errs, os.ErrNotExist) {
+		t.Errorf("Expected errors.Is to match the contained errors")
+	}
+
+	if permission := errs.Filter(os.ErrPermission); len(permission) != 2 || permission["/a"] != nil {
+		t.Errorf("Expected the two permission errors, received %v", permission)
+	}
+
+	if other := errs.Exclude(os.ErrPermission); len(other) != 1 || other["/a"] == nil {
+		t.Errorf("Expected only the ENOSPC error, received %v", other)
+	}
+
+	if expected := "3 errors: open /a: no space left on device; open /b: permission denied; open /c: permission denied"; errs.Error() != expected {
+		t.Errorf("Expected %q, received %q", expected, errs.Error())
+	}
+
+	if err := errs.Filter(os.ErrNotExist).ErrorOrNil(); err != nil {
+		t.Errorf("An empty MultiError should give a nil error, received %v", err)
+	}
+}
+
+func TestFSPathCopyTreePartialFailure(t *testing.T) {
+	src := NewMemFilesystem()
+	src.WriteFile("small", []byte("a"), 0644)
+	src.WriteFile("large", []byte("abc"), 0644)
+	dst := NewPolicyFilesystem(NewMemFilesystem(), Policy{MaxFileSize: 1})
+
+	err := PathOn(src, ".").CopyTree(PathOn(dst, "copy"))
+	var errs MultiError
+
+	if !errors.As(err, &errs) || len(errs) != 1 || !errors.Is(errs["large"], ErrDenied) {
+		t.Fatalf("Expected a MultiError for the large file, received %v", err)
+	}
+
+	if _, err = dst.Stat("copy/small"); err != nil {
+		t.Errorf("The small file should have been copied: %v", err)
+	}
+}
+
+func TestRmdirRecursivePartialFailure(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{{Path: "a", Content: "a"}, {Path: "sub/locked", Content: "b"}, {Path: "sub/c", Content: "c"}})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	locked := dir.JoinPath("sub/locked")
+
+	if err = locked.SetImmutable(true); err != nil {
+		dir.RmdirRecursive()
+		t.Skip("Cannot set the immutable attribute here: " + err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+	defer locked.SetImmutable(false)
+
+	err = dir.RmdirRecursive()
+	var errs MultiError
+
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[locked] == nil {
+		t.Fatalf("Expected a MultiError for only the immutable file, received %v", err)
+	}
+
+	if dir.JoinPath("a").Exists() || dir.JoinPath("sub/c").Exists() {
+		t.Errorf("Everything else should have been removed")
+	}
+}
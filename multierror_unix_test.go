@@ -0,0 +1,40 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCopyTreePartialFailure(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{{Path: "src/a", Content: "a"}, {Path: "src/sub/b", Content: "b"}})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	// pipes cannot be copied, but should not stop the rest of the tree
+	for _, name := range []Path{"src/pipe", "src/sub/pipe"} {
+		if err = dir.JoinPath(name).Mkfifo(0600); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	err = dir.JoinPath("src").CopyTree(dir.JoinPath("dst"))
+	var errs MultiError
+
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[dir.JoinPath("src/pipe")] == nil || errs[dir.JoinPath("src/sub/pipe")] == nil {
+		t.Fatalf("Expected a MultiError for both pipes, received %v", err)
+	}
+
+	for _, name := range []Path{"dst/a", "dst/sub/b"} {
+		if !dir.JoinPath(name).Exists() {
+			t.Errorf("%s should have been copied", name)
+		}
+	}
+}
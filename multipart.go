@@ -0,0 +1,104 @@
+package pathlib
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// AttachTo writes the contents of the file Path to the multipart writer as a form file named fieldName.
+func (p Path) AttachTo(w *multipart.Writer, fieldName string) error {
+	f, err := os.Open(string(p))
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	part, err := w.CreateFormFile(fieldName, p.Name())
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, f)
+	return err
+}
+
+// MultipartFile returns a multipart/form-data body containing the file Path as the form file fieldName, along with the matching content type (eg. for http.Post).  The body is streamed from the file as it is read, so large files are not buffered in memory.
+func (p Path) MultipartFile(fieldName string) (io.ReadCloser, string, error) {
+	if !p.IsFile() {
+		return nil, "", fmt.Errorf("%s is not a file", p)
+	}
+
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+
+	go func() {
+		err := p.AttachTo(w, fieldName)
+
+		if err == nil {
+			err = w.Close()
+		}
+
+		pw.CloseWithError(err)
+	}()
+
+	return pr, w.FormDataContentType(), nil
+}
+
+// SaveMultipart saves the multipart file part into the directory dir and returns the new Path.  The client-supplied filename is sanitized with FromUntrustedName, so it cannot escape dir and an existing file is never overwritten.
+func SaveMultipart(part *multipart.Part, dir Path) (Path, error) {
+	target, err := FromUntrustedName(dir, part.FileName())
+
+	if err != nil {
+		return Path(""), fmt.Errorf("Multipart part %s has no usable filename: %w", part.FormName(), err)
+	}
+
+	outfile, err := os.OpenFile(string(target), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+
+	if err != nil {
+		return target, err
+	}
+
+	_, err = io.Copy(outfile, part)
+	closeErr := outfile.Close()
+
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		target.Unlink()
+		return target, err
+	}
+
+	return target, nil
+}
+
+// sanitizeFilename reduces an untrusted filename to a single safe path component, returning an empty string if nothing usable remains.
+func sanitizeFilename(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = filepath.Base("/" + name)
+
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+
+		return r
+	}, name)
+
+	name = strings.Trim(name, ". ")
+
+	if name == "" || name == "_" {
+		return ""
+	}
+
+	return name
+}
@@ -0,0 +1,86 @@
+package pathlib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"testing"
+)
+
+func TestMultipartRoundTrip(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := dir.JoinPath("out").Mkdir()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	src := dir.JoinPath("upload.txt")
+	err = src.WriteBytes([]byte("multipart contents"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	body, contentType, err := src.MultipartFile("file")
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer body.Close()
+
+	_, params, err := mime.ParseMediaType(contentType)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	reader := multipart.NewReader(body, params["boundary"])
+	part, err := reader.NextPart()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	saved, err := SaveMultipart(part, dir.JoinPath("out"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if saved != dir.JoinPath("out", "upload.txt") {
+		t.Errorf("Saved to unexpected path %s", saved)
+	}
+
+	contents, err := ioutil.ReadFile(string(saved))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if string(contents) != "multipart contents" {
+		t.Errorf("Saved contents do not match: %s", contents)
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := map[string]string{
+		"report.pdf":        "report.pdf",
+		"../../etc/passwd":  "passwd",
+		`C:\Users\me\a.txt`: "a.txt",
+		"..":                "",
+		"bad\x00name?.txt":  "bad_name_.txt",
+		" .hidden. ":        "hidden",
+		"dir/":              "dir",
+	}
+
+	for name, target := range tests {
+		if sanitized := sanitizeFilename(name); sanitized != target {
+			t.Errorf("sanitizeFilename(%q) = %q, expected %q", name, sanitized, target)
+		}
+	}
+}
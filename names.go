@@ -0,0 +1,110 @@
+package pathlib
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// DecodeName decodes a filename as it arrives from the web or email: RFC 2231 extended values (eg. "UTF-8'en'na%C3%AFve.txt" from a Content-Disposition filename* parameter), RFC 2047 encoded-words (eg. "=?UTF-8?Q?na=C3=AFve.txt?="), and percent-encoding from URLs.  Names without any encoding are returned as they are, including those with a "%" that is not a valid escape, such as "100% done.txt".  The result is not sanitized; pass it to FromUntrustedName before using it as a Path.
+func DecodeName(name string) (string, error) {
+	if charset, rest, ok := strings.Cut(name, "'"); ok && rfc2231Charsets[strings.ToUpper(charset)] {
+		if _, value, ok := strings.Cut(rest, "'"); ok {
+			return decodeRFC2231(charset, value)
+		}
+	}
+
+	if strings.Contains(name, "=?") && strings.Contains(name, "?=") {
+		return new(mime.WordDecoder).DecodeHeader(name)
+	}
+
+	if strings.Contains(name, "%") {
+		if decoded, err := url.PathUnescape(name); err == nil {
+			return decoded, nil
+		}
+	}
+
+	return name, nil
+}
+
+// rfc2231Charsets are the charsets DecodeName understands in RFC 2231 values.  Names with other charsets are treated as plain names, so that names such as "rock'n'roll.mp3" are not mistaken for encoded ones.
+var rfc2231Charsets = map[string]bool{"UTF-8": true, "US-ASCII": true, "ISO-8859-1": true, "LATIN1": true}
+
+// decodeRFC2231 percent-decodes the value and converts it from the charset.
+func decodeRFC2231(charset, value string) (string, error) {
+	decoded, err := url.PathUnescape(value)
+
+	if err != nil {
+		return "", err
+	}
+
+	switch strings.ToUpper(charset) {
+	case "UTF-8", "US-ASCII":
+		if !utf8.ValidString(decoded) {
+			return "", fmt.Errorf("Name %q is not valid %s", value, charset)
+		}
+
+		return decoded, nil
+	case "ISO-8859-1", "LATIN1":
+		runes := make([]rune, len(decoded))
+
+		for i := 0; i < len(decoded); i++ {
+			runes[i] = rune(decoded[i])
+		}
+
+		return string(runes), nil
+	}
+
+	return "", fmt.Errorf("Unsupported charset %s for name %q", charset, value)
+}
+
+// EncodeName encodes the filename as an RFC 2231 extended value in UTF-8 with no language tag (the charset, an empty language, and the percent-encoded name, separated by apostrophes), for the filename* parameter of a Content-Disposition header.  DecodeName reverses it.
+func EncodeName(name string) string {
+	var encoded strings.Builder
+	encoded.WriteString("UTF-8''")
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+
+		// attr-char from RFC 5987
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("!#$&+-.^_`|~", c) >= 0 {
+			encoded.WriteByte(c)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+
+	return encoded.String()
+}
+
+// URLPath returns the Path as a slash-separated URL path with each component percent-encoded, for building URLs that name the file.  It is relative unless the Path is absolute.
+func (p Path) URLPath() string {
+	parts := strings.Split(filepath.ToSlash(string(p)), "/")
+
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// FileURL returns the absolute file:// URL of the Path, which Open accepts.
+func (p Path) FileURL() (string, error) {
+	abs, err := filepath.Abs(string(p))
+
+	if err != nil {
+		return "", err
+	}
+
+	urlPath := Path(abs).URLPath()
+
+	// Windows drives are written as file:///C:/dir
+	if !strings.HasPrefix(urlPath, "/") {
+		urlPath = "/" + urlPath
+	}
+
+	return "file://" + urlPath, nil
+}
@@ -0,0 +1,70 @@
+package pathlib
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestDecodeName(t *testing.T) {
+	tests := map[string]string{
+		"plain.txt":                            "plain.txt",
+		"rock'n'roll.mp3":                      "rock'n'roll.mp3",
+		"UTF-8''na%C3%AFve%20file.txt":         "naïve file.txt",
+		"utf-8'en'%E2%82%AC%20rates.pdf":       "€ rates.pdf",
+		"iso-8859-1''caf%E9.txt":               "café.txt",
+		"=?UTF-8?Q?na=C3=AFve.txt?=":           "naïve.txt",
+		"=?UTF-8?B?0L/RgNC40LLQtdGCLnR4dA==?=": "привет.txt",
+		"my%20report%2B2024.pdf":               "my report+2024.pdf",
+		"100% done.txt":                        "100% done.txt",
+		"report 50%.pdf":                       "report 50%.pdf",
+		"bad%zz":                               "bad%zz",
+	}
+
+	for encoded, expected := range tests {
+		if decoded, err := DecodeName(encoded); err != nil || decoded != expected {
+			t.Errorf("Expected %q to decode to %q but got %q (%v)", encoded, expected, decoded, err)
+		}
+	}
+
+	for _, name := range []string{"UTF-8''%ZZ", "UTF-8''%FF.txt"} {
+		if decoded, err := DecodeName(name); err == nil {
+			t.Errorf("Expected an error decoding %q but got %q", name, decoded)
+		}
+	}
+
+	for _, name := range []string{"naïve file.txt", "a;b=c,d'e\".txt", "plain"} {
+		if decoded, err := DecodeName(EncodeName(name)); err != nil || decoded != name {
+			t.Errorf("Expected %q to round trip but got %q (%v)", name, decoded, err)
+		}
+	}
+}
+
+func TestURLPath(t *testing.T) {
+	tests := map[Path]string{
+		"a/b c/d#e.txt": "a/b%20c/d%23e.txt",
+		"/srv/100%.txt": "/srv/100%25.txt",
+		"naïve?.txt":    "na%C3%AFve%3F.txt",
+	}
+
+	for p, expected := range tests {
+		if actual := p.URLPath(); actual != expected {
+			t.Errorf("Expected %s to become %s but got %s", p, expected, actual)
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	fileURL, err := Path("/tmp/a b/c#d.txt").FileURL()
+
+	if err != nil || fileURL != "file:///tmp/a%20b/c%23d.txt" {
+		t.Errorf("Unexpected file URL %s (%v)", fileURL, err)
+	}
+
+	opened, err := Open(fileURL)
+
+	if err != nil || opened.Path() != "/tmp/a b/c#d.txt" {
+		t.Errorf("Expected the file URL to open the same Path but got %s (%v)", opened.Path(), err)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !plan9
+
+package pathlib
+
+import "syscall"
+
+// errNoSpace is the error of a write to a full disk.
+var errNoSpace error = syscall.ENOSPC
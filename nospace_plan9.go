@@ -0,0 +1,6 @@
+package pathlib
+
+import "syscall"
+
+// errNoSpace stands in for ENOSPC, which Plan 9 does not define.
+var errNoSpace error = syscall.NewError("no space left on device")
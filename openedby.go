@@ -0,0 +1,44 @@
+package pathlib
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+)
+
+// ProcessInfo identifies a process, as returned by OpenedBy.
+type ProcessInfo struct {
+	PID  int
+	Name string
+}
+
+// parseLsof parses the output of lsof -F pc, where each process is a "p<pid>" line followed by a "c<command>" line.
+func parseLsof(r io.Reader) ([]ProcessInfo, error) {
+	var processes []ProcessInfo
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if len(line) == 0 {
+			continue
+		}
+
+		switch line[0] {
+		case 'p':
+			pid, err := strconv.Atoi(line[1:])
+
+			if err != nil {
+				return nil, err
+			}
+
+			processes = append(processes, ProcessInfo{PID: pid})
+		case 'c':
+			if len(processes) > 0 {
+				processes[len(processes)-1].Name = line[1:]
+			}
+		}
+	}
+
+	return processes, scanner.Err()
+}
@@ -0,0 +1,53 @@
+package pathlib
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// OpenedBy returns the processes that have the Path open, found by scanning /proc/*/fd.  Processes belonging to other users are only visible with sufficient privileges, and are silently skipped otherwise.
+func (p Path) OpenedBy() ([]ProcessInfo, error) {
+	target, err := os.Stat(string(p))
+
+	if err != nil {
+		return nil, err
+	}
+
+	procs, err := os.ReadDir("/proc")
+
+	if err != nil {
+		return nil, err
+	}
+
+	var processes []ProcessInfo
+
+	for _, proc := range procs {
+		pid, err := strconv.Atoi(proc.Name())
+
+		if err != nil {
+			continue // not a process
+		}
+
+		fdDir := "/proc/" + proc.Name() + "/fd/"
+		fds, err := os.ReadDir(fdDir)
+
+		if err != nil {
+			continue // exited, or not ours to inspect
+		}
+
+		for _, fd := range fds {
+			info, err := os.Stat(fdDir + fd.Name())
+
+			if err != nil || !os.SameFile(info, target) {
+				continue
+			}
+
+			comm, _ := os.ReadFile("/proc/" + proc.Name() + "/comm")
+			processes = append(processes, ProcessInfo{PID: pid, Name: strings.TrimSpace(string(comm))})
+			break
+		}
+	}
+
+	return processes, nil
+}
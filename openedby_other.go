@@ -0,0 +1,32 @@
+//go:build !linux && !windows
+
+package pathlib
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// OpenedBy returns the processes that have the Path open, as reported by lsof, which must be installed.
+func (p Path) OpenedBy() ([]ProcessInfo, error) {
+	if _, err := os.Stat(string(p)); err != nil {
+		return nil, err
+	}
+
+	out, err := exec.Command("lsof", "-F", "pc", "--", string(p)).Output()
+
+	var exitErr *exec.ExitError
+
+	// lsof exits with 1 when no process has the file open
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && len(out) == 0 {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLsof(bytes.NewReader(out))
+}
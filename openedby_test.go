@@ -0,0 +1,77 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestOpenedBy(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "windows" {
+		t.Skip("OpenedBy relies on lsof on this platform")
+	}
+
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := p.WriteBytes([]byte("data")); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	processes, err := p.OpenedBy()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	for _, process := range processes {
+		if process.PID == os.Getpid() {
+			t.Errorf("Unexpectedly found this process holding %s", p)
+		}
+	}
+
+	f, err := os.Open(string(p))
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer f.Close()
+
+	processes, err = p.OpenedBy()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	found := false
+
+	for _, process := range processes {
+		found = found || process.PID == os.Getpid()
+	}
+
+	if !found {
+		t.Errorf("Expected this process (%d) among %v", os.Getpid(), processes)
+	}
+
+	if _, err = Path(string(p) + "-missing").OpenedBy(); err == nil {
+		t.Errorf("Expected an error for a missing file")
+	}
+}
+
+func TestParseLsof(t *testing.T) {
+	processes, err := parseLsof(strings.NewReader("p12\ncvim\np345\ncless\n"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	expected := []ProcessInfo{{PID: 12, Name: "vim"}, {PID: 345, Name: "less"}}
+
+	if fmt.Sprint(processes) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, received %v", expected, processes)
+	}
+}
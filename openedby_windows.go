@@ -0,0 +1,88 @@
+package pathlib
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	rstrtmgr                = syscall.NewLazyDLL("rstrtmgr.dll")
+	procRmStartSession      = rstrtmgr.NewProc("RmStartSession")
+	procRmRegisterResources = rstrtmgr.NewProc("RmRegisterResources")
+	procRmGetList           = rstrtmgr.NewProc("RmGetList")
+	procRmEndSession        = rstrtmgr.NewProc("RmEndSession")
+)
+
+const (
+	errorMoreData    = 234
+	cchRMSessionKey  = 32
+	cchRMMaxAppName  = 255
+	cchRMMaxSvcName  = 63
+	rmInitialProcess = 16
+)
+
+// rmProcessInfo mirrors RM_PROCESS_INFO.
+type rmProcessInfo struct {
+	ProcessID        uint32
+	ProcessStartTime syscall.Filetime
+	AppName          [cchRMMaxAppName + 1]uint16
+	ServiceShortName [cchRMMaxSvcName + 1]uint16
+	ApplicationType  uint32
+	AppStatus        uint32
+	TSSessionID      uint32
+	Restartable      int32
+}
+
+// OpenedBy returns the processes that have the Path open, as reported by the Restart Manager.
+func (p Path) OpenedBy() ([]ProcessInfo, error) {
+	if _, err := os.Stat(string(p)); err != nil {
+		return nil, err
+	}
+
+	name, err := syscall.UTF16PtrFromString(string(p))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var session uint32
+	var key [cchRMSessionKey + 1]uint16
+
+	if ret, _, _ := procRmStartSession.Call(uintptr(unsafe.Pointer(&session)), 0, uintptr(unsafe.Pointer(&key[0]))); ret != 0 {
+		return nil, os.NewSyscallError("RmStartSession", syscall.Errno(ret))
+	}
+
+	defer procRmEndSession.Call(uintptr(session))
+
+	if ret, _, _ := procRmRegisterResources.Call(uintptr(session), 1, uintptr(unsafe.Pointer(&name)), 0, 0, 0, 0); ret != 0 {
+		return nil, os.NewSyscallError("RmRegisterResources", syscall.Errno(ret))
+	}
+
+	infos := make([]rmProcessInfo, rmInitialProcess)
+
+	for {
+		var needed uint32
+		count := uint32(len(infos))
+		var reasons uint32
+
+		ret, _, _ := procRmGetList.Call(uintptr(session), uintptr(unsafe.Pointer(&needed)), uintptr(unsafe.Pointer(&count)), uintptr(unsafe.Pointer(&infos[0])), uintptr(unsafe.Pointer(&reasons)))
+
+		if ret == errorMoreData {
+			infos = make([]rmProcessInfo, needed)
+			continue
+		}
+
+		if ret != 0 {
+			return nil, os.NewSyscallError("RmGetList", syscall.Errno(ret))
+		}
+
+		processes := make([]ProcessInfo, 0, count)
+
+		for _, info := range infos[:count] {
+			processes = append(processes, ProcessInfo{PID: int(info.ProcessID), Name: syscall.UTF16ToString(info.AppName[:])})
+		}
+
+		return processes, nil
+	}
+}
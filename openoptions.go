@@ -0,0 +1,31 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+)
+
+// OpenOptions tunes how OpenWithOptions opens a file.  The hints map to CreateFile flags on Windows; elsewhere only WriteThrough has an effect (as O_SYNC), and the sharing options are ignored, since Unix does not lock open files.
+type OpenOptions struct {
+	// SequentialScan hints that the file will be read from start to end (FILE_FLAG_SEQUENTIAL_SCAN).
+	SequentialScan bool
+	// RandomAccess hints that the file will be read out of order (FILE_FLAG_RANDOM_ACCESS).
+	RandomAccess bool
+	// WriteThrough makes writes go straight to disk rather than through the cache (FILE_FLAG_WRITE_THROUGH, or O_SYNC).
+	WriteThrough bool
+	// DenyRead stops other processes from opening the file for reading while it is open (omits FILE_SHARE_READ).
+	DenyRead bool
+	// DenyWrite stops other processes from opening the file for writing while it is open (omits FILE_SHARE_WRITE).
+	DenyWrite bool
+	// ShareDelete lets other processes delete or rename the file while it is open (FILE_SHARE_DELETE).
+	ShareDelete bool
+}
+
+// OpenWithOptions opens the Path like OpenWithPermissions, applying the I/O hints and sharing modes in opts.  If the Path does not exist, it creates it.
+func (p Path) OpenWithOptions(mode string, perms os.FileMode, opts OpenOptions) (*os.File, error) {
+	if p.IsDir() {
+		return nil, fmt.Errorf("Cannot open %s because it is a directory.", p)
+	}
+
+	return openWithOptions(string(p), p.openFlag(mode), perms, opts)
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package pathlib
+
+import (
+	"os"
+)
+
+func openWithOptions(name string, flag int, perms os.FileMode, opts OpenOptions) (*os.File, error) {
+	if opts.WriteThrough {
+		flag |= os.O_SYNC
+	}
+
+	return os.OpenFile(name, flag, perms)
+}
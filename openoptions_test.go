@@ -0,0 +1,54 @@
+package pathlib
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+)
+
+func TestOpenWithOptions(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	f, err := p.OpenWithOptions("w", 0644, OpenOptions{WriteThrough: true, DenyWrite: true})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	if _, err = f.Write([]byte("hello")); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	// Windows refuses a second writer while the first denies writing
+	second, err := p.OpenWithOptions("w", 0644, OpenOptions{})
+
+	if runtime.GOOS == "windows" && err == nil {
+		t.Errorf("Expected a sharing violation opening %s for writing twice", p)
+	}
+
+	if err == nil {
+		second.Close()
+	}
+
+	f.Close()
+
+	f, err = p.OpenWithOptions("r", 0644, OpenOptions{SequentialScan: true})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer f.Close()
+
+	contents, err := io.ReadAll(f)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if string(contents) != "hello" {
+		t.Errorf("Expected hello, received %q", contents)
+	}
+}
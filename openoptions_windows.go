@@ -0,0 +1,88 @@
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+const (
+	fileFlagWriteThrough   = 0x80000000
+	fileFlagRandomAccess   = 0x10000000
+	fileFlagSequentialScan = 0x08000000
+	fileShareDelete        = 0x4
+	fileWriteAttributes    = 0x100
+	fileWriteEA            = 0x10
+	standardRightsWrite    = 0x20000
+	synchronize            = 0x100000
+)
+
+// openWithOptions mirrors os.OpenFile, but calls CreateFile directly so that the sharing mode and flags can be set.
+func openWithOptions(name string, flag int, perms os.FileMode, opts OpenOptions) (*os.File, error) {
+	path, err := syscall.UTF16PtrFromString(name)
+
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	var access uint32
+
+	switch flag & (os.O_RDONLY | os.O_WRONLY | os.O_RDWR) {
+	case os.O_RDONLY:
+		access = syscall.GENERIC_READ
+	case os.O_WRONLY:
+		access = syscall.GENERIC_WRITE
+	case os.O_RDWR:
+		access = syscall.GENERIC_READ | syscall.GENERIC_WRITE
+	}
+
+	if flag&os.O_APPEND != 0 {
+		access &^= syscall.GENERIC_WRITE
+		access |= syscall.FILE_APPEND_DATA | fileWriteAttributes | fileWriteEA | standardRightsWrite | synchronize
+	}
+
+	var share uint32
+
+	if !opts.DenyRead {
+		share |= syscall.FILE_SHARE_READ
+	}
+
+	if !opts.DenyWrite {
+		share |= syscall.FILE_SHARE_WRITE
+	}
+
+	if opts.ShareDelete {
+		share |= fileShareDelete
+	}
+
+	createMode := uint32(syscall.OPEN_EXISTING)
+
+	if flag&os.O_CREATE != 0 {
+		createMode = syscall.OPEN_ALWAYS
+	}
+
+	attrs := uint32(syscall.FILE_ATTRIBUTE_NORMAL)
+
+	if flag&os.O_CREATE != 0 && perms&0200 == 0 {
+		attrs = syscall.FILE_ATTRIBUTE_READONLY
+	}
+
+	if opts.SequentialScan {
+		attrs |= fileFlagSequentialScan
+	}
+
+	if opts.RandomAccess {
+		attrs |= fileFlagRandomAccess
+	}
+
+	if opts.WriteThrough {
+		attrs |= fileFlagWriteThrough
+	}
+
+	handle, err := syscall.CreateFile(path, access, share, nil, createMode, attrs, 0)
+
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return os.NewFile(uintptr(handle), name), nil
+}
@@ -0,0 +1,124 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Options selects how a ConfiguredPath behaves where the behavior of Paths is changing, so that each change can be adopted on its own before it becomes the default.  Plain Paths behave as with DefaultOptions.
+type Options struct {
+	// LegacyOpenModes keeps the current mode strings of Open: "r" reads, "w" writes, "rw" does both, "+" appends, and missing files are always created.  Otherwise modes follow Python's open: "r" reads, "w" truncates or creates the file for writing, "a" appends to it (creating it if needed), "x" creates it and fails if it exists, and "+" adds reading or writing to any of them.
+	LegacyOpenModes bool
+
+	// AutoAbs makes Configured resolve relative Paths against the working directory when they are created, so they keep referring to the same file if the working directory changes.
+	AutoAbs bool
+
+	// MkdirExistOK makes Mkdir succeed when the directory already exists, rather than returning an error.
+	MkdirExistOK bool
+}
+
+// DefaultOptions are the Options matching the behavior of plain Paths.
+var DefaultOptions = Options{LegacyOpenModes: true}
+
+// ConfiguredPath is a Path that behaves according to Options.  Paths derived from it with JoinPath and Parent keep its Options; those from the other methods of Path are plain Paths.
+type ConfiguredPath struct {
+	Path
+	opts Options
+}
+
+// Configured returns the Path configured with the Options.
+func Configured(p Path, opts Options) (ConfiguredPath, error) {
+	if opts.AutoAbs {
+		abs, err := filepath.Abs(string(p))
+
+		if err != nil {
+			return ConfiguredPath{}, err
+		}
+
+		p = Path(abs)
+	}
+
+	return ConfiguredPath{Path: p, opts: opts}, nil
+}
+
+// Options returns the Options the Path was configured with.
+func (p ConfiguredPath) Options() Options {
+	return p.opts
+}
+
+// JoinPath joins the Paths onto this one, as Path.JoinPath does, keeping the Options.
+func (p ConfiguredPath) JoinPath(paths ...Path) ConfiguredPath {
+	return ConfiguredPath{Path: p.Path.JoinPath(paths...), opts: p.opts}
+}
+
+// Parent returns the directory containing this Path, keeping the Options.
+func (p ConfiguredPath) Parent() ConfiguredPath {
+	return ConfiguredPath{Path: p.Path.Parent(), opts: p.opts}
+}
+
+// Mkdir makes the directory and any missing parents, as Path.Mkdir does.  With MkdirExistOK, an existing directory is not an error.
+func (p ConfiguredPath) Mkdir() error {
+	if p.opts.MkdirExistOK && p.IsDir() {
+		return nil
+	}
+
+	return p.Path.Mkdir()
+}
+
+// Open opens the Path with the mode, creating files with 0755 permissions (subject to umask), as Path.Open does.  The meaning of the mode depends on LegacyOpenModes.
+func (p ConfiguredPath) Open(mode string) (*os.File, error) {
+	return p.OpenWithPermissions(mode, 0755)
+}
+
+// OpenWithPermissions opens the Path with the mode and, if it is created, the permissions.  The meaning of the mode depends on LegacyOpenModes.
+func (p ConfiguredPath) OpenWithPermissions(mode string, perms os.FileMode) (*os.File, error) {
+	if p.opts.LegacyOpenModes {
+		return p.Path.OpenWithPermissions(mode, perms)
+	}
+
+	flag, err := openModeFlag(mode)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return os.OpenFile(string(p.Path), flag, perms)
+}
+
+// openModeFlag translates a mode string in the style of Python's open into os.OpenFile flags.  "b" and "t" are accepted and ignored, since files are always binary.
+func openModeFlag(mode string) (int, error) {
+	var flag int
+	plus := strings.Count(mode, "+")
+	kinds := 0
+
+	for _, c := range mode {
+		switch c {
+		case 'r':
+			flag = os.O_RDONLY
+		case 'w':
+			flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		case 'a':
+			flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		case 'x':
+			flag = os.O_WRONLY | os.O_CREATE | os.O_EXCL
+		case '+', 'b', 't':
+			continue
+		default:
+			return 0, fmt.Errorf("Invalid open mode %q", mode)
+		}
+
+		kinds++
+	}
+
+	if kinds != 1 || plus > 1 || (strings.Contains(mode, "b") && strings.Contains(mode, "t")) {
+		return 0, fmt.Errorf("Invalid open mode %q: it needs exactly one of r, w, a, or x, and at most one +", mode)
+	}
+
+	if plus == 1 {
+		flag = flag&^(os.O_RDONLY|os.O_WRONLY) | os.O_RDWR
+	}
+
+	return flag, nil
+}
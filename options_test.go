@@ -0,0 +1,122 @@
+package pathlib
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfiguredOpenModes(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{{Path: "file", Content: "hello"}})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	p, err := Configured(dir.JoinPath("file"), Options{})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// "a" appends rather than overwriting the start, as the legacy "w" does
+	f, err := p.Open("a")
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	f.Write([]byte(" world"))
+	f.Close()
+
+	if data, _ := p.ReadBytes(); string(data) != "hello world" {
+		t.Errorf("Expected an append, received %q", data)
+	}
+
+	// "w" truncates
+	f, err = p.Open("w+")
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	f.Write([]byte("new"))
+	f.Seek(0, io.SeekStart)
+	data, _ := io.ReadAll(f)
+	f.Close()
+
+	if string(data) != "new" {
+		t.Errorf("Expected the file to be truncated, and readable with +, received %q", data)
+	}
+
+	if _, err = p.Open("x"); !os.IsExist(err) {
+		t.Errorf("Expected x to fail on an existing file, received %v", err)
+	}
+
+	if _, err = p.Open("r"); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	for _, mode := range []string{"", "rw", "q", "r++", "wa"} {
+		if _, err = p.Open(mode); err == nil {
+			t.Errorf("Expected mode %q to be invalid", mode)
+		}
+	}
+
+	// the legacy modes are unchanged
+	legacy, _ := Configured(p.Path, DefaultOptions)
+	f, err = legacy.Open("w")
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	f.Write([]byte("N"))
+	f.Close()
+
+	if data, _ := p.ReadBytes(); string(data) != "New" {
+		t.Errorf("Expected the legacy w to overwrite without truncating, received %q", data)
+	}
+}
+
+func TestConfiguredMkdirAndAbs(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	p, err := Configured(dir, Options{MkdirExistOK: true})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	sub := p.JoinPath("a", "b")
+
+	for i := 0; i < 2; i++ {
+		if err = sub.Mkdir(); err != nil {
+			t.Errorf("Mkdir %d: %s", i, err)
+		}
+	}
+
+	if sub.Parent().Options() != p.Options() {
+		t.Errorf("Derived Paths should keep their Options")
+	}
+
+	if plain, _ := Configured(dir, DefaultOptions); plain.Mkdir() == nil {
+		t.Errorf("Mkdir of an existing directory should fail without MkdirExistOK")
+	}
+
+	rel, err := Configured("relative/name", Options{AutoAbs: true})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if !filepath.IsAbs(string(rel.Path)) || rel.Name() != "name" {
+		t.Errorf("Expected an absolute Path, received %s", rel.Path)
+	}
+}
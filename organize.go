@@ -0,0 +1,108 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gershwinlabs/pathlib/media"
+)
+
+// photoExts are the extensions OrganizeByDate treats as photos even when they carry no EXIF date.
+var photoExts = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".heic": true, ".webp": true,
+	".tif": true, ".tiff": true, ".dng": true, ".nef": true, ".cr2": true, ".arw": true,
+}
+
+// ExifTime returns the time the photo at the Path was taken, according to its EXIF data.  See media.ExifTime.
+func (p Path) ExifTime() (time.Time, error) {
+	f, err := os.Open(string(p))
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	defer f.Close()
+
+	return media.ExifTime(f)
+}
+
+// OrganizeByDate moves the photos found beneath src into folders beneath dest named by formatting the date each was taken with layout (eg. "2006/01" for year and month folders).  The EXIF date is used when available, falling back to the modification time for files with a photo extension; other files are left alone.  Existing files are never overwritten: a numeric suffix is added to the name instead.  Dest may be src or within it, so running again only moves new photos: photos already in the folder for their date are left alone, and a dest within src is not searched.  The new Paths of the moved photos are returned.
+func OrganizeByDate(src, dest Path, layout string) ([]Path, error) {
+	var moved []Path
+	absSrc, err := filepath.Abs(string(src))
+
+	if err != nil {
+		return nil, err
+	}
+
+	absDest, err := filepath.Abs(string(dest))
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = filepath.Walk(string(src), func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() && absDest != absSrc {
+			if abs, _ := filepath.Abs(path); abs == absDest {
+				return filepath.SkipDir
+			}
+		}
+
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+
+		p := Path(path)
+		taken, err := p.ExifTime()
+
+		if err != nil {
+			if !photoExts[strings.ToLower(filepath.Ext(path))] {
+				return nil
+			}
+
+			taken = info.ModTime()
+		}
+
+		dir := dest.JoinPath(Path(taken.Format(layout)))
+
+		if parent, _ := filepath.Abs(string(p.Parent())); parent == filepath.Join(absDest, taken.Format(layout)) {
+			return nil // already organized
+		}
+
+		if err = os.MkdirAll(string(dir), 0755); err != nil {
+			return err
+		}
+
+		target := uniquePath(dir.JoinPath(Path(p.Name())))
+
+		if err = p.Rename(target); err != nil {
+			return err
+		}
+
+		moved = append(moved, target)
+		return nil
+	})
+
+	return moved, err
+}
+
+// uniquePath returns the Path, or if it already exists, the Path with the first numeric suffix (eg. "name-1.ext") that does not.
+func uniquePath(p Path) Path {
+	if !p.lexists() {
+		return p
+	}
+
+	ext := filepath.Ext(string(p))
+	base := strings.TrimSuffix(string(p), ext)
+
+	for i := 1; ; i++ {
+		candidate := Path(fmt.Sprintf("%s-%d%s", base, i, ext))
+
+		if !candidate.lexists() {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestOrganizeByDate(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	src := dir.JoinPath("inbox")
+	dest := dir.JoinPath("photos")
+
+	err := CreateTree(src, TreeSpec{
+		{Path: "a/one.jpg", Content: "not really a jpeg"},
+		{Path: "two.jpg", Content: "also not a jpeg"},
+		{Path: "notes.txt", Content: "not a photo"},
+		{Path: "existing", Dir: true},
+	})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	taken := time.Date(2019, 7, 4, 12, 0, 0, 0, time.Local)
+
+	for _, name := range []Path{"a/one.jpg", "two.jpg"} {
+		os.Chtimes(string(src.JoinPath(name)), taken, taken)
+	}
+
+	err = CreateTree(dest, TreeSpec{{Path: "2019/07/two.jpg", Content: "already here"}})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	moved, err := OrganizeByDate(src, dest, "2006/01")
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if len(moved) != 2 {
+		t.Errorf("Expected 2 moved photos, received %v", moved)
+	}
+
+	for _, name := range []Path{"2019/07/one.jpg", "2019/07/two.jpg", "2019/07/two-1.jpg"} {
+		if !dest.JoinPath(name).IsFile() {
+			t.Errorf("%s was not created", name)
+		}
+	}
+
+	if !src.JoinPath("notes.txt").Exists() {
+		t.Errorf("Non-photo files should not be moved")
+	}
+
+	// organizing in place, or into a folder within the source, must leave organized photos alone when run again
+	for _, target := range []Path{dest, dest.JoinPath("sorted")} {
+		for run := 0; run < 2; run++ {
+			if moved, err = OrganizeByDate(dest, target, "2006/01"); err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			if run == 1 && len(moved) != 0 {
+				t.Errorf("Expected nothing to move when organizing %s again, received %v", target, moved)
+			}
+		}
+	}
+
+	if !dest.JoinPath("sorted", "2019", "07", "two-1.jpg").IsFile() || dest.JoinPath("sorted", "2019", "07", "two-2.jpg").Exists() {
+		t.Errorf("Organized photos were moved again")
+	}
+}
+
+func TestExifTimeNotPhoto(t *testing.T) {
+	_, err := Path("path.go").ExifTime()
+
+	if err == nil {
+		t.Errorf("ExifTime should fail for files without EXIF data")
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package pathlib
+
+import (
+	"os"
+)
+
+// fileOwner reports false, since files have no user and group IDs on this platform.
+func fileOwner(info os.FileInfo) (int, int, bool) {
+	return 0, 0, false
+}
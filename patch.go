@@ -0,0 +1,465 @@
+package pathlib
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// patchMagic starts every patch written by GeneratePatch.
+const patchMagic = "PLBDIFF1"
+
+// GeneratePatch writes a binary patch that turns the file old into the file new, using the bsdiff algorithm: matches are found with a suffix array of old and extended into approximate matches, so code that merely shifted (as in recompiled executables) costs little.  The patch records the sizes and SHA-256 hashes of both files and is compressed with DEFLATE.  Both files are read into memory.
+func GeneratePatch(old, new, patch Path) error {
+	oldData, err := old.ReadBytes()
+
+	if err != nil {
+		return err
+	}
+
+	newData, err := new.ReadBytes()
+
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(patchMagic)
+	writePatchHeader(&buf, oldData)
+	writePatchHeader(&buf, newData)
+
+	compressor, err := flate.NewWriter(&buf, flate.BestCompression)
+
+	if err != nil {
+		return err
+	}
+
+	if err = bsdiff(oldData, newData, compressor); err != nil {
+		return err
+	}
+
+	if err = compressor.Close(); err != nil {
+		return err
+	}
+
+	return patch.writeBytesAtomic(buf.Bytes(), 0644)
+}
+
+func writePatchHeader(w *bytes.Buffer, data []byte) {
+	sum := sha256.Sum256(data)
+	binary.Write(w, binary.BigEndian, int64(len(data)))
+	w.Write(sum[:])
+}
+
+// ApplyPatch rebuilds a file at out from the file old and a patch written by GeneratePatch.  It refuses to apply the patch unless old matches the file the patch was generated from, and verifies the result before atomically moving it into place.  A new out gets the permissions of old, and out may be the same Path as old.
+func ApplyPatch(old, patch, out Path) error {
+	oldData, err := old.ReadBytes()
+
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(string(patch))
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	magic := make([]byte, len(patchMagic))
+
+	if _, err = io.ReadFull(r, magic); err != nil || string(magic) != patchMagic {
+		return fmt.Errorf("Not a patch file: %s", patch)
+	}
+
+	var oldSize, newSize int64
+	var oldSum, newSum [sha256.Size]byte
+
+	if err = binary.Read(r, binary.BigEndian, &oldSize); err == nil {
+		if _, err = io.ReadFull(r, oldSum[:]); err == nil {
+			if err = binary.Read(r, binary.BigEndian, &newSize); err == nil {
+				_, err = io.ReadFull(r, newSum[:])
+			}
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("Truncated patch file %s: %w", patch, err)
+	}
+
+	if int64(len(oldData)) != oldSize || sha256.Sum256(oldData) != oldSum {
+		return fmt.Errorf("Patch %s was not generated from %s", patch, old)
+	}
+
+	if newSize < 0 || newSize > 1<<40 {
+		return fmt.Errorf("Corrupt patch file %s", patch)
+	}
+
+	newData, err := bspatch(oldData, newSize, bufio.NewReader(flate.NewReader(r)))
+
+	if err != nil {
+		return fmt.Errorf("Corrupt patch file %s: %w", patch, err)
+	}
+
+	if sha256.Sum256(newData) != newSum {
+		return fmt.Errorf("Patch %s did not produce the expected file", patch)
+	}
+
+	perms := os.FileMode(0644)
+
+	if stat, err := os.Stat(string(old)); err == nil {
+		perms = stat.Mode().Perm()
+	}
+
+	return out.writeBytesAtomic(newData, perms)
+}
+
+// bspatch replays the control triples, diff bytes, and extra bytes from r against old.  The output grows as data arrives, so a corrupt newSize cannot make it allocate more than the patch holds.
+func bspatch(old []byte, newSize int64, r *bufio.Reader) ([]byte, error) {
+	var out bytes.Buffer
+	var oldPos, newPos int64
+
+	for newPos < newSize {
+		var ctrl [3]int64
+
+		for i := range ctrl {
+			value, err := binary.ReadVarint(r)
+
+			if err != nil {
+				return nil, err
+			}
+
+			ctrl[i] = value
+		}
+
+		// each term is checked on its own, since their sum can overflow
+		if ctrl[0] < 0 || ctrl[1] < 0 || ctrl[0] > newSize-newPos || ctrl[1] > newSize-newPos-ctrl[0] {
+			return nil, fmt.Errorf("control block out of range")
+		}
+
+		if _, err := io.CopyN(&out, r, ctrl[0]); err != nil {
+			return nil, err
+		}
+
+		diff := out.Bytes()[newPos:]
+
+		for i := range diff {
+			if at := oldPos + int64(i); at >= 0 && at < int64(len(old)) {
+				diff[i] += old[at]
+			}
+		}
+
+		if _, err := io.CopyN(&out, r, ctrl[1]); err != nil {
+			return nil, err
+		}
+
+		newPos += ctrl[0] + ctrl[1]
+		oldPos += ctrl[0] + ctrl[2]
+	}
+
+	return out.Bytes(), nil
+}
+
+// bsdiff writes the patch body turning old into new: for each step, a control triple of varints (bytes to add to old, bytes to insert, and how far to seek in old), followed by the diff bytes and the inserted bytes.
+func bsdiff(old, new []byte, w io.Writer) error {
+	index := suffixSort(old)
+	var scan, length, pos, lastScan, lastPos, lastOffset int
+	var ctrl []byte
+
+	for scan < len(new) {
+		oldScore := 0
+		scan += length
+
+		for scsc := scan; scan < len(new); scan++ {
+			length, pos = suffixSearch(index, old, new[scan:], 0, len(old))
+
+			for ; scsc < scan+length; scsc++ {
+				if scsc+lastOffset < len(old) && old[scsc+lastOffset] == new[scsc] {
+					oldScore++
+				}
+			}
+
+			if (length == oldScore && length != 0) || length > oldScore+8 {
+				break
+			}
+
+			if scan+lastOffset < len(old) && old[scan+lastOffset] == new[scan] {
+				oldScore--
+			}
+		}
+
+		if length == oldScore && scan != len(new) {
+			continue
+		}
+
+		// extend the previous match forwards and this one backwards as long as they mostly agree
+		s, bestForward, lenForward := 0, 0, 0
+
+		for i := 0; lastScan+i < scan && lastPos+i < len(old); {
+			if old[lastPos+i] == new[lastScan+i] {
+				s++
+			}
+
+			i++
+
+			if s*2-i > bestForward*2-lenForward {
+				bestForward, lenForward = s, i
+			}
+		}
+
+		lenBack := 0
+
+		if scan < len(new) {
+			s, bestBack := 0, 0
+
+			for i := 1; scan >= lastScan+i && pos >= i; i++ {
+				if old[pos-i] == new[scan-i] {
+					s++
+				}
+
+				if s*2-i > bestBack*2-lenBack {
+					bestBack, lenBack = s, i
+				}
+			}
+		}
+
+		if lastScan+lenForward > scan-lenBack {
+			overlap := (lastScan + lenForward) - (scan - lenBack)
+			s, bestSplit, lenSplit := 0, 0, 0
+
+			for i := 0; i < overlap; i++ {
+				if new[lastScan+lenForward-overlap+i] == old[lastPos+lenForward-overlap+i] {
+					s++
+				}
+
+				if new[scan-lenBack+i] == old[pos-lenBack+i] {
+					s--
+				}
+
+				if s > bestSplit {
+					bestSplit, lenSplit = s, i+1
+				}
+			}
+
+			lenForward += lenSplit - overlap
+			lenBack -= lenSplit
+		}
+
+		extra := (scan - lenBack) - (lastScan + lenForward)
+		ctrl = binary.AppendVarint(ctrl[:0], int64(lenForward))
+		ctrl = binary.AppendVarint(ctrl, int64(extra))
+		ctrl = binary.AppendVarint(ctrl, int64((pos-lenBack)-(lastPos+lenForward)))
+		diff := make([]byte, lenForward)
+
+		for i := range diff {
+			diff[i] = new[lastScan+i] - old[lastPos+i]
+		}
+
+		for _, block := range [][]byte{ctrl, diff, new[lastScan+lenForward : lastScan+lenForward+extra]} {
+			if _, err := w.Write(block); err != nil {
+				return err
+			}
+		}
+
+		lastScan, lastPos, lastOffset = scan-lenBack, pos-lenBack, pos-scan
+	}
+
+	return nil
+}
+
+// suffixSearch finds the longest match for target among the suffixes of old between index positions start and end, returning its length and position in old.
+func suffixSearch(index []int, old, target []byte, start, end int) (int, int) {
+	for end-start >= 2 {
+		mid := start + (end-start)/2
+		suffix := old[index[mid]:]
+
+		if n := min(len(suffix), len(target)); bytes.Compare(suffix[:n], target[:n]) < 0 {
+			start = mid
+		} else {
+			end = mid
+		}
+	}
+
+	startLen := matchLen(old[index[start]:], target)
+	endLen := matchLen(old[index[end]:], target)
+
+	if startLen > endLen {
+		return startLen, index[start]
+	}
+
+	return endLen, index[end]
+}
+
+func matchLen(a, b []byte) int {
+	i := 0
+
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
+
+// suffixSort returns the suffix array of buf (including the empty suffix) using Larsson and Sadakane's qsufsort, as in bsdiff.
+func suffixSort(buf []byte) []int {
+	index := make([]int, len(buf)+1)
+	group := make([]int, len(buf)+1)
+	var buckets [256]int
+
+	for _, c := range buf {
+		buckets[c]++
+	}
+
+	for i := 1; i < 256; i++ {
+		buckets[i] += buckets[i-1]
+	}
+
+	copy(buckets[1:], buckets[:255])
+	buckets[0] = 0
+
+	for i, c := range buf {
+		buckets[c]++
+		index[buckets[c]] = i
+	}
+
+	index[0] = len(buf)
+
+	for i, c := range buf {
+		group[i] = buckets[c]
+	}
+
+	group[len(buf)] = 0
+
+	for i := 1; i < 256; i++ {
+		if buckets[i] == buckets[i-1]+1 {
+			index[buckets[i]] = -1
+		}
+	}
+
+	index[0] = -1
+
+	for h := 1; index[0] != -(len(buf) + 1); h += h {
+		length := 0
+		i := 0
+
+		for i < len(buf)+1 {
+			if index[i] < 0 {
+				length -= index[i]
+				i -= index[i]
+			} else {
+				if length != 0 {
+					index[i-length] = -length
+				}
+
+				length = group[index[i]] + 1 - i
+				suffixSplit(index, group, i, length, h)
+				i += length
+				length = 0
+			}
+		}
+
+		if length != 0 {
+			index[i-length] = -length
+		}
+	}
+
+	for i := 0; i < len(buf)+1; i++ {
+		index[group[i]] = i
+	}
+
+	return index
+}
+
+func suffixSplit(index, group []int, start, length, h int) {
+	if length < 16 {
+		for k := start; k < start+length; {
+			j := 1
+			x := group[index[k]+h]
+
+			for i := 1; k+i < start+length; i++ {
+				if group[index[k+i]+h] < x {
+					x = group[index[k+i]+h]
+					j = 0
+				}
+
+				if group[index[k+i]+h] == x {
+					index[k+j], index[k+i] = index[k+i], index[k+j]
+					j++
+				}
+			}
+
+			for i := 0; i < j; i++ {
+				group[index[k+i]] = k + j - 1
+			}
+
+			if j == 1 {
+				index[k] = -1
+			}
+
+			k += j
+		}
+
+		return
+	}
+
+	x := group[index[start+length/2]+h]
+	jj, kk := 0, 0
+
+	for i := start; i < start+length; i++ {
+		if group[index[i]+h] < x {
+			jj++
+		}
+
+		if group[index[i]+h] == x {
+			kk++
+		}
+	}
+
+	jj += start
+	kk += jj
+	i, j, k := start, 0, 0
+
+	for i < jj {
+		if group[index[i]+h] < x {
+			i++
+		} else if group[index[i]+h] == x {
+			index[i], index[jj+j] = index[jj+j], index[i]
+			j++
+		} else {
+			index[i], index[kk+k] = index[kk+k], index[i]
+			k++
+		}
+	}
+
+	for jj+j < kk {
+		if group[index[jj+j]+h] == x {
+			j++
+		} else {
+			index[jj+j], index[kk+k] = index[kk+k], index[jj+j]
+			k++
+		}
+	}
+
+	if jj > start {
+		suffixSplit(index, group, start, jj-start, h)
+	}
+
+	for i := 0; i < kk-jj; i++ {
+		group[index[jj+i]] = kk - 1
+	}
+
+	if jj == kk-1 {
+		index[jj] = -1
+	}
+
+	if start+length > kk {
+		suffixSplit(index, group, kk, start+length-kk, h)
+	}
+}
@@ -0,0 +1,107 @@
+package pathlib
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+)
+
+func TestSuffixSort(t *testing.T) {
+	for _, input := range []string{"", "a", "banana", "mississippi", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "abracadabra abracadabra abracadabra"} {
+		index := suffixSort([]byte(input))
+		expected := make([]int, len(input)+1)
+
+		for i := range expected {
+			expected[i] = i
+		}
+
+		sort.Slice(expected, func(i, j int) bool { return input[expected[i]:] < input[expected[j]:] })
+
+		if fmt.Sprint(index) != fmt.Sprint(expected) {
+			t.Errorf("Suffix array of %q: expected %v, received %v", input, expected, index)
+		}
+	}
+}
+
+func TestPatch(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := dir.Mkdir(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	random := rand.New(rand.NewSource(3))
+	oldData := make([]byte, 200000)
+	random.Read(oldData)
+
+	// shift everything, insert some bytes, and sprinkle small edits as a recompile would
+	newData := append([]byte("header"), oldData[:50000]...)
+	newData = append(newData, oldData[60000:]...)
+
+	for i := 1000; i < len(newData); i += 5000 {
+		newData[i]++
+	}
+
+	tests := map[string][2][]byte{
+		"edited":    {oldData, newData},
+		"empty old": {nil, []byte("brand new")},
+		"empty new": {oldData[:100], nil},
+	}
+
+	for name, test := range tests {
+		old := dir.JoinPath("old")
+		updated := dir.JoinPath("new")
+		patch := dir.JoinPath("patch")
+		out := dir.JoinPath("out")
+
+		if err := old.WriteBytes(test[0]); err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		if err := updated.WriteBytes(test[1]); err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		if err := GeneratePatch(old, updated, patch); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if err := ApplyPatch(old, patch, out); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		contents, err := out.ReadBytes()
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+
+		if !bytes.Equal(contents, test[1]) {
+			t.Errorf("%s: patched file does not match", name)
+		}
+
+		if name == "edited" {
+			stat, err := os.Stat(string(patch))
+
+			if err != nil {
+				t.Errorf(err.Error())
+			} else if stat.Size() > 20000 {
+				t.Errorf("Patch is unexpectedly large: %d bytes", stat.Size())
+			}
+
+			// a patch only applies to the file it was generated from
+			if err = ApplyPatch(updated, patch, dir.JoinPath("wrong")); err == nil {
+				t.Errorf("Expected an error applying a patch to the wrong file")
+			}
+		}
+	}
+
+	if err := ApplyPatch(dir.JoinPath("old"), dir.JoinPath("new"), dir.JoinPath("out")); err == nil {
+		t.Errorf("Expected an error for a file that is not a patch")
+	}
+}
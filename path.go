@@ -226,11 +226,142 @@ func (p Path) JoinPath(paths ...Path) Path {
 	return Path(ret)
 }
 
+// SecureJoin joins the untrusted path onto the Path, guaranteeing that the result stays within the Path.  Any ".." components are resolved lexically as if the Path were the filesystem root, and an error is returned if symbolic links would lead outside of the Path.
+func (p Path) SecureJoin(untrusted string) (Path, error) {
+	cleaned := filepath.Clean(string(filepath.Separator) + filepath.FromSlash(untrusted))
+	joined := p.JoinPath(Path(cleaned))
+
+	root, err := filepath.EvalSymlinks(string(p))
+
+	if err != nil {
+		return joined, err
+	}
+
+	existing := joined
+
+	for !existing.lexists() {
+		existing = existing.Parent()
+	}
+
+	resolved, err := filepath.EvalSymlinks(string(existing))
+
+	if err != nil {
+		return joined, err
+	}
+
+	if !Path(resolved).within(Path(root)) {
+		return joined, fmt.Errorf("%s escapes %s", untrusted, p)
+	}
+
+	return joined, nil
+}
+
+// lexists returns true if the Path exists, without following a final symbolic link.
+func (p Path) lexists() bool {
+	_, err := os.Lstat(string(p))
+	return err == nil
+}
+
+// within returns true if the Path is lexically the same as or beneath the base Path.
+func (p Path) within(base Path) bool {
+	rel, err := filepath.Rel(string(base), string(p))
+
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 // Name returns only the last portion of the Path as a string.
 func (p Path) Name() string {
 	return filepath.Base(string(p))
 }
 
+// Stem returns the last portion of the Path without its Suffix, so "archive.tar.gz" becomes "archive.tar".
+func (p Path) Stem() string {
+	name := p.Name()
+	return name[:len(name)-len(p.Suffix())]
+}
+
+// Suffix returns the file extension of the last portion of the Path, including the dot, or an empty string if there is none.  Unlike filepath.Ext, a leading dot (as in ".bashrc") does not start a suffix.
+func (p Path) Suffix() string {
+	name := p.Name()
+	i := strings.LastIndex(name, ".")
+
+	if i <= 0 || i == len(name)-1 {
+		return ""
+	}
+
+	return name[i:]
+}
+
+// Suffixes returns all of the file extensions of the last portion of the Path, so "archive.tar.gz" returns [".tar", ".gz"].
+func (p Path) Suffixes() []string {
+	name := strings.TrimLeft(p.Name(), ".")
+
+	if strings.HasSuffix(name, ".") {
+		return nil
+	}
+
+	parts := strings.Split(name, ".")[1:]
+	suffixes := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		suffixes = append(suffixes, "."+part)
+	}
+
+	return suffixes
+}
+
+// Parts returns the components of the cleaned Path.  For an absolute Path, the first component is the root (including any volume name), so "/usr/bin" returns ["/", "usr", "bin"].
+func (p Path) Parts() []string {
+	cleaned := filepath.Clean(string(p))
+
+	if cleaned == "." {
+		return nil
+	}
+
+	volume := filepath.VolumeName(cleaned)
+	rest := cleaned[len(volume):]
+	var parts []string
+
+	if strings.HasPrefix(rest, string(filepath.Separator)) {
+		parts = append(parts, volume+string(filepath.Separator))
+		rest = rest[1:]
+	} else if len(volume) > 0 {
+		parts = append(parts, volume)
+	}
+
+	for _, part := range strings.Split(rest, string(filepath.Separator)) {
+		if len(part) > 0 {
+			parts = append(parts, part)
+		}
+	}
+
+	return parts
+}
+
+// IsAbsolute returns true if the Path is absolute.
+func (p Path) IsAbsolute() bool {
+	return filepath.IsAbs(string(p))
+}
+
+// Match reports whether the Path matches the glob pattern, without touching the filesystem.  A relative pattern is matched against the end of the Path, so "*.go" matches "src/main.go", while an absolute pattern must match the whole Path.  As in RGlob, a "**" component matches any number of components.
+func (p Path) Match(pattern string) bool {
+	patternParts := Path(pattern).Parts()
+
+	if len(patternParts) == 0 {
+		return false
+	}
+
+	if !Path(pattern).IsAbsolute() {
+		patternParts = append([]string{"**"}, patternParts...)
+	}
+
+	return matchGlobParts(patternParts, p.Parts())
+}
+
 // Parent returns the last directory in the Path. For a file, it returns the directory that the file is in.  For a directory, it just returns the directory, not the directory above it.
 func (p Path) Parent() Path {
 	return Path(filepath.Dir(string(p)))
@@ -265,6 +396,41 @@ func (p Path) WriteBytes(data []byte) error {
 	return nil
 }
 
+// writeBytesAtomic writes the bytes to a temporary file next to the Path and renames it into place, so readers see either the old or the new contents but never a partial write.  The permissions of an existing file are preserved.
+func (p Path) writeBytesAtomic(data []byte, perms os.FileMode) error {
+	if stat, err := os.Stat(string(p)); err == nil {
+		perms = stat.Mode().Perm()
+	}
+
+	tmp, err := ioutil.TempFile(string(p.Parent()), "."+p.Name()+".tmp")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	_, err = tmp.Write(data)
+
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Chmod(tmp.Name(), perms)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), string(p))
+}
+
 // Unlink removes a file Path, but will return an error if the Path is a directory (see Rmdir).
 func (p Path) Unlink() error {
 	if p.IsDir() {
@@ -283,16 +449,68 @@ func (p Path) Rmdir() error {
 	return os.Remove(string(p))
 }
 
-// RmdirRecursive removes a directory and all items within it.
+// RmdirRecursive removes a directory and all items within it.  Failing to remove an item does not stop the rest being removed; the failures are returned together as a MultiError keyed by the items that remain.
 func (p Path) RmdirRecursive() error {
 	if !p.IsDir() {
 		return fmt.Errorf("%s is not a directory.  Use Unlink() instead.", p)
 	}
 
-	return os.RemoveAll(string(p))
+	if err := os.RemoveAll(string(p)); err != nil {
+		// os.RemoveAll carries on past failures but only returns the first, so try again to find them all
+		errs := MultiError{}
+		p.removeRemaining(errs)
+
+		if len(errs) == 0 {
+			return err
+		}
+
+		return errs
+	}
+
+	return nil
+}
+
+// removeRemaining removes what it can of the Path, recording why the rest could not be removed.  Directories that are not empty because their contents could not be removed are not recorded themselves.
+func (p Path) removeRemaining(errs MultiError) bool {
+	info, err := os.Lstat(string(p))
+
+	if os.IsNotExist(err) {
+		return true
+	} else if err != nil {
+		errs.add(p, err)
+		return false
+	}
+
+	if info.IsDir() {
+		entries, err := os.ReadDir(string(p))
+
+		if err != nil {
+			errs.add(p, err)
+			return false
+		}
+
+		empty := true
+
+		for _, entry := range entries {
+			if !p.JoinPath(Path(entry.Name())).removeRemaining(errs) {
+				empty = false
+			}
+		}
+
+		if !empty {
+			return false
+		}
+	}
+
+	if err = os.Remove(string(p)); err != nil && !os.IsNotExist(err) {
+		errs.add(p, err)
+		return false
+	}
+
+	return true
 }
 
-// Rename changes the name of the file to the target Path (essentially a move).
+// Rename changes the name of the file to the target Path (essentially a move).  It fails if the target is on a different filesystem; use Move for that.
 func (p Path) Rename(target Path) error {
 	return os.Rename(string(p), string(target))
 }
@@ -303,6 +521,11 @@ func (p Path) OpenWithPermissions(mode string, perms os.FileMode) (*os.File, err
 		return nil, fmt.Errorf("Cannot open %s because it is a directory.", p)
 	}
 
+	return os.OpenFile(string(p), p.openFlag(mode), perms)
+}
+
+// openFlag translates the mode string used by Open into os.OpenFile flags.
+func (p Path) openFlag(mode string) int {
 	flag := os.O_RDONLY // default to read mode
 
 	if strings.Contains(mode, "r") && strings.Contains(mode, "w") {
@@ -321,7 +544,7 @@ func (p Path) OpenWithPermissions(mode string, perms os.FileMode) (*os.File, err
 		flag |= os.O_CREATE
 	}
 
-	return os.OpenFile(string(p), flag, perms)
+	return flag
 }
 
 // Open opens the Path with the specified mode with 0755 permissions.  If the Path does not exist, it creates it.
@@ -329,7 +552,7 @@ func (p Path) Open(mode string) (*os.File, error) {
 	return p.OpenWithPermissions(mode, 0755)
 }
 
-// RelativeTo returns how this path is relative to the input Path, if at all.
+// RelativeTo returns how this path is relative to the input Path, if at all.  It only compares the Paths as strings, so neither needs to exist.
 func (p Path) RelativeTo(base Path) (Path, error) {
 	relPath, err := filepath.Rel(string(base), string(p))
 
@@ -260,3 +260,64 @@ func TestRelativeTo(t *testing.T) {
 		}
 	}
 }
+
+func TestStemAndSuffixes(t *testing.T) {
+	tests := map[Path][]string{
+		Path("/foo/archive.tar.gz"): {"archive.tar", ".gz", ".tar .gz"},
+		Path("foo/bar.txt"):         {"bar", ".txt", ".txt"},
+		Path("foo/bar"):             {"bar", "", ""},
+		Path(".bashrc"):             {".bashrc", "", ""},
+		Path("foo."):                {"foo.", "", ""},
+	}
+
+	for p, target := range tests {
+		stem, suffix, suffixes := p.Stem(), p.Suffix(), strings.Join(p.Suffixes(), " ")
+
+		if stem != target[0] || suffix != target[1] || suffixes != target[2] {
+			t.Errorf("%s: received %q %q %q, expected %q", p, stem, suffix, suffixes, target)
+		}
+	}
+}
+
+func TestParts(t *testing.T) {
+	tests := map[Path]string{
+		Path("/usr/bin/env"): "[/ usr bin env]",
+		Path("foo//bar/"):    "[foo bar]",
+		Path("../foo"):       "[.. foo]",
+		Path("/"):            "[/]",
+		Path("."):            "[]",
+	}
+
+	for p, target := range tests {
+		if parts := fmt.Sprint(p.Parts()); parts != target {
+			t.Errorf("Parts failed for %s: %s != %s", p, parts, target)
+		}
+	}
+}
+
+func TestIsAbsolute(t *testing.T) {
+	if !Path("/foo/bar").IsAbsolute() || Path("foo/bar").IsAbsolute() {
+		t.Errorf("IsAbsolute failed")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := map[string]bool{
+		"*.go":            true,
+		"src/*.go":        true,
+		"*.txt":           false,
+		"/home/*.go":      false,
+		"/home/**/*.go":   true,
+		"/*/*/*/src/*.go": true,
+		"project/**":      true,
+		"[":               false,
+	}
+
+	p := Path("/home/me/project/src/main.go")
+
+	for pattern, target := range tests {
+		if p.Match(pattern) != target {
+			t.Errorf("Match(%q) on %s should be %v", pattern, p, target)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package pathlib
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// PathID identifies a Path stored in a PathPool.
+type PathID int32
+
+// NoPathID is the parent of top-level components in a PathPool.
+const NoPathID PathID = -1
+
+type pathPoolKey struct {
+	parent PathID
+	name   string
+}
+
+// PathPool stores large numbers of Paths compactly by sharing their common prefixes: each Path is kept as a reference to its parent plus its final component, and component names are interned, so a tree of millions of files costs little more than its distinct names.  Paths are cleaned when added.  A PathPool is not safe for concurrent use.
+type PathPool struct {
+	parents  []PathID
+	names    []string
+	ids      map[pathPoolKey]PathID
+	interned map[string]string
+}
+
+// NewPathPool returns an empty PathPool.
+func NewPathPool() *PathPool {
+	return &PathPool{ids: map[pathPoolKey]PathID{}, interned: map[string]string{}}
+}
+
+// Add stores the Path (and each of its ancestors) in the pool if needed, and returns its ID.
+func (pool *PathPool) Add(p Path) PathID {
+	id := NoPathID
+
+	for _, part := range p.Parts() {
+		key := pathPoolKey{parent: id, name: part}
+		existing, ok := pool.ids[key]
+
+		if !ok {
+			name, ok := pool.interned[part]
+
+			// parts are substrings of p, which would otherwise keep the whole of p alive
+			if !ok {
+				name = strings.Clone(part)
+				pool.interned[name] = name
+			}
+
+			existing = PathID(len(pool.names))
+			pool.parents = append(pool.parents, id)
+			pool.names = append(pool.names, name)
+			pool.ids[pathPoolKey{parent: id, name: name}] = existing
+		}
+
+		id = existing
+	}
+
+	return id
+}
+
+// Lookup returns the ID of the Path, if it is in the pool.
+func (pool *PathPool) Lookup(p Path) (PathID, bool) {
+	id := NoPathID
+
+	for _, part := range p.Parts() {
+		next, ok := pool.ids[pathPoolKey{parent: id, name: part}]
+
+		if !ok {
+			return NoPathID, false
+		}
+
+		id = next
+	}
+
+	return id, id != NoPathID
+}
+
+// Path rebuilds the Path with the ID.
+func (pool *PathPool) Path(id PathID) Path {
+	var parts []string
+
+	for ; id != NoPathID; id = pool.parents[id] {
+		parts = append(parts, pool.names[id])
+	}
+
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+
+	return Path(filepath.Join(parts...))
+}
+
+// Parent returns the ID of the parent of the Path with the ID, or NoPathID for a top-level component.
+func (pool *PathPool) Parent(id PathID) PathID {
+	return pool.parents[id]
+}
+
+// Name returns the final component of the Path with the ID.
+func (pool *PathPool) Name(id PathID) string {
+	return pool.names[id]
+}
+
+// Len returns the number of Paths in the pool, including ancestors that were added implicitly.
+func (pool *PathPool) Len() int {
+	return len(pool.names)
+}
@@ -0,0 +1,57 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPathPool(t *testing.T) {
+	pool := NewPathPool()
+	paths := []Path{"/usr/lib/libc.so", "/usr/lib/libm.so", "/usr/bin/env", "relative/dir/", "/", "/usr/lib/../bin/env"}
+	ids := make([]PathID, len(paths))
+
+	for i, p := range paths {
+		ids[i] = pool.Add(p)
+	}
+
+	expected := []Path{"/usr/lib/libc.so", "/usr/lib/libm.so", "/usr/bin/env", "relative/dir", "/", "/usr/bin/env"}
+
+	for i, id := range ids {
+		if pool.Path(id) != expected[i] {
+			t.Errorf("Expected %s, received %s", expected[i], pool.Path(id))
+		}
+	}
+
+	if ids[2] != ids[5] {
+		t.Errorf("Equivalent Paths received different IDs")
+	}
+
+	// "/", "usr", "lib", "libc.so", "libm.so", "bin", "env", "relative", "dir"
+	if pool.Len() != 9 {
+		t.Errorf("Expected 9 entries, received %d", pool.Len())
+	}
+
+	if id, ok := pool.Lookup("/usr/lib"); !ok || pool.Parent(ids[0]) != id || pool.Name(id) != "lib" {
+		t.Errorf("Lookup of /usr/lib failed")
+	}
+
+	if _, ok := pool.Lookup("/usr/share"); ok {
+		t.Errorf("Lookup found a Path that was never added")
+	}
+
+	if _, ok := pool.Lookup("."); ok {
+		t.Errorf("Lookup found the empty Path")
+	}
+}
+
+func BenchmarkPathPool(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		pool := NewPathPool()
+
+		for dir := 0; dir < 100; dir++ {
+			for file := 0; file < 100; file++ {
+				pool.Add(Path(fmt.Sprintf("/srv/data/project/dir%d/file%d.txt", dir, file)))
+			}
+		}
+	}
+}
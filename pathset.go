@@ -0,0 +1,111 @@
+package pathlib
+
+import (
+	"path/filepath"
+)
+
+// Union returns the Paths in either a or b, cleaned with filepath.Clean and without duplicates, in the order they first appear.
+func Union(a, b []Path) []Path {
+	var union []Path
+	seen := map[Path]bool{}
+
+	for _, paths := range [][]Path{a, b} {
+		for _, p := range paths {
+			p = Path(filepath.Clean(string(p)))
+
+			if !seen[p] {
+				seen[p] = true
+				union = append(union, p)
+			}
+		}
+	}
+
+	return union
+}
+
+// Intersect returns the Paths in both a and b, cleaned with filepath.Clean and without duplicates, in the order they appear in a.
+func Intersect(a, b []Path) []Path {
+	inB := cleanSet(b)
+	return filterPaths(a, func(p Path) bool { return inB[p] })
+}
+
+// Difference returns the Paths in a that are not in b, cleaned with filepath.Clean and without duplicates, in the order they appear in a.
+func Difference(a, b []Path) []Path {
+	inB := cleanSet(b)
+	return filterPaths(a, func(p Path) bool { return !inB[p] })
+}
+
+// UnionTrees returns the fewest Paths from a and b that cover the same trees, treating each Path as everything beneath it: Paths within another Path of either set are dropped, since the other covers them.
+func UnionTrees(a, b []Path) []Path {
+	union := Union(a, b)
+	return filterPaths(union, func(p Path) bool { return !withinAnyOther(p, union) })
+}
+
+// IntersectTrees returns the Paths covering what the trees of a and b have in common, treating each Path as everything beneath it: a Path from either set is kept if it is within a Path of the other set.  Like UnionTrees, the result is reduced to the fewest Paths.
+func IntersectTrees(a, b []Path) []Path {
+	cleanA := Union(a, nil)
+	cleanB := Union(b, nil)
+	var common []Path
+
+	for _, paths := range [][2][]Path{{cleanA, cleanB}, {cleanB, cleanA}} {
+		for _, p := range paths[0] {
+			for _, other := range paths[1] {
+				if p.within(other) {
+					common = append(common, p)
+					break
+				}
+			}
+		}
+	}
+
+	return UnionTrees(common, nil)
+}
+
+// DifferenceTrees returns the Paths in a that are not within any Path in b, treating each Path in b as everything beneath it, as when applying exclude directories to a list of files.
+func DifferenceTrees(a, b []Path) []Path {
+	cleanB := Union(b, nil)
+
+	return filterPaths(a, func(p Path) bool {
+		for _, excluded := range cleanB {
+			if p.within(excluded) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func cleanSet(paths []Path) map[Path]bool {
+	set := make(map[Path]bool, len(paths))
+
+	for _, p := range paths {
+		set[Path(filepath.Clean(string(p)))] = true
+	}
+
+	return set
+}
+
+// filterPaths returns the cleaned, distinct Paths that keep accepts, in order.
+func filterPaths(paths []Path, keep func(Path) bool) []Path {
+	var filtered []Path
+
+	for _, p := range Union(paths, nil) {
+		if keep(p) {
+			filtered = append(filtered, p)
+		}
+	}
+
+	return filtered
+}
+
+// withinAnyOther reports whether the Path is within one of the other Paths.
+func withinAnyOther(p Path, paths []Path) bool {
+	for _, other := range paths {
+		if other != p && p.within(other) {
+			return true
+		}
+	}
+
+	return false
+}
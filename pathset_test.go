@@ -0,0 +1,30 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPathSets(t *testing.T) {
+	a := []Path{"/a/b", "/a/c/", "/a/./d", "/a/b", "/x/y"}
+	b := []Path{"/a/c", "/a/d/e", "/z", "/x/y/"}
+
+	tests := map[string]struct {
+		result   []Path
+		expected []Path
+	}{
+		"Union":           {Union(a, b), []Path{"/a/b", "/a/c", "/a/d", "/x/y", "/a/d/e", "/z"}},
+		"Intersect":       {Intersect(a, b), []Path{"/a/c", "/x/y"}},
+		"Difference":      {Difference(a, b), []Path{"/a/b", "/a/d"}},
+		"UnionTrees":      {UnionTrees(a, b), []Path{"/a/b", "/a/c", "/a/d", "/x/y", "/z"}},
+		"IntersectTrees":  {IntersectTrees([]Path{"/a", "/b/c", "/d"}, []Path{"/a/x", "/a/x/y", "/b", "/e"}), []Path{"/b/c", "/a/x"}},
+		"DifferenceTrees": {DifferenceTrees([]Path{"/a/x.go", "/a/vendor/y.go", "/a/vendorz.go", "/b"}, []Path{"/a/vendor", "/b/"}), []Path{"/a/x.go", "/a/vendorz.go"}},
+		"Empty":           {Intersect(a, nil), nil},
+	}
+
+	for name, test := range tests {
+		if fmt.Sprint(test.result) != fmt.Sprint(test.expected) {
+			t.Errorf("%s: expected %v but got %v", name, test.expected, test.result)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package pathlib
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// Pattern is a compiled glob pattern that can be matched against many Paths without parsing the pattern each time.  Create one with CompilePattern.  It is safe for concurrent use.
+type Pattern struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+// CompilePattern compiles the glob pattern into a reusable Pattern.  The syntax is that of filepath.Match ("*", "?", and character classes such as "[a-z]" or "[^0-9]", which may also be negated with "!"), plus "**" components, which match any number of directories as in RGlob, and braces, which match any of their comma-separated alternatives (eg. "*.{jpg,png}").  Braces may be nested and may contain slashes.  As with Path.Match, a relative pattern matches the end of a Path, so "*.go" matches "src/main.go", while an absolute pattern must match the whole Path.  Matching takes time linear in the length of the Path.  Patterns and Paths are expected to be valid UTF-8, since invalid bytes all match one another.
+func CompilePattern(pattern string) (*Pattern, error) {
+	slashed := filepath.ToSlash(pattern)
+	components, err := splitPattern(slashed)
+
+	if err != nil {
+		return nil, fmt.Errorf("Bad pattern %q: %w", pattern, err)
+	}
+
+	if len(components) == 0 {
+		return &Pattern{pattern: pattern}, nil // like Path.Match, an empty pattern matches nothing
+	}
+
+	if !filepath.IsAbs(pattern) {
+		components = append([]string{"**"}, components...)
+	}
+
+	// consecutive "**" components match the same as one
+	for i := len(components) - 1; i > 0; i-- {
+		if components[i] == "**" && components[i-1] == "**" {
+			components = append(components[:i], components[i+1:]...)
+		}
+	}
+
+	var re strings.Builder
+	re.WriteString("^")
+	sep := ""
+
+	for i, component := range components {
+		if component == "**" {
+			switch {
+			case i == len(components)-1 && i == 0:
+				re.WriteString(".*")
+			case i == len(components)-1:
+				re.WriteString("(?:/.*)?")
+			default:
+				re.WriteString(sep + "(?:.*/)?")
+			}
+
+			sep = ""
+			continue
+		}
+
+		translated, err := translateGlob(component)
+
+		if err != nil {
+			return nil, fmt.Errorf("Bad pattern %q: %w", pattern, err)
+		}
+
+		re.WriteString(sep + translated)
+		sep = "/"
+	}
+
+	re.WriteString("$")
+	compiled, err := regexp.Compile(re.String())
+
+	if err != nil {
+		return nil, fmt.Errorf("Bad pattern %q: %w", pattern, err)
+	}
+
+	return &Pattern{pattern: pattern, re: compiled}, nil
+}
+
+// String returns the pattern the Pattern was compiled from.
+func (p *Pattern) String() string {
+	return p.pattern
+}
+
+// Match reports whether the Path matches the Pattern, without touching the filesystem.
+func (p *Pattern) Match(path Path) bool {
+	return p.re != nil && p.re.MatchString(filepath.ToSlash(filepath.Clean(string(path))))
+}
+
+// Filter returns the Paths that match the Pattern, in order.
+func (p *Pattern) Filter(paths []Path) []Path {
+	var matches []Path
+
+	for _, path := range paths {
+		if p.Match(path) {
+			matches = append(matches, path)
+		}
+	}
+
+	return matches
+}
+
+// splitPattern splits the slash-separated pattern into components at the slashes outside braces, dropping "." and empty components other than the leading one of an absolute pattern.
+func splitPattern(pattern string) ([]string, error) {
+	var components []string
+	depth, start := 0, 0
+
+	for i := 0; i <= len(pattern); i++ {
+		if i < len(pattern) {
+			switch pattern[i] {
+			case '\\':
+				if runtime.GOOS != "windows" && i+1 < len(pattern) {
+					i++
+				}
+
+				continue
+			case '{':
+				depth++
+				continue
+			case '}':
+				if depth > 0 {
+					depth--
+				}
+
+				continue
+			case '/':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+
+		if component := pattern[start:i]; component != "." && (len(component) > 0 || (start == 0 && i < len(pattern))) {
+			components = append(components, component)
+		}
+
+		start = i + 1
+	}
+
+	if depth > 0 {
+		return nil, filepath.ErrBadPattern
+	}
+
+	return components, nil
+}
+
+// translateGlob translates a glob pattern component into a regular expression.
+func translateGlob(glob string) (string, error) {
+	var re strings.Builder
+	runes := []rune(glob)
+	depth := 0
+
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '*':
+			re.WriteString("[^/]*")
+		case '?':
+			re.WriteString("[^/]")
+		case '\\':
+			if runtime.GOOS == "windows" {
+				re.WriteString(regexp.QuoteMeta(string(c)))
+				break
+			}
+
+			if i++; i == len(runes) {
+				return "", filepath.ErrBadPattern
+			}
+
+			re.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case '[':
+			class, end, err := translateClass(runes, i)
+
+			if err != nil {
+				return "", err
+			}
+
+			re.WriteString(class)
+			i = end
+		case '{':
+			depth++
+			re.WriteString("(?:")
+		case ',':
+			if depth > 0 {
+				re.WriteString("|")
+			} else {
+				re.WriteString(",")
+			}
+		case '}':
+			if depth > 0 {
+				depth--
+				re.WriteString(")")
+			} else {
+				re.WriteString(regexp.QuoteMeta("}"))
+			}
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	if depth > 0 {
+		return "", filepath.ErrBadPattern
+	}
+
+	return re.String(), nil
+}
+
+// translateClass translates the character class starting at runes[start], returning the regular expression and the index of its closing bracket.  Negated classes never match a slash.
+func translateClass(runes []rune, start int) (string, int, error) {
+	var class strings.Builder
+	i := start + 1
+
+	if i < len(runes) && (runes[i] == '^' || runes[i] == '!') {
+		class.WriteString("[^/")
+		i++
+	} else {
+		class.WriteString("[")
+	}
+
+	empty := true
+
+	for ; i < len(runes); i++ {
+		c := runes[i]
+
+		if c == ']' && !empty {
+			class.WriteString("]")
+			return class.String(), i, nil
+		}
+
+		if c == '\\' && runtime.GOOS != "windows" {
+			if i++; i == len(runes) {
+				break
+			}
+
+			c = runes[i]
+		} else if c == ']' || c == '-' {
+			break // an empty class, or a range without a start
+		}
+
+		fmt.Fprintf(&class, `\x{%x}`, c)
+		empty = false
+
+		if i+2 < len(runes) && runes[i+1] == '-' && runes[i+2] != ']' {
+			i += 2
+			end := runes[i]
+
+			if end == '\\' && runtime.GOOS != "windows" {
+				if i++; i == len(runes) {
+					break
+				}
+
+				end = runes[i]
+			}
+
+			if end < c {
+				break
+			}
+
+			fmt.Fprintf(&class, `-\x{%x}`, end)
+		}
+	}
+
+	return "", 0, filepath.ErrBadPattern
+}
@@ -0,0 +1,91 @@
+package pathlib
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompilePattern(t *testing.T) {
+	tests := map[string]map[Path]bool{
+		"*.go": {
+			"main.go": true, "src/main.go": true, "main.go.bak": false, "src/go": false,
+		},
+		"src/**/*.go": {
+			"src/main.go": true, "src/a/b/c.go": true, "x/src/a.go": true, "src.go": false, "srcs/a.go": false,
+		},
+		"/var/log/**": {
+			"/var/log": true, "/var/log/syslog": true, "/var/log/nginx/access.log": true, "/var/logs": false, "/x/var/log": false,
+		},
+		"/var/*/*.log": {
+			"/var/log/a.log": true, "/var/log/nginx/a.log": false,
+		},
+		"*.{jpg,png,tar.{gz,xz}}": {
+			"a.jpg": true, "b/c.png": true, "d.tar.gz": true, "e.tar.xz": true, "f.gif": false, "g.tar": false,
+		},
+		"{docs,src/pkg}/*.md": {
+			"docs/a.md": true, "src/pkg/b.md": true, "src/c.md": false,
+		},
+		"file[0-9][!a-c].txt": {
+			"file1d.txt": true, "file1a.txt": false, "filex1.txt": false, "file1/.txt": false,
+		},
+		"?.txt": {
+			"a.txt": true, "ab.txt": false, "dir/b.txt": true,
+		},
+		`a\*b`: {
+			"a*b": true, "axb": false,
+		},
+		"a,b": {
+			"a,b": true, "a": false,
+		},
+		"**/vendor/**": {
+			"vendor": true, "x/vendor/y/z.go": true, "vendors/x": false,
+		},
+	}
+
+	for pattern, paths := range tests {
+		compiled, err := CompilePattern(pattern)
+
+		if err != nil {
+			t.Errorf("%s: %s", pattern, err)
+			continue
+		}
+
+		for path, expected := range paths {
+			if actual := compiled.Match(path); actual != expected {
+				t.Errorf("Expected %s matching %s to be %v but was %v", pattern, path, expected, actual)
+			}
+		}
+	}
+
+	for _, pattern := range []string{"[", "[]", "a[z-a]", "{a,b", `a\`, "[a-"} {
+		if _, err := CompilePattern(pattern); !errors.Is(err, filepath.ErrBadPattern) {
+			t.Errorf("Expected %q to be a bad pattern but got %v", pattern, err)
+		}
+	}
+}
+
+func TestCompilePatternMatchesPathMatch(t *testing.T) {
+	patterns := []string{"*.go", "a/*/c", "**/b", "/a/**/d", "[ab]*", "a/**"}
+	paths := []Path{"a", "a/b", "a/b/c", "a/x/c", "/a/b/c/d", "/a/d", "x.go", "b/x.go", "bz", "a/b/"}
+
+	for _, pattern := range patterns {
+		compiled, err := CompilePattern(pattern)
+
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		for _, path := range paths {
+			if compiled.Match(path) != path.Match(pattern) {
+				t.Errorf("Pattern and Path.Match disagree on %s matching %s", pattern, path)
+			}
+		}
+	}
+
+	compiled, _ := CompilePattern("*.go")
+
+	if filtered := compiled.Filter([]Path{"a.go", "b.txt", "c/d.go"}); len(filtered) != 2 || compiled.String() != "*.go" {
+		t.Errorf("Unexpected filtered Paths %v", filtered)
+	}
+}
@@ -0,0 +1,21 @@
+package pathlib
+
+import (
+	"os"
+)
+
+// IsNamedPipe returns true if the Path is a named pipe (FIFO).  Note that false is returned if the Path does not exist.
+func (p Path) IsNamedPipe() bool {
+	stat, err := os.Stat(string(p))
+
+	if err != nil {
+		return false
+	}
+
+	return stat.Mode()&os.ModeNamedPipe != 0
+}
+
+// DialPipe opens the named pipe Path for writing, connecting to the process that called ListenPipe.
+func (p Path) DialPipe() (*os.File, error) {
+	return os.OpenFile(string(p), os.O_WRONLY, 0)
+}
@@ -0,0 +1,52 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package pathlib
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestNamedPipe(t *testing.T) {
+	p := NamedPipe("pathlib-" + randomString(20))
+	defer p.Unlink()
+
+	go func() {
+		for !p.IsNamedPipe() {
+			time.Sleep(time.Millisecond)
+		}
+
+		w, err := p.DialPipe()
+
+		if err != nil {
+			t.Errorf(err.Error())
+			return
+		}
+
+		w.Write([]byte("over the pipe"))
+		w.Close()
+	}()
+
+	r, err := p.ListenPipe()
+
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	contents, err := ioutil.ReadAll(r)
+	r.Close()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if string(contents) != "over the pipe" {
+		t.Errorf("Read %q from pipe", contents)
+	}
+
+	if !p.IsNamedPipe() || p.IsFile() {
+		t.Errorf("%s should be a named pipe", p)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package pathlib
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// NamedPipe returns the Path for a named pipe called name.  On Unix this is a FIFO within os.TempDir(); on Windows it is within the \\.\pipe\ namespace.
+func NamedPipe(name string) Path {
+	return Path(filepath.Join(os.TempDir(), name))
+}
+
+// Mkfifo creates a named pipe (FIFO) at the Path with the given permissions (subject to umask).
+func (p Path) Mkfifo(perms os.FileMode) error {
+	return syscall.Mkfifo(string(p), uint32(perms.Perm()))
+}
+
+// ListenPipe creates the named pipe Path if it does not already exist and opens it for reading, blocking until a writer connects (see DialPipe).
+func (p Path) ListenPipe() (*os.File, error) {
+	if !p.IsNamedPipe() {
+		if err := p.Mkfifo(0600); err != nil {
+			return nil, err
+		}
+	}
+
+	return os.OpenFile(string(p), os.O_RDONLY, 0)
+}
@@ -0,0 +1,63 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32             = syscall.NewLazyDLL("kernel32.dll")
+	procCreateNamedPipeW = kernel32.NewProc("CreateNamedPipeW")
+	procConnectNamedPipe = kernel32.NewProc("ConnectNamedPipe")
+)
+
+const (
+	pipeAccessInbound  = 0x1
+	errorPipeConnected = syscall.Errno(535)
+	pipeBufferSize     = 4096
+)
+
+// NamedPipe returns the Path for a named pipe called name.  On Unix this is a FIFO within os.TempDir(); on Windows it is within the \\.\pipe\ namespace.
+func NamedPipe(name string) Path {
+	return Path(`\\.\pipe\` + name)
+}
+
+// Mkfifo is not supported on Windows, where named pipes only exist while a server holds them open.  Use ListenPipe instead.
+func (p Path) Mkfifo(perms os.FileMode) error {
+	return fmt.Errorf("Mkfifo is not supported on Windows; use ListenPipe for %s", p)
+}
+
+// ListenPipe creates the named pipe Path and opens it for reading, blocking until a writer connects (see DialPipe).
+func (p Path) ListenPipe() (*os.File, error) {
+	name, err := syscall.UTF16PtrFromString(string(p))
+
+	if err != nil {
+		return nil, err
+	}
+
+	handle, _, err := procCreateNamedPipeW.Call(
+		uintptr(unsafe.Pointer(name)),
+		pipeAccessInbound,
+		0, // PIPE_TYPE_BYTE | PIPE_WAIT
+		1, // a single instance
+		pipeBufferSize,
+		pipeBufferSize,
+		0,
+		0,
+	)
+
+	if syscall.Handle(handle) == syscall.InvalidHandle {
+		return nil, &os.PathError{Op: "CreateNamedPipe", Path: string(p), Err: err}
+	}
+
+	ok, _, err := procConnectNamedPipe.Call(handle, 0)
+
+	if ok == 0 && err != errorPipeConnected {
+		syscall.CloseHandle(syscall.Handle(handle))
+		return nil, &os.PathError{Op: "ConnectNamedPipe", Path: string(p), Err: err}
+	}
+
+	return os.NewFile(handle, string(p)), nil
+}
@@ -0,0 +1,184 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// ErrDenied is returned when an operation is refused by a Policy.
+var ErrDenied = errors.New("denied by policy")
+
+// Policy restricts what may be changed through a PolicyFilesystem.  The zero value imposes no restrictions.
+type Policy struct {
+	// WriteRoots limits writes, renames, and removals to these directories and everything beneath them.  Empty means anywhere.  Roots are compared lexically, so symbolic links within them are not followed.
+	WriteRoots []string
+
+	// DenyDelete protects names matching any of these patterns (as with Path.Match) from being removed, renamed away, or replaced by writes and renames.
+	DenyDelete []string
+
+	// MaxFileSize limits the size of written files.  Zero means unlimited.
+	MaxFileSize int64
+}
+
+// PolicyFilesystem wraps a Filesystem and refuses changes that its Policy does not allow, with errors wrapping ErrDenied.  Reads are passed through.  Since FSPaths derived with JoinPath or Parent share their Filesystem, handing an FSPath on a PolicyFilesystem to a plugin or user script confines everything it derives from it.  Capability interfaces of the wrapped Filesystem, such as Chmoder, are hidden.
+type PolicyFilesystem struct {
+	fsys   Filesystem
+	policy Policy
+}
+
+// NewPolicyFilesystem returns a PolicyFilesystem enforcing the policy on fsys.
+func NewPolicyFilesystem(fsys Filesystem, policy Policy) *PolicyFilesystem {
+	return &PolicyFilesystem{fsys: fsys, policy: policy}
+}
+
+// Restrict returns the Path bound to the OS Filesystem with the policy enforced.
+func Restrict(p Path, policy Policy) FSPath {
+	return PathOn(policyOS{NewPolicyFilesystem(OS, policy)}, string(p))
+}
+
+// policyOS is a PolicyFilesystem over OS, which keeps taking native OS paths.
+type policyOS struct {
+	*PolicyFilesystem
+}
+
+func (policyOS) osNames() {}
+
+// checkWrite returns an error unless the name is within a write root.
+func (f *PolicyFilesystem) checkWrite(op, name string) error {
+	if len(f.policy.WriteRoots) == 0 {
+		return nil
+	}
+
+	for _, root := range f.policy.WriteRoots {
+		if Path(filepath.FromSlash(name)).within(Path(filepath.FromSlash(root))) {
+			return nil
+		}
+	}
+
+	return &fs.PathError{Op: op, Path: name, Err: fmt.Errorf("%w: outside the writable roots", ErrDenied)}
+}
+
+// checkDelete returns an error unless the name may be removed.
+func (f *PolicyFilesystem) checkDelete(op, name string) error {
+	if err := f.checkWrite(op, name); err != nil {
+		return err
+	}
+
+	for _, pattern := range f.policy.DenyDelete {
+		if Path(filepath.FromSlash(name)).Match(pattern) {
+			return &fs.PathError{Op: op, Path: name, Err: fmt.Errorf("%w: protected by %q", ErrDenied, pattern)}
+		}
+	}
+
+	return nil
+}
+
+// checkDeleteTree returns an error unless the name and everything within it may be removed.
+func (f *PolicyFilesystem) checkDeleteTree(op, name string) error {
+	if err := f.checkDelete(op, name); err != nil {
+		return err
+	}
+
+	if len(f.policy.DenyDelete) == 0 {
+		return nil
+	}
+
+	err := fs.WalkDir(f.fsys, name, func(child string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		return f.checkDelete(op, child)
+	})
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// checkReplace returns an error if the name exists and may not be removed, since replacing it would remove it.
+func (f *PolicyFilesystem) checkReplace(op, name string) error {
+	if _, err := f.fsys.Stat(name); err != nil {
+		return nil
+	}
+
+	return f.checkDelete(op, name)
+}
+
+func (f *PolicyFilesystem) Open(name string) (fs.File, error) {
+	return f.fsys.Open(name)
+}
+
+func (f *PolicyFilesystem) Stat(name string) (fs.FileInfo, error) {
+	return f.fsys.Stat(name)
+}
+
+func (f *PolicyFilesystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return f.fsys.ReadDir(name)
+}
+
+func (f *PolicyFilesystem) ReadFile(name string) ([]byte, error) {
+	return f.fsys.ReadFile(name)
+}
+
+func (f *PolicyFilesystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if err := f.checkWrite("write", name); err != nil {
+		return err
+	}
+
+	if f.policy.MaxFileSize > 0 && int64(len(data)) > f.policy.MaxFileSize {
+		return &fs.PathError{Op: "write", Path: name, Err: fmt.Errorf("%w: %d bytes exceeds the %d byte limit", ErrDenied, len(data), f.policy.MaxFileSize)}
+	}
+
+	if err := f.checkReplace("write", name); err != nil {
+		return err
+	}
+
+	return f.fsys.WriteFile(name, data, perm)
+}
+
+func (f *PolicyFilesystem) MkdirAll(name string, perm fs.FileMode) error {
+	if err := f.checkWrite("mkdir", name); err != nil {
+		return err
+	}
+
+	return f.fsys.MkdirAll(name, perm)
+}
+
+func (f *PolicyFilesystem) Remove(name string) error {
+	if err := f.checkDelete("remove", name); err != nil {
+		return err
+	}
+
+	return f.fsys.Remove(name)
+}
+
+// RemoveAll also refuses to remove a directory containing protected names.
+func (f *PolicyFilesystem) RemoveAll(name string) error {
+	if err := f.checkDeleteTree("remove", name); err != nil {
+		return err
+	}
+
+	return f.fsys.RemoveAll(name)
+}
+
+// Rename also refuses to move a directory containing protected names, or to replace a protected name.
+func (f *PolicyFilesystem) Rename(oldname, newname string) error {
+	if err := f.checkDeleteTree("rename", oldname); err != nil {
+		return err
+	}
+
+	if err := f.checkWrite("rename", newname); err != nil {
+		return err
+	}
+
+	if err := f.checkReplace("rename", newname); err != nil {
+		return err
+	}
+
+	return f.fsys.Rename(oldname, newname)
+}
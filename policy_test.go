@@ -0,0 +1,73 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRestrict(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer root.RmdirRecursive()
+
+	err := CreateTree(root, TreeSpec{
+		{Path: "output", Dir: true},
+		{Path: "data/state.db", Content: "db"},
+		{Path: "data/cache/tmp.txt", Content: "tmp"},
+		{Path: "data/cache/keep.db", Content: "db"},
+		{Path: "input.txt", Content: "input"},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	policy := Policy{
+		WriteRoots:  []string{string(root.JoinPath("output")), string(root.JoinPath("data"))},
+		DenyDelete:  []string{"*.db"},
+		MaxFileSize: 10,
+	}
+
+	// the plugin only receives the restricted root, and derives everything from it
+	plugin := Restrict(root, policy)
+
+	allowed := map[string]error{
+		"write":         plugin.JoinPath("output", "result.txt").WriteBytes([]byte("ok")),
+		"read":          func() error { _, err := plugin.JoinPath("input.txt").ReadBytes(); return err }(),
+		"mkdir":         plugin.JoinPath("output", "sub").Mkdir(),
+		"delete":        plugin.JoinPath("data", "cache", "tmp.txt").Unlink(),
+		"rename within": plugin.JoinPath("output", "result.txt").Rename(plugin.JoinPath("data", "result.txt")),
+	}
+
+	for name, err := range allowed {
+		if err != nil {
+			t.Errorf("Expected %s to be allowed: %v", name, err)
+		}
+	}
+
+	denied := map[string]error{
+		"write outside":  plugin.JoinPath("input.txt").WriteBytes([]byte("oops")),
+		"escape":         plugin.JoinPath("output", "..", "input.txt").WriteBytes([]byte("oops")),
+		"too large":      plugin.JoinPath("output", "big").WriteBytes([]byte("more than ten bytes")),
+		"protected":      plugin.JoinPath("data", "state.db").Unlink(),
+		"rename away":    plugin.JoinPath("data", "state.db").Rename(plugin.JoinPath("data", "state.old")),
+		"recursive":      plugin.JoinPath("data", "cache").RmdirRecursive(),
+		"mkdir outside":  plugin.JoinPath("elsewhere").Mkdir(),
+		"delete outside": plugin.JoinPath("input.txt").Unlink(),
+		"rename over":    plugin.JoinPath("data", "result.txt").Rename(plugin.JoinPath("data", "cache", "keep.db")),
+		"overwrite":      plugin.JoinPath("data", "state.db").WriteBytes(nil),
+		"move protected": plugin.JoinPath("data", "cache").Rename(plugin.JoinPath("output", "cache")),
+	}
+
+	for name, err := range denied {
+		if !errors.Is(err, ErrDenied) {
+			t.Errorf("Expected %s to be denied, received %v", name, err)
+		}
+	}
+
+	for _, name := range []Path{"data/state.db", "data/cache/keep.db"} {
+		if contents, _ := root.JoinPath(name).ReadBytes(); string(contents) != "db" {
+			t.Errorf("Protected file %s was removed or replaced: %q", name, contents)
+		}
+	}
+}
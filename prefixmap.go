@@ -0,0 +1,78 @@
+package pathlib
+
+import (
+	"iter"
+	"path/filepath"
+	"sort"
+)
+
+// PrefixMap maps Path prefixes to values, looking up the value of the longest prefix containing a Path, for routing requests, applying per-directory policies, and scoping configuration.  Prefixes match whole components, so "/srv/www" contains "/srv/www/index.html" but not "/srv/www2".  Paths are cleaned with filepath.Clean, and "/" (or "." for relative Paths) contains everything.  The zero value is an empty map ready to use.  Like a built-in map, it is safe for concurrent reads but not for concurrent writes.
+type PrefixMap[V any] struct {
+	entries map[Path]V
+}
+
+// Set maps the prefix to the value, replacing any value it already had.
+func (m *PrefixMap[V]) Set(prefix Path, value V) {
+	if m.entries == nil {
+		m.entries = make(map[Path]V)
+	}
+
+	m.entries[Path(filepath.Clean(string(prefix)))] = value
+}
+
+// Get returns the value of exactly the prefix.
+func (m *PrefixMap[V]) Get(prefix Path) (V, bool) {
+	value, ok := m.entries[Path(filepath.Clean(string(prefix)))]
+	return value, ok
+}
+
+// Delete removes the prefix.
+func (m *PrefixMap[V]) Delete(prefix Path) {
+	delete(m.entries, Path(filepath.Clean(string(prefix))))
+}
+
+// Len returns the number of prefixes.
+func (m *PrefixMap[V]) Len() int {
+	return len(m.entries)
+}
+
+// Lookup returns the longest prefix that contains the Path (or is the Path itself) along with its value, and false if there is none.  It takes time proportional to the depth of the Path, not the number of prefixes.
+func (m *PrefixMap[V]) Lookup(p Path) (Path, V, bool) {
+	dir := filepath.Clean(string(p))
+
+	for len(m.entries) > 0 {
+		if value, ok := m.entries[Path(dir)]; ok {
+			return Path(dir), value, true
+		}
+
+		parent := filepath.Dir(dir)
+
+		if parent == dir {
+			break
+		}
+
+		dir = parent
+	}
+
+	var zero V
+	return "", zero, false
+}
+
+// All returns an iterator over the prefixes and their values, sorted by prefix.
+func (m *PrefixMap[V]) All() iter.Seq2[Path, V] {
+	return func(yield func(Path, V) bool) {
+		prefixes := make([]Path, 0, len(m.entries))
+
+		for prefix := range m.entries {
+			prefixes = append(prefixes, prefix)
+		}
+
+		sort.Slice(prefixes, func(i, j int) bool { return prefixes[i] < prefixes[j] })
+
+		for _, prefix := range prefixes {
+			if !yield(prefix, m.entries[prefix]) {
+				return
+			}
+		}
+	}
+}
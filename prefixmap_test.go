@@ -0,0 +1,65 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPrefixMap(t *testing.T) {
+	var m PrefixMap[string]
+
+	if _, _, ok := m.Lookup("/srv"); ok {
+		t.Errorf("Expected an empty map to match nothing")
+	}
+
+	m.Set("/", "root")
+	m.Set("/srv/www/", "www")
+	m.Set("/srv/www/static", "static")
+	m.Set("docs", "docs")
+
+	tests := map[Path]struct {
+		prefix Path
+		value  string
+	}{
+		"/srv/www/index.html":      {"/srv/www", "www"},
+		"/srv/www":                 {"/srv/www", "www"},
+		"/srv/www2/index.html":     {"/", "root"},
+		"/srv/www/static/app.js":   {"/srv/www/static", "static"},
+		"/srv/www/static/../x.txt": {"/srv/www", "www"},
+		"/etc/passwd":              {"/", "root"},
+		"docs/guide.md":            {"docs", "docs"},
+		"./docs":                   {"docs", "docs"},
+	}
+
+	for p, expected := range tests {
+		prefix, value, ok := m.Lookup(p)
+
+		if !ok || prefix != expected.prefix || value != expected.value {
+			t.Errorf("Expected %s to match %s (%s) but matched %s (%s, %v)", p, expected.prefix, expected.value, prefix, value, ok)
+		}
+	}
+
+	if _, _, ok := m.Lookup("documents/x"); ok {
+		t.Errorf("Expected relative Paths outside docs not to match")
+	}
+
+	if value, ok := m.Get("/srv/www"); !ok || value != "www" {
+		t.Errorf("Expected an exact match for /srv/www")
+	}
+
+	m.Delete("/srv/www/static/")
+
+	if prefix, _, _ := m.Lookup("/srv/www/static/app.js"); prefix != "/srv/www" || m.Len() != 3 {
+		t.Errorf("Expected the deleted prefix to no longer match but matched %s", prefix)
+	}
+
+	var all []string
+
+	for prefix, value := range m.All() {
+		all = append(all, fmt.Sprintf("%s=%s", prefix, value))
+	}
+
+	if fmt.Sprint(all) != "[/=root /srv/www=www docs=docs]" {
+		t.Errorf("Unexpected entries %v", all)
+	}
+}
@@ -0,0 +1,182 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// PrivilegedOps performs the operations that usually require root: changing ownership, creating device nodes, and setting the immutable flag.  Features such as CopyTree's Archive mode go through it, so they can be tested unprivileged by substituting a FakePrivilegedOps with WithPrivilegedOps.
+type PrivilegedOps interface {
+	// Lchown changes the user and group of the Path, without following a symbolic link.
+	Lchown(p Path, uid, gid int) error
+
+	// Mknod creates a device node at the Path, as Path.Mknod does.
+	Mknod(p Path, mode os.FileMode, major, minor uint32) error
+
+	// IsImmutable returns true if the Path has the immutable flag, as Path.IsImmutable does.
+	IsImmutable(p Path) (bool, error)
+
+	// SetImmutable sets or clears the immutable flag of the Path, as Path.SetImmutable does.
+	SetImmutable(p Path, immutable bool) error
+}
+
+// HostPrivilegedOps performs privileged operations on the real filesystem.  It is the default.
+var HostPrivilegedOps PrivilegedOps = hostPrivilegedOps{}
+
+type hostPrivilegedOps struct{}
+
+func (hostPrivilegedOps) Lchown(p Path, uid, gid int) error {
+	return os.Lchown(string(p), uid, gid)
+}
+
+func (hostPrivilegedOps) Mknod(p Path, mode os.FileMode, major, minor uint32) error {
+	return p.Mknod(mode, major, minor)
+}
+
+func (hostPrivilegedOps) IsImmutable(p Path) (bool, error) {
+	return p.IsImmutable()
+}
+
+func (hostPrivilegedOps) SetImmutable(p Path, immutable bool) error {
+	return p.SetImmutable(immutable)
+}
+
+// FakeDevice describes a device node created by a FakePrivilegedOps.
+type FakeDevice struct {
+	Mode  os.FileMode
+	Major uint32
+	Minor uint32
+}
+
+// FakeOwner describes the ownership given to a Path by a FakePrivilegedOps.
+type FakeOwner struct {
+	UID int
+	GID int
+}
+
+// FakePrivilegedOps records privileged operations instead of performing them, so code using them can be tested without root.  Device nodes are created as empty regular files, so that later steps find something at the Path.  Operations on Paths that do not exist fail as they would for real.  The zero value is ready to use, and it is safe for concurrent use.
+type FakePrivilegedOps struct {
+	// Fail, if set, is called before each operation ("lchown", "mknod", or "setimmutable"), and a non-nil result is returned instead of performing it.
+	Fail func(op string, p Path) error
+
+	mu        sync.Mutex
+	owners    map[Path]FakeOwner
+	devices   map[Path]FakeDevice
+	immutable map[Path]bool
+}
+
+func (f *FakePrivilegedOps) check(op string, p Path, mustExist bool) error {
+	if f.Fail != nil {
+		if err := f.Fail(op, p); err != nil {
+			return err
+		}
+	}
+
+	if mustExist && !p.lexists() {
+		return &os.PathError{Op: op, Path: string(p), Err: os.ErrNotExist}
+	}
+
+	return nil
+}
+
+func (f *FakePrivilegedOps) Lchown(p Path, uid, gid int) error {
+	if err := f.check("lchown", p, true); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.owners == nil {
+		f.owners = map[Path]FakeOwner{}
+	}
+
+	f.owners[p] = FakeOwner{UID: uid, GID: gid}
+	return nil
+}
+
+func (f *FakePrivilegedOps) Mknod(p Path, mode os.FileMode, major, minor uint32) error {
+	if _, err := mknodMode(mode); err != nil {
+		return err
+	}
+
+	if err := f.check("mknod", p, false); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(string(p), os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm())
+
+	if err != nil {
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.devices == nil {
+		f.devices = map[Path]FakeDevice{}
+	}
+
+	f.devices[p] = FakeDevice{Mode: mode, Major: major, Minor: minor}
+	return nil
+}
+
+func (f *FakePrivilegedOps) SetImmutable(p Path, immutable bool) error {
+	if err := f.check("setimmutable", p, true); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.immutable == nil {
+		f.immutable = map[Path]bool{}
+	}
+
+	if immutable {
+		f.immutable[p] = true
+	} else {
+		delete(f.immutable, p)
+	}
+
+	return nil
+}
+
+// Owner returns the ownership last given to the Path, if any.
+func (f *FakePrivilegedOps) Owner(p Path) (FakeOwner, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	owner, ok := f.owners[p]
+	return owner, ok
+}
+
+// Device returns the device node created at the Path, if any.
+func (f *FakePrivilegedOps) Device(p Path) (FakeDevice, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	device, ok := f.devices[p]
+	return device, ok
+}
+
+// IsImmutable returns true if the Path was last made immutable by SetImmutable.  Real immutable flags are not consulted.
+func (f *FakePrivilegedOps) IsImmutable(p Path) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.immutable[p], nil
+}
+
+// String summarizes what was recorded, for test failure messages.
+func (f *FakePrivilegedOps) String() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return fmt.Sprintf("owners %v, devices %v, immutable %v", f.owners, f.devices, f.immutable)
+}
@@ -0,0 +1,138 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+)
+
+func TestArchiveWithFakePrivilegedOps(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{{Path: "src/file", Content: "hello"}, {Path: "src/sub/locked", Content: "keep"}})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	fake := &FakePrivilegedOps{}
+	src, dst := dir.JoinPath("src"), dir.JoinPath("dst")
+
+	if err = fake.SetImmutable(src.JoinPath("sub/locked"), true); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if err = src.CopyTree(dst, Archive(), WithPrivilegedOps(fake)); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if runtime.GOOS != "windows" {
+		for _, p := range []Path{dst, dst.JoinPath("file"), dst.JoinPath("sub/locked")} {
+			if owner, ok := fake.Owner(p); !ok || owner.UID != os.Getuid() || owner.GID != os.Getgid() {
+				t.Errorf("%s should be owned by %d:%d: %s", p, os.Getuid(), os.Getgid(), fake)
+			}
+		}
+	}
+
+	if immutable, _ := fake.IsImmutable(dst.JoinPath("sub/locked")); !immutable {
+		t.Errorf("The immutable flag should be copied: %s", fake)
+	}
+
+	if immutable, _ := fake.IsImmutable(dst.JoinPath("file")); immutable {
+		t.Errorf("Only immutable files should be made immutable: %s", fake)
+	}
+}
+
+func TestArchiveReportsFailedPrivilegedOps(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(dir, TreeSpec{{Path: "src/file", Content: "hello"}})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	src, dst := dir.JoinPath("src"), dir.JoinPath("dst")
+	fake := &FakePrivilegedOps{Fail: func(op string, p Path) error {
+		if op == "setimmutable" && p.Parent() != src {
+			return &os.PathError{Op: op, Path: string(p), Err: syscall.EPERM}
+		}
+
+		return nil
+	}}
+
+	fake.SetImmutable(src.JoinPath("file"), true)
+	var report CopyReport
+
+	if err = src.CopyTree(dst, Archive(), WithPrivilegedOps(fake), ReportUnapplied(&report)); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if len(report.Unapplied) != 1 || report.Unapplied[0].Attribute != "immutable" || !errors.Is(report.Unapplied[0].Err, syscall.EPERM) {
+		t.Errorf("The immutable flag should be reported as unapplied: %+v", report.Unapplied)
+	}
+
+	if err = src.CopyTree(dir.JoinPath("other"), Archive(), WithPrivilegedOps(fake)); !errors.Is(err, syscall.EPERM) {
+		t.Errorf("Without a report, failing to set the immutable flag should fail the copy, got %v", err)
+	}
+}
+
+func TestArchiveCopiesDevices(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("Device nodes are not supported on this platform")
+	}
+
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := dir.Mkdir(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	fake := &FakePrivilegedOps{}
+	dst := dir.JoinPath("null")
+
+	if err := Path("/dev/null").Copy(dst, Archive(), WithPrivilegedOps(fake)); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	device, ok := fake.Device(dst)
+	major, minor, _ := deviceNumbers(mustLstat(t, "/dev/null"))
+
+	if !ok || device.Mode&os.ModeCharDevice == 0 || device.Major != major || device.Minor != minor {
+		t.Errorf("%s should be recorded as a copy of /dev/null (%d, %d): %s", dst, major, minor, fake)
+	}
+
+	if err := Path("/dev/null").Copy(dir.JoinPath("plain")); err == nil {
+		t.Errorf("Devices should only be copied in archive mode")
+	}
+}
+
+func TestFakePrivilegedOpsMissingPath(t *testing.T) {
+	fake := &FakePrivilegedOps{}
+	missing := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := fake.Lchown(missing, 0, 0); !os.IsNotExist(err) {
+		t.Errorf("Lchown of a missing Path should fail with ErrNotExist, got %v", err)
+	}
+
+	if err := fake.Mknod(missing, 0600, 1, 3); err == nil {
+		t.Errorf("Mknod should fail for modes that are not devices")
+	}
+}
+
+func mustLstat(t *testing.T, p Path) os.FileInfo {
+	info, err := os.Lstat(string(p))
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	return info
+}
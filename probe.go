@@ -0,0 +1,20 @@
+package pathlib
+
+import (
+	"os"
+
+	"github.com/gershwinlabs/pathlib/media"
+)
+
+// ProbeMedia returns basic metadata about the media file Path, such as image dimensions or audio/video duration, by parsing its headers.  See media.Probe for the supported formats.
+func (p Path) ProbeMedia() (media.Info, error) {
+	f, err := os.Open(string(p))
+
+	if err != nil {
+		return media.Info{}, err
+	}
+
+	defer f.Close()
+
+	return media.Probe(f)
+}
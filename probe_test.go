@@ -0,0 +1,39 @@
+package pathlib
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/gershwinlabs/pathlib/media"
+)
+
+func TestProbeMedia(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s.png", randomString(20)))
+	f, err := os.Create(string(p))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	err = png.Encode(f, image.NewRGBA(image.Rect(0, 0, 10, 20)))
+	f.Close()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	info, err := p.ProbeMedia()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if info.Kind != media.Image || info.Width != 10 || info.Height != 20 {
+		t.Errorf("Incorrect media info: %+v", info)
+	}
+}
@@ -0,0 +1,132 @@
+package procfs
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gershwinlabs/pathlib"
+)
+
+// Proc is the /proc directory of a single process.
+type Proc struct {
+	pathlib.Path
+	PID int
+}
+
+// ProcPID returns the Proc of the process with the PID.
+func ProcPID(pid int) Proc {
+	return Proc{Path: ProcRoot.JoinPath(pathlib.Path(strconv.Itoa(pid))), PID: pid}
+}
+
+// Self returns the Proc of the calling process.
+func Self() Proc {
+	self := ProcRoot.JoinPath("self")
+	target, err := os.Readlink(string(self))
+
+	if err != nil {
+		return Proc{Path: self}
+	}
+
+	pid, _ := strconv.Atoi(target)
+	return Proc{Path: self, PID: pid}
+}
+
+// Procs returns every process visible in ProcRoot.
+func Procs() ([]Proc, error) {
+	entries, err := os.ReadDir(string(ProcRoot))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var procs []Proc
+
+	for _, entry := range entries {
+		if pid, err := strconv.Atoi(entry.Name()); err == nil {
+			procs = append(procs, ProcPID(pid))
+		}
+	}
+
+	return procs, nil
+}
+
+// Cmdline returns the command-line arguments of the process.  Kernel threads have none.
+func (p Proc) Cmdline() ([]string, error) {
+	contents, err := p.JoinPath("cmdline").ReadBytes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseNulSeparated(contents), nil
+}
+
+// Environ returns the initial environment of the process as "KEY=value" strings.
+func (p Proc) Environ() ([]string, error) {
+	contents, err := p.JoinPath("environ").ReadBytes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseNulSeparated(contents), nil
+}
+
+// Comm returns the command name of the process, which the kernel truncates to 15 bytes.
+func (p Proc) Comm() (string, error) {
+	return ReadString(p.JoinPath("comm"))
+}
+
+// Exe returns the Path of the executable the process is running.
+func (p Proc) Exe() (pathlib.Path, error) {
+	return readlink(p.JoinPath("exe"))
+}
+
+// Cwd returns the working directory of the process.
+func (p Proc) Cwd() (pathlib.Path, error) {
+	return readlink(p.JoinPath("cwd"))
+}
+
+// Status returns the fields of /proc/PID/status, such as "Name", "State", and "VmRSS".
+func (p Proc) Status() (map[string]string, error) {
+	contents, err := p.JoinPath("status").ReadBytes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseKeyValues(contents), nil
+}
+
+// Fds returns the Paths the process's open descriptors refer to, keyed by descriptor number.  Descriptors that are not files, such as sockets, map to names like "socket:[1234]".
+func (p Proc) Fds() (map[int]pathlib.Path, error) {
+	fdDir := p.JoinPath("fd")
+	entries, err := os.ReadDir(string(fdDir))
+
+	if err != nil {
+		return nil, err
+	}
+
+	fds := make(map[int]pathlib.Path, len(entries))
+
+	for _, entry := range entries {
+		fd, err := strconv.Atoi(entry.Name())
+
+		if err != nil {
+			continue
+		}
+
+		// descriptors may close while they are listed
+		if target, err := readlink(fdDir.JoinPath(pathlib.Path(entry.Name()))); err == nil {
+			fds[fd] = pathlib.Path(strings.TrimSuffix(string(target), " (deleted)"))
+		}
+	}
+
+	return fds, nil
+}
+
+func readlink(p pathlib.Path) (pathlib.Path, error) {
+	target, err := os.Readlink(string(p))
+	return pathlib.Path(target), err
+}
@@ -0,0 +1,79 @@
+// Package procfs provides typed Paths for common locations under /proc and /sys on Linux, with helpers for parsing the small text files found there.
+package procfs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gershwinlabs/pathlib"
+)
+
+// ProcRoot and SysRoot are where procfs and sysfs are mounted.  They may be changed to read from a copy, such as a test fixture or a container's view.
+var (
+	ProcRoot = pathlib.Path("/proc")
+	SysRoot  = pathlib.Path("/sys")
+)
+
+// ReadString reads a single-value file such as /sys/block/sda/size, without its trailing newline.
+func ReadString(p pathlib.Path) (string, error) {
+	contents, err := p.ReadBytes()
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(contents), "\n"), nil
+}
+
+// ReadInt reads a file holding a single decimal integer.
+func ReadInt(p pathlib.Path) (int64, error) {
+	value, err := ReadString(p)
+
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("Cannot parse %s as an integer: %w", p, err)
+	}
+
+	return n, nil
+}
+
+// ReadBool reads a file holding "0" or "1", as sysfs uses for flags.
+func ReadBool(p pathlib.Path) (bool, error) {
+	n, err := ReadInt(p)
+	return n != 0, err
+}
+
+// ParseKeyValues parses "Key: value" lines, as found in /proc/PID/status and /proc/meminfo.  Lines without the separator are skipped.
+func ParseKeyValues(data []byte) map[string]string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+
+		if found {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	return values
+}
+
+// ParseNulSeparated splits NUL-separated data, as found in /proc/PID/cmdline and /proc/PID/environ, dropping the trailing terminator.
+func ParseNulSeparated(data []byte) []string {
+	data = bytes.TrimSuffix(data, []byte{0})
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	return strings.Split(string(data), "\x00")
+}
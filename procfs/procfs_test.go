@@ -0,0 +1,116 @@
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/gershwinlabs/pathlib"
+)
+
+func TestParseKeyValues(t *testing.T) {
+	values := ParseKeyValues([]byte("Name:\tbash\nState:\tS (sleeping)\nbogus\nVmRSS:\t  1024 kB\n"))
+
+	if values["Name"] != "bash" || values["State"] != "S (sleeping)" || values["VmRSS"] != "1024 kB" || len(values) != 3 {
+		t.Errorf("Unexpected values %v", values)
+	}
+}
+
+func TestParseNulSeparated(t *testing.T) {
+	tests := map[string]string{
+		"ls\x00-l\x00": "[ls -l]",
+		"":             "[]",
+		"a\x00\x00b":   "[a  b]",
+	}
+
+	for data, target := range tests {
+		if parsed := fmt.Sprint(ParseNulSeparated([]byte(data))); parsed != target {
+			t.Errorf("ParseNulSeparated(%q): %s != %s", data, parsed, target)
+		}
+	}
+}
+
+func TestSelf(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("procfs is only available on Linux")
+	}
+
+	self := Self()
+
+	if self.PID != os.Getpid() {
+		t.Errorf("Expected PID %d, received %d", os.Getpid(), self.PID)
+	}
+
+	cmdline, err := ProcPID(os.Getpid()).Cmdline()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if len(cmdline) != len(os.Args) || cmdline[0] != os.Args[0] {
+		t.Errorf("Expected %q, received %q", os.Args, cmdline)
+	}
+
+	status, err := self.Status()
+
+	if err != nil || status["Pid"] != fmt.Sprint(os.Getpid()) {
+		t.Errorf("Unexpected status %v: %v", status, err)
+	}
+
+	if fds, err := self.Fds(); err != nil || fds[0] == "" {
+		t.Errorf("Expected stdin in %v: %v", fds, err)
+	}
+}
+
+func TestSysBlock(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pathlib-")
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	root := pathlib.Path(dir)
+	defer root.RmdirRecursive()
+
+	err = pathlib.CreateTree(root, pathlib.TreeSpec{
+		{Path: "block/sda/size", Content: "2048\n"},
+		{Path: "block/sda/removable", Content: "0\n"},
+		{Path: "block/sda/device/model", Content: "Samsung SSD     \n"},
+		{Path: "block/sda/sda2/partition", Content: "2\n"},
+		{Path: "block/sda/sda2/size", Content: "1024\n"},
+		{Path: "block/sda/sda1/partition", Content: "1\n"},
+		{Path: "block/sda/queue/rotational", Content: "0\n"},
+		{Path: "block/sda/holders", Dir: true},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer func(original pathlib.Path) { SysRoot = original }(SysRoot)
+	SysRoot = root
+	sda := SysBlock("sda")
+
+	if size, err := sda.Size(); err != nil || size != 2048*512 {
+		t.Errorf("Unexpected size %d: %v", size, err)
+	}
+
+	if model, err := sda.Model(); err != nil || model != "Samsung SSD" {
+		t.Errorf("Unexpected model %q: %v", model, err)
+	}
+
+	if removable, err := sda.Removable(); err != nil || removable {
+		t.Errorf("Unexpected removable %v: %v", removable, err)
+	}
+
+	partitions, err := sda.Partitions()
+
+	if err != nil || len(partitions) != 2 || partitions[0].Name() != "sda1" || partitions[1].Dev() != "/dev/sda2" {
+		t.Errorf("Unexpected partitions %v: %v", partitions, err)
+	}
+
+	if model, err := partitions[1].Model(); err != nil || model != "" {
+		t.Errorf("Expected no model for a partition, received %q: %v", model, err)
+	}
+}
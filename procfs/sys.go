@@ -0,0 +1,114 @@
+package procfs
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gershwinlabs/pathlib"
+)
+
+// sectorSize is the unit of sysfs block sizes, regardless of the device's own sector size.
+const sectorSize = 512
+
+// Block is the sysfs directory of a block device or partition, such as /sys/block/sda or /sys/block/sda/sda1.
+type Block struct {
+	pathlib.Path
+}
+
+// SysBlock returns the Block of the device, named as in /dev (such as "sda" or "nvme0n1").
+func SysBlock(dev string) Block {
+	return Block{SysRoot.JoinPath("block", pathlib.Path(dev))}
+}
+
+// SysBlocks returns every block device in SysRoot, sorted by name.  Partitions are not included; see Partitions.
+func SysBlocks() ([]Block, error) {
+	entries, err := os.ReadDir(string(SysRoot.JoinPath("block")))
+
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]Block, 0, len(entries))
+
+	for _, entry := range entries {
+		blocks = append(blocks, SysBlock(entry.Name()))
+	}
+
+	return blocks, nil
+}
+
+// Dev returns the device node of the Block, such as /dev/sda.
+func (b Block) Dev() pathlib.Path {
+	return pathlib.Path("/dev").JoinPath(pathlib.Path(b.Name()))
+}
+
+// Size returns the size of the device in bytes.
+func (b Block) Size() (int64, error) {
+	sectors, err := ReadInt(b.JoinPath("size"))
+	return sectors * sectorSize, err
+}
+
+// Model returns the model name reported by the device, which is empty for virtual devices and partitions.
+func (b Block) Model() (string, error) {
+	model, err := ReadString(b.JoinPath("device", "model"))
+
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+
+	return strings.TrimSpace(model), err
+}
+
+// Removable returns true if the device has removable media.
+func (b Block) Removable() (bool, error) {
+	return ReadBool(b.JoinPath("removable"))
+}
+
+// ReadOnly returns true if the device is read-only.
+func (b Block) ReadOnly() (bool, error) {
+	return ReadBool(b.JoinPath("ro"))
+}
+
+// Partitions returns the partitions of the device, sorted by name.
+func (b Block) Partitions() ([]Block, error) {
+	entries, err := os.ReadDir(string(b.Path))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var partitions []Block
+
+	for _, entry := range entries {
+		partition := b.JoinPath(pathlib.Path(entry.Name()))
+
+		if partition.JoinPath("partition").Exists() {
+			partitions = append(partitions, Block{partition})
+		}
+	}
+
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].Path < partitions[j].Path })
+	return partitions, nil
+}
+
+// Holders returns the devices built on top of this one, such as device-mapper or RAID volumes.
+func (b Block) Holders() ([]string, error) {
+	entries, err := os.ReadDir(string(b.JoinPath("holders")))
+
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	holders := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		holders = append(holders, entry.Name())
+	}
+
+	return holders, nil
+}
@@ -0,0 +1,138 @@
+package pathlib
+
+import (
+	"fmt"
+	"io/fs"
+	"mime"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProfileLargest is the number of largest files a TreeProfile lists.
+const ProfileLargest = 10
+
+// TreeProfile summarizes the files within a directory tree, as returned by Profile.
+type TreeProfile struct {
+	Files     int
+	Dirs      int
+	TotalSize int64
+
+	// ByExtension groups files by lowercased extension (eg. ".go"), with "" for files without one.
+	ByExtension map[string]SizeCount
+
+	// ByClass groups files by the top-level MIME type of their extension (eg. "image" or "text"), with "unknown" for unrecognized extensions.
+	ByClass map[string]SizeCount
+
+	// Largest lists the biggest files, largest first.
+	Largest []FileStat
+
+	// Depths counts files by depth, where Depths[1] counts those directly within the tree's root.
+	Depths []int
+
+	Oldest FileStat
+	Newest FileStat
+}
+
+// SizeCount is the number and total size of a group of files.
+type SizeCount struct {
+	Count int
+	Size  int64
+}
+
+// FileStat identifies a file along with its size and modification time.
+type FileStat struct {
+	Path    Path
+	Size    int64
+	ModTime time.Time
+}
+
+// Profile walks the directory Path and summarizes what is in it: file counts and sizes by extension and MIME class, the largest files, how deep files are nested, and the oldest and newest files.  Only regular files are counted as files; symbolic links are not followed.
+func (p Path) Profile() (TreeProfile, error) {
+	profile := TreeProfile{ByExtension: make(map[string]SizeCount), ByClass: make(map[string]SizeCount)}
+
+	if !p.IsDir() {
+		return profile, fmt.Errorf("Profile only works on directories: %s", p)
+	}
+
+	err := filepath.WalkDir(string(p), func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if name != string(p) {
+				profile.Dirs++
+			}
+
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+
+		if err != nil {
+			return err
+		}
+
+		file := FileStat{Path: Path(name), Size: info.Size(), ModTime: info.ModTime()}
+		profile.add(file)
+
+		rel, _ := filepath.Rel(string(p), name)
+		depth := strings.Count(rel, string(filepath.Separator)) + 1
+
+		for len(profile.Depths) <= depth {
+			profile.Depths = append(profile.Depths, 0)
+		}
+
+		profile.Depths[depth]++
+		return nil
+	})
+
+	return profile, err
+}
+
+func (t *TreeProfile) add(file FileStat) {
+	if t.Files == 0 || file.ModTime.Before(t.Oldest.ModTime) {
+		t.Oldest = file
+	}
+
+	if t.Files == 0 || file.ModTime.After(t.Newest.ModTime) {
+		t.Newest = file
+	}
+
+	t.Files++
+	t.TotalSize += file.Size
+
+	ext := strings.ToLower(filepath.Ext(string(file.Path)))
+	byExt := t.ByExtension[ext]
+	byExt.Count++
+	byExt.Size += file.Size
+	t.ByExtension[ext] = byExt
+
+	class := "unknown"
+
+	if mimeType := mime.TypeByExtension(ext); mimeType != "" {
+		class, _, _ = strings.Cut(mimeType, "/")
+	}
+
+	byClass := t.ByClass[class]
+	byClass.Count++
+	byClass.Size += file.Size
+	t.ByClass[class] = byClass
+
+	if len(t.Largest) < ProfileLargest || file.Size > t.Largest[len(t.Largest)-1].Size {
+		i := sort.Search(len(t.Largest), func(i int) bool { return t.Largest[i].Size < file.Size })
+		t.Largest = append(t.Largest, FileStat{})
+		copy(t.Largest[i+1:], t.Largest[i:])
+		t.Largest[i] = file
+
+		if len(t.Largest) > ProfileLargest {
+			t.Largest = t.Largest[:ProfileLargest]
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProfile(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	err := CreateTree(dir, TreeSpec{
+		{Path: "index.html", Content: "<html>"},
+		{Path: "assets/logo.PNG", Content: strings.Repeat("p", 100)},
+		{Path: "assets/icons/a.png", Content: strings.Repeat("p", 10)},
+		{Path: "data/config.json", Content: "{}"},
+		{Path: "README", Content: "readme"},
+		{Path: "link", Symlink: "README"},
+		{Path: "empty", Dir: true},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	old := time.Now().Add(-24 * time.Hour)
+	os.Chtimes(string(dir.JoinPath("data", "config.json")), old, old)
+	profile, err := dir.Profile()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if profile.Files != 5 || profile.Dirs != 4 || profile.TotalSize != 124 {
+		t.Errorf("Unexpected totals %d files, %d dirs, %d bytes", profile.Files, profile.Dirs, profile.TotalSize)
+	}
+
+	if profile.ByExtension[".png"] != (SizeCount{2, 110}) || profile.ByExtension[""] != (SizeCount{1, 6}) {
+		t.Errorf("Unexpected extensions %v", profile.ByExtension)
+	}
+
+	if profile.ByClass["image"] != (SizeCount{2, 110}) || profile.ByClass["text"].Count != 1 || profile.ByClass["unknown"].Count != 1 {
+		t.Errorf("Unexpected classes %v", profile.ByClass)
+	}
+
+	if len(profile.Largest) != 5 || profile.Largest[0].Path != dir.JoinPath("assets", "logo.PNG") || profile.Largest[1].Size != 10 {
+		t.Errorf("Unexpected largest files %v", profile.Largest)
+	}
+
+	if fmt.Sprint(profile.Depths) != "[0 2 2 1]" {
+		t.Errorf("Unexpected depths %v", profile.Depths)
+	}
+
+	if profile.Oldest.Path != dir.JoinPath("data", "config.json") || profile.Newest.Path == profile.Oldest.Path {
+		t.Errorf("Unexpected oldest %v and newest %v", profile.Oldest, profile.Newest)
+	}
+
+	if _, err = dir.JoinPath("README").Profile(); err == nil {
+		t.Errorf("Expected an error profiling a file")
+	}
+}
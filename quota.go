@@ -0,0 +1,295 @@
+package pathlib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded is returned when a write through a Quota would exceed its limit.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// quotaFile holds the persisted accounting of a Quota within its root.
+const quotaFile = ".quota.json"
+
+// DefaultReconcileInterval is how often a Quota recounts its usage with TreeSize.
+const DefaultReconcileInterval = time.Hour
+
+// Quota limits the number of bytes that may be stored beneath a root directory, such as a tenant's directory in a multi-tenant service.  Usage is tracked as writes are made through the Quota, persisted within the root, and periodically reconciled against TreeSize to account for changes made outside of it.
+type Quota struct {
+	// ReconcileInterval is how often usage is recounted.  It defaults to DefaultReconcileInterval.
+	ReconcileInterval time.Duration
+
+	root  Path
+	limit int64
+	mu    sync.Mutex
+	state quotaState
+}
+
+type quotaState struct {
+	Used       int64     `json:"used"`
+	Reconciled time.Time `json:"reconciled"`
+}
+
+// QuotaDir returns a Quota that limits root to limit bytes, creating root if needed and loading any persisted accounting.
+func QuotaDir(root Path, limit int64) (*Quota, error) {
+	if !root.Exists() {
+		if err := root.Mkdir(); err != nil {
+			return nil, err
+		}
+	}
+
+	q := &Quota{ReconcileInterval: DefaultReconcileInterval, root: root, limit: limit}
+	contents, err := root.JoinPath(quotaFile).ReadBytes()
+
+	if err == nil && json.Unmarshal(contents, &q.state) == nil {
+		return q, nil
+	}
+
+	return q, q.Reconcile()
+}
+
+// Used returns the number of bytes currently accounted to the Quota.
+func (q *Quota) Used() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.state.Used
+}
+
+// Remaining returns the number of bytes that may still be written.
+func (q *Quota) Remaining() int64 {
+	return q.limit - q.Used()
+}
+
+// Path returns the Path of name within the Quota's root, which cannot escape it.
+func (q *Quota) Path(name string) (Path, error) {
+	p, err := q.root.SecureJoin(name)
+
+	if err == nil && p == q.root.JoinPath(quotaFile) {
+		err = fmt.Errorf("%s is reserved", quotaFile)
+	}
+
+	return p, err
+}
+
+// Reconcile recounts the usage of the root with TreeSize and persists it.
+func (q *Quota) Reconcile() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.reconcile()
+}
+
+func (q *Quota) reconcile() error {
+	size, err := q.root.TreeSize()
+
+	if err != nil {
+		return err
+	}
+
+	if stat, err := os.Stat(string(q.root.JoinPath(quotaFile))); err == nil {
+		size -= stat.Size()
+	}
+
+	q.state = quotaState{Used: size, Reconciled: time.Now()}
+	return q.persist()
+}
+
+func (q *Quota) persist() error {
+	encoded, err := json.Marshal(q.state)
+
+	if err != nil {
+		return err
+	}
+
+	return q.root.JoinPath(quotaFile).writeBytesAtomic(encoded, 0600)
+}
+
+// reserve accounts for a change of delta bytes, failing if it would exceed the limit.
+func (q *Quota) reserve(delta int64) error {
+	if time.Since(q.state.Reconciled) > q.ReconcileInterval {
+		if err := q.reconcile(); err != nil {
+			return err
+		}
+	}
+
+	if delta > 0 && q.state.Used+delta > q.limit {
+		return fmt.Errorf("%w: %s would use %d of %d bytes", ErrQuotaExceeded, q.root, q.state.Used+delta, q.limit)
+	}
+
+	q.state.Used += delta
+	return q.persist()
+}
+
+// WriteBytes writes the data to name within the root, failing with ErrQuotaExceeded if the new contents would not fit.
+func (q *Quota) WriteBytes(name string, data []byte) error {
+	p, err := q.Path(name)
+
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err = q.reserve(int64(len(data)) - fileSize(p)); err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(string(p.Parent()), 0755); err != nil {
+		return err
+	}
+
+	if err = p.WriteBytes(data); err != nil {
+		q.reconcile()
+		return err
+	}
+
+	return nil
+}
+
+// Create creates or truncates name within the root and returns a writer that fails with ErrQuotaExceeded once the limit is reached.  Usage is accounted as data is written.
+func (q *Quota) Create(name string) (*QuotaWriter, error) {
+	p, err := q.Path(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err = q.reserve(-fileSize(p)); err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(string(p.Parent()), 0755); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Create(string(p))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuotaWriter{quota: q, file: f}, nil
+}
+
+// Unlink removes name within the root and releases its usage.
+func (q *Quota) Unlink(name string) error {
+	p, err := q.Path(name)
+
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	size := fileSize(p)
+
+	if err = p.Unlink(); err != nil {
+		return err
+	}
+
+	return q.reserve(-size)
+}
+
+// QuotaWriter writes a file through a Quota.
+type QuotaWriter struct {
+	quota *Quota
+	file  *os.File
+}
+
+// Write writes the data to the file if it fits within the Quota.
+func (w *QuotaWriter) Write(data []byte) (int, error) {
+	w.quota.mu.Lock()
+	defer w.quota.mu.Unlock()
+
+	if err := w.quota.reserve(int64(len(data))); err != nil {
+		return 0, err
+	}
+
+	n, err := w.file.Write(data)
+
+	if n < len(data) {
+		w.quota.state.Used -= int64(len(data) - n)
+	}
+
+	return n, err
+}
+
+// Close closes the file.
+func (w *QuotaWriter) Close() error {
+	return w.file.Close()
+}
+
+// fileSize returns the size of the file Path, or 0 if it does not exist.
+func fileSize(p Path) int64 {
+	stat, err := os.Lstat(string(p))
+
+	if err != nil || !stat.Mode().IsRegular() {
+		return 0
+	}
+
+	return stat.Size()
+}
+
+// TreeSize returns the total size in bytes of the regular files at or beneath the Path.  Symbolic links are not followed.
+func (p Path) TreeSize() (int64, error) {
+	stat, err := os.Lstat(string(p))
+
+	if err != nil {
+		return 0, err
+	}
+
+	if stat.Mode().IsRegular() {
+		return stat.Size(), nil
+	}
+
+	if !stat.IsDir() {
+		return 0, nil
+	}
+
+	return treeSize(string(p))
+}
+
+// treeSize sums the sizes of the regular files beneath dir.  Entry types come from the directory listing, so only regular files need to be stat'd, and those calls are spread across workers.
+func treeSize(dir string) (int64, error) {
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	files := entries[:0]
+
+	for _, entry := range entries {
+		switch {
+		case entry.IsDir():
+			subSize, err := treeSize(filepath.Join(dir, entry.Name()))
+			size += subSize
+
+			if err != nil {
+				return size, err
+			}
+		case entry.Type().IsRegular():
+			files = append(files, entry)
+		}
+	}
+
+	infos, err := lstatEntries(files)
+
+	for _, info := range infos {
+		if info != nil {
+			size += info.Size()
+		}
+	}
+
+	return size, err
+}
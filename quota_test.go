@@ -0,0 +1,138 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestQuotaDir(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	q, err := QuotaDir(root, 10)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer root.RmdirRecursive()
+
+	err = q.WriteBytes("a/one", []byte("123456"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	err = q.WriteBytes("two", []byte("123456"))
+
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected ErrQuotaExceeded, received %v", err)
+	}
+
+	// overwriting only accounts for the difference in size
+	err = q.WriteBytes("a/one", []byte("1234567890"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	err = q.Unlink("a/one")
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	w, err := q.Create("stream")
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	_, err = w.Write([]byte("12345678"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	_, err = w.Write([]byte("123"))
+
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected ErrQuotaExceeded, received %v", err)
+	}
+
+	w.Close()
+
+	if q.Used() != 8 {
+		t.Errorf("Expected 8 bytes used, found %d", q.Used())
+	}
+
+	// accounting is persisted
+	reopened, err := QuotaDir(root, 10)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if reopened.Used() != 8 || reopened.Remaining() != 2 {
+		t.Errorf("Persisted usage is %d", reopened.Used())
+	}
+
+	_, err = q.Path("../escape")
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	_, err = q.Path(quotaFile)
+
+	if err == nil {
+		t.Errorf("The accounting file should not be writable through the Quota")
+	}
+}
+
+func TestQuotaReconcile(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	q, err := QuotaDir(root, 100)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer root.RmdirRecursive()
+
+	err = root.JoinPath("outside").WriteBytes([]byte("written outside of the quota"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	err = q.Reconcile()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if q.Used() != 28 {
+		t.Errorf("Reconciled usage is %d, expected 28", q.Used())
+	}
+}
+
+func TestTreeSize(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := CreateTree(root, TreeSpec{{Path: "a", Content: "12"}, {Path: "b/c", Content: "345"}, {Path: "link", Symlink: "a"}})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer root.RmdirRecursive()
+
+	size, err := root.TreeSize()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if size != 5 {
+		t.Errorf("TreeSize returned %d, expected 5", size)
+	}
+}
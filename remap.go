@@ -0,0 +1,88 @@
+package pathlib
+
+import (
+	"path/filepath"
+)
+
+// Remapper translates Paths between namespaces using prefix mappings, eg. from paths inside a container to the matching paths on the host.  Prefixes only match at path component boundaries, and the longest matching prefix wins.  The zero value is an empty Remapper ready to use.
+type Remapper struct {
+	mappings []remapping
+}
+
+type remapping struct {
+	from Path
+	to   Path
+}
+
+// NewRemapper returns a Remapper configured with the mappings from prefix to replacement.
+func NewRemapper(mappings map[Path]Path) *Remapper {
+	r := &Remapper{}
+
+	for from, to := range mappings {
+		r.Add(from, to)
+	}
+
+	return r
+}
+
+// Add maps the prefix from to the replacement to.
+func (r *Remapper) Add(from, to Path) {
+	from = Path(filepath.Clean(string(from)))
+	to = Path(filepath.Clean(string(to)))
+
+	for i := range r.mappings {
+		if r.mappings[i].from == from {
+			r.mappings[i].to = to
+			return
+		}
+	}
+
+	r.mappings = append(r.mappings, remapping{from: from, to: to})
+}
+
+// Translate returns the Path with its longest matching prefix replaced, and whether any prefix matched.  Unmatched Paths are returned unchanged.
+func (r *Remapper) Translate(p Path) (Path, bool) {
+	return r.translate(p, false)
+}
+
+// Reverse undoes Translate, replacing the longest matching replacement with its prefix.
+func (r *Remapper) Reverse(p Path) (Path, bool) {
+	return r.translate(p, true)
+}
+
+func (r *Remapper) translate(p Path, reverse bool) (Path, bool) {
+	cleaned := Path(filepath.Clean(string(p)))
+	best := -1
+	bestLen := -1
+
+	for i, m := range r.mappings {
+		from := m.from
+
+		if reverse {
+			from = m.to
+		}
+
+		if len(from) > bestLen && cleaned.within(from) {
+			best = i
+			bestLen = len(from)
+		}
+	}
+
+	if best < 0 {
+		return p, false
+	}
+
+	from, to := r.mappings[best].from, r.mappings[best].to
+
+	if reverse {
+		from, to = to, from
+	}
+
+	rel, err := filepath.Rel(string(from), string(cleaned))
+
+	if err != nil {
+		return p, false
+	}
+
+	return to.JoinPath(Path(rel)), true
+}
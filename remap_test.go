@@ -0,0 +1,39 @@
+package pathlib
+
+import (
+	"testing"
+)
+
+func TestRemapper(t *testing.T) {
+	r := NewRemapper(map[Path]Path{
+		"/workspace":       "/home/runner/work/project",
+		"/workspace/cache": "/var/cache/ci",
+	})
+
+	tests := map[Path]Path{
+		"/workspace":                "/home/runner/work/project",
+		"/workspace/src/main.go":    "/home/runner/work/project/src/main.go",
+		"/workspace/cache/a":        "/var/cache/ci/a",
+		"/workspace/../etc/passwd":  "/workspace/../etc/passwd",
+		"/workspaces/other":         "/workspaces/other",
+		"/workspace/cache-fake/foo": "/home/runner/work/project/cache-fake/foo",
+	}
+
+	for p, target := range tests {
+		translated, _ := r.Translate(p)
+
+		if translated != target {
+			t.Errorf("Translate(%s) = %s, expected %s", p, translated, target)
+		}
+	}
+
+	if _, ok := r.Translate("/workspaces/other"); ok {
+		t.Errorf("Prefixes should only match at component boundaries")
+	}
+
+	reversed, ok := r.Reverse("/var/cache/ci/a")
+
+	if !ok || reversed != "/workspace/cache/a" {
+		t.Errorf("Reverse returned %s", reversed)
+	}
+}
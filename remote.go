@@ -0,0 +1,35 @@
+package pathlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrUnresponsive is returned by ProbeRemote when the Path does not respond before the context is done, as happens on dead network mounts.
+var ErrUnresponsive = errors.New("path is unresponsive")
+
+// probeStat is a variable so tests can simulate a hung mount.
+var probeStat = func(p Path) error {
+	_, err := os.Stat(string(p))
+	return err
+}
+
+// ProbeRemote checks whether the Path responds to a stat before ctx is done, returning how long the stat took.  This lets applications detect a hung NFS or SMB mount and degrade gracefully instead of blocking.  The stat runs in its own goroutine, since a hung mount cannot be interrupted; if it never returns, that goroutine is leaked, so callers should avoid probing a dead mount repeatedly.  The returned error wraps both ErrUnresponsive and the context's error on timeout, or is the stat's own error if the Path responded with one.
+func (p Path) ProbeRemote(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	result := make(chan error, 1)
+
+	go func() {
+		result <- probeStat(p)
+	}()
+
+	select {
+	case err := <-result:
+		return time.Since(start), err
+	case <-ctx.Done():
+		return time.Since(start), fmt.Errorf("%w: %s: %w", ErrUnresponsive, p, ctx.Err())
+	}
+}
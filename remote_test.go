@@ -0,0 +1,48 @@
+package pathlib
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestProbeRemote(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := Path("/tmp").ProbeRemote(ctx); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if _, err := Path("/foo/bar/baz/fjkdsalfjaklrejakfdsa").ProbeRemote(ctx); !os.IsNotExist(err) {
+		t.Errorf("Expected a not-exist error, received %v", err)
+	}
+}
+
+func TestProbeRemoteTimeout(t *testing.T) {
+	blocked := make(chan struct{})
+	defer close(blocked)
+
+	defer func(original func(Path) error) { probeStat = original }(probeStat)
+	probeStat = func(Path) error {
+		<-blocked
+		return nil
+	}
+
+	// the deadline starts before ProbeRemote starts timing, so its own measurement may fall a little short of it
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := Path("/mnt/dead").ProbeRemote(ctx)
+
+	if !errors.Is(err, ErrUnresponsive) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected an unresponsive error, received %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Returned after %s, before the deadline", elapsed)
+	}
+}
@@ -0,0 +1,430 @@
+package pathlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync"
+	"time"
+)
+
+// ErrReplayMismatch is returned by a Replayer when an operation differs from the next one in its recording.
+var ErrReplayMismatch = errors.New("operation does not match the recording")
+
+// RecordedOp is a single Filesystem operation and its result, as captured by a Recorder.
+type RecordedOp struct {
+	Op      string         `json:"op"`
+	Name    string         `json:"name"`
+	NewName string         `json:"new_name,omitempty"`
+	Perm    fs.FileMode    `json:"perm,omitempty"`
+	Data    []byte         `json:"data,omitempty"` // written, read, or opened contents
+	Info    *RecordedInfo  `json:"info,omitempty"`
+	Entries []RecordedInfo `json:"entries,omitempty"`
+	Err     string         `json:"err,omitempty"`
+	ErrKind string         `json:"err_kind,omitempty"` // which fs error the failure matched, if any
+}
+
+// RecordedInfo is the fs.FileInfo of a file as captured by a Recorder.
+type RecordedInfo struct {
+	Name    string      `json:"name"`
+	Size    int64       `json:"size"`
+	Mode    fs.FileMode `json:"mode"`
+	ModTime time.Time   `json:"mod_time"`
+}
+
+// FileInfo returns the RecordedInfo as an fs.FileInfo.
+func (i RecordedInfo) FileInfo() fs.FileInfo {
+	return recordedFileInfo{i}
+}
+
+func newRecordedInfo(info fs.FileInfo) *RecordedInfo {
+	return &RecordedInfo{Name: info.Name(), Size: info.Size(), Mode: info.Mode(), ModTime: info.ModTime()}
+}
+
+type recordedFileInfo struct {
+	info RecordedInfo
+}
+
+func (i recordedFileInfo) Name() string       { return i.info.Name }
+func (i recordedFileInfo) Size() int64        { return i.info.Size }
+func (i recordedFileInfo) Mode() fs.FileMode  { return i.info.Mode }
+func (i recordedFileInfo) ModTime() time.Time { return i.info.ModTime }
+func (i recordedFileInfo) IsDir() bool        { return i.info.Mode.IsDir() }
+func (i recordedFileInfo) Sys() any           { return nil }
+
+// recordedErrs are the errors a replayed failure can still be matched against with errors.Is.
+var recordedErrs = map[string]error{
+	"not_exist":  fs.ErrNotExist,
+	"exist":      fs.ErrExist,
+	"permission": fs.ErrPermission,
+	"invalid":    fs.ErrInvalid,
+	"read_only":  ErrReadOnly,
+}
+
+// Recorder is a Filesystem that passes every operation through to another Filesystem and records it along with its result, so the recording can be saved and later checked with a Replayer.  It is safe for concurrent use.
+type Recorder struct {
+	fsys Filesystem
+	mu   sync.Mutex
+	ops  []RecordedOp
+}
+
+// NewRecorder returns a Recorder wrapping fsys.
+func NewRecorder(fsys Filesystem) *Recorder {
+	return &Recorder{fsys: fsys}
+}
+
+// Ops returns the operations recorded so far.
+func (r *Recorder) Ops() []RecordedOp {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedOp(nil), r.ops...)
+}
+
+// Save writes the recording to the Path as JSON lines.
+func (r *Recorder) Save(p Path) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+
+	for _, op := range r.Ops() {
+		if err := encoder.Encode(op); err != nil {
+			return err
+		}
+	}
+
+	return p.writeBytesAtomic(buf.Bytes(), 0644)
+}
+
+func (r *Recorder) record(op RecordedOp, err error) {
+	if err != nil {
+		op.Err = err.Error()
+
+		for kind, target := range recordedErrs {
+			if errors.Is(err, target) {
+				op.ErrKind = kind
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.ops = append(r.ops, op)
+	r.mu.Unlock()
+}
+
+// Open reads the whole file or directory listing, so that it can be replayed.
+func (r *Recorder) Open(name string) (fs.File, error) {
+	op := RecordedOp{Op: "open", Name: name}
+	f, err := r.fsys.Open(name)
+
+	if err != nil {
+		r.record(op, err)
+		return nil, err
+	}
+
+	defer f.Close()
+	info, err := f.Stat()
+
+	if err == nil {
+		op.Info = newRecordedInfo(info)
+
+		if dir, ok := f.(fs.ReadDirFile); ok && info.IsDir() {
+			var entries []fs.DirEntry
+
+			if entries, err = dir.ReadDir(-1); err == nil {
+				op.Entries, err = recordEntries(entries)
+			}
+		} else {
+			op.Data, err = io.ReadAll(f)
+		}
+	}
+
+	r.record(op, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return replayFile(op), nil
+}
+
+func (r *Recorder) Stat(name string) (fs.FileInfo, error) {
+	info, err := r.fsys.Stat(name)
+	op := RecordedOp{Op: "stat", Name: name}
+
+	if err == nil {
+		op.Info = newRecordedInfo(info)
+	}
+
+	r.record(op, err)
+	return info, err
+}
+
+func (r *Recorder) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := r.fsys.ReadDir(name)
+	op := RecordedOp{Op: "readdir", Name: name}
+
+	if err == nil {
+		op.Entries, err = recordEntries(entries)
+	}
+
+	r.record(op, err)
+	return entries, err
+}
+
+func (r *Recorder) ReadFile(name string) ([]byte, error) {
+	data, err := r.fsys.ReadFile(name)
+	r.record(RecordedOp{Op: "read", Name: name, Data: data}, err)
+	return data, err
+}
+
+func (r *Recorder) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	err := r.fsys.WriteFile(name, data, perm)
+	r.record(RecordedOp{Op: "write", Name: name, Data: data, Perm: perm}, err)
+	return err
+}
+
+func (r *Recorder) MkdirAll(name string, perm fs.FileMode) error {
+	err := r.fsys.MkdirAll(name, perm)
+	r.record(RecordedOp{Op: "mkdir", Name: name, Perm: perm}, err)
+	return err
+}
+
+func (r *Recorder) Remove(name string) error {
+	err := r.fsys.Remove(name)
+	r.record(RecordedOp{Op: "remove", Name: name}, err)
+	return err
+}
+
+func (r *Recorder) RemoveAll(name string) error {
+	err := r.fsys.RemoveAll(name)
+	r.record(RecordedOp{Op: "removeall", Name: name}, err)
+	return err
+}
+
+func (r *Recorder) Rename(oldname, newname string) error {
+	err := r.fsys.Rename(oldname, newname)
+	r.record(RecordedOp{Op: "rename", Name: oldname, NewName: newname}, err)
+	return err
+}
+
+func recordEntries(entries []fs.DirEntry) ([]RecordedInfo, error) {
+	recorded := make([]RecordedInfo, 0, len(entries))
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+
+		if err != nil {
+			return nil, err
+		}
+
+		recorded = append(recorded, *newRecordedInfo(info))
+	}
+
+	return recorded, nil
+}
+
+// Replayer is a Filesystem that answers each operation from a recording instead of touching any storage, checking that the operations arrive in the recorded order with the recorded arguments (including written data).  A mismatched operation fails with an error wrapping ErrReplayMismatch; Verify reports the first mismatch, or any recorded operations that never happened.  It is safe for concurrent use, though concurrent callers must still perform operations in the recorded order.
+type Replayer struct {
+	mu       sync.Mutex
+	ops      []RecordedOp
+	next     int
+	mismatch error
+}
+
+// NewReplayer returns a Replayer for the recorded operations.
+func NewReplayer(ops []RecordedOp) *Replayer {
+	return &Replayer{ops: ops}
+}
+
+// LoadRecording returns a Replayer for the recording saved to the Path by Recorder.Save.
+func LoadRecording(p Path) (*Replayer, error) {
+	var ops []RecordedOp
+
+	for raw, err := range p.ReadJSONLines(nil) {
+		if err != nil {
+			return nil, err
+		}
+
+		var op RecordedOp
+
+		if err = json.Unmarshal(raw, &op); err != nil {
+			return nil, err
+		}
+
+		ops = append(ops, op)
+	}
+
+	return NewReplayer(ops), nil
+}
+
+// Verify returns the first mismatched operation, or an error if some recorded operations were never performed.
+func (r *Replayer) Verify() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mismatch != nil {
+		return r.mismatch
+	}
+
+	if r.next < len(r.ops) {
+		return fmt.Errorf("%w: %d recorded operations were not performed, starting with %s %s", ErrReplayMismatch, len(r.ops)-r.next, r.ops[r.next].Op, r.ops[r.next].Name)
+	}
+
+	return nil
+}
+
+// replay consumes the next recorded operation, which must match expected, returning it and its recorded error.
+func (r *Replayer) replay(expected RecordedOp) (RecordedOp, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mismatch != nil {
+		return RecordedOp{}, r.mismatch
+	}
+
+	if r.next >= len(r.ops) {
+		r.mismatch = fmt.Errorf("%w: unexpected %s %s after the recording ended", ErrReplayMismatch, expected.Op, expected.Name)
+		return RecordedOp{}, r.mismatch
+	}
+
+	op := r.ops[r.next]
+
+	if op.Op != expected.Op || op.Name != expected.Name || op.NewName != expected.NewName || (expected.Op == "write" && !bytes.Equal(op.Data, expected.Data)) || op.Perm != expected.Perm {
+		r.mismatch = fmt.Errorf("%w: operation %d was %s %s, but the recording has %s %s", ErrReplayMismatch, r.next, expected.Op, expected.Name, op.Op, op.Name)
+		return RecordedOp{}, r.mismatch
+	}
+
+	r.next++
+
+	if op.Err == "" {
+		return op, nil
+	}
+
+	err := errors.New(op.Err)
+
+	if target, ok := recordedErrs[op.ErrKind]; ok {
+		err = &fs.PathError{Op: op.Op, Path: op.Name, Err: target}
+	}
+
+	return op, err
+}
+
+func (r *Replayer) Open(name string) (fs.File, error) {
+	op, err := r.replay(RecordedOp{Op: "open", Name: name})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return replayFile(op), nil
+}
+
+func (r *Replayer) Stat(name string) (fs.FileInfo, error) {
+	op, err := r.replay(RecordedOp{Op: "stat", Name: name})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return op.Info.FileInfo(), nil
+}
+
+func (r *Replayer) ReadDir(name string) ([]fs.DirEntry, error) {
+	op, err := r.replay(RecordedOp{Op: "readdir", Name: name})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return replayEntries(op.Entries), nil
+}
+
+func (r *Replayer) ReadFile(name string) ([]byte, error) {
+	op, err := r.replay(RecordedOp{Op: "read", Name: name})
+	return op.Data, err
+}
+
+func (r *Replayer) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	_, err := r.replay(RecordedOp{Op: "write", Name: name, Data: data, Perm: perm})
+	return err
+}
+
+func (r *Replayer) MkdirAll(name string, perm fs.FileMode) error {
+	_, err := r.replay(RecordedOp{Op: "mkdir", Name: name, Perm: perm})
+	return err
+}
+
+func (r *Replayer) Remove(name string) error {
+	_, err := r.replay(RecordedOp{Op: "remove", Name: name})
+	return err
+}
+
+func (r *Replayer) RemoveAll(name string) error {
+	_, err := r.replay(RecordedOp{Op: "removeall", Name: name})
+	return err
+}
+
+func (r *Replayer) Rename(oldname, newname string) error {
+	_, err := r.replay(RecordedOp{Op: "rename", Name: oldname, NewName: newname})
+	return err
+}
+
+// replayFile returns an in-memory fs.File holding the contents captured by an open.
+func replayFile(op RecordedOp) fs.File {
+	info := op.Info.FileInfo()
+
+	if info.IsDir() {
+		return &replayDir{info: info, entries: replayEntries(op.Entries)}
+	}
+
+	return &replayOpenFile{Reader: bytes.NewReader(op.Data), info: info}
+}
+
+func replayEntries(recorded []RecordedInfo) []fs.DirEntry {
+	entries := make([]fs.DirEntry, 0, len(recorded))
+
+	for _, info := range recorded {
+		entries = append(entries, fs.FileInfoToDirEntry(info.FileInfo()))
+	}
+
+	return entries
+}
+
+type replayOpenFile struct {
+	*bytes.Reader
+	info fs.FileInfo
+}
+
+func (f *replayOpenFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *replayOpenFile) Close() error               { return nil }
+
+type replayDir struct {
+	info    fs.FileInfo
+	entries []fs.DirEntry
+}
+
+func (d *replayDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *replayDir) Close() error               { return nil }
+
+func (d *replayDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: fs.ErrInvalid}
+}
+
+func (d *replayDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
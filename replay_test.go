@@ -0,0 +1,91 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"testing"
+)
+
+// replayWorkload is a small piece of file-manipulating code to record and replay.
+func replayWorkload(fsys Filesystem, contents string) error {
+	dir := PathOn(fsys, "project")
+
+	if err := dir.Mkdir(); err != nil {
+		return err
+	}
+
+	if err := dir.JoinPath("a.txt").WriteBytes([]byte(contents)); err != nil {
+		return err
+	}
+
+	if _, err := dir.JoinPath("missing").ReadBytes(); !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Expected a not-exist error, received %v", err)
+	}
+
+	f, err := dir.JoinPath("a.txt").Open()
+
+	if err != nil {
+		return err
+	}
+
+	data, _ := io.ReadAll(f)
+	f.Close()
+
+	if string(data) != contents {
+		return fmt.Errorf("Read back %q", data)
+	}
+
+	entries, err := dir.ReadDir()
+
+	if err != nil || len(entries) != 1 {
+		return fmt.Errorf("Unexpected entries %v: %v", entries, err)
+	}
+
+	return dir.JoinPath("a.txt").Rename(dir.JoinPath("b.txt"))
+}
+
+func TestRecordReplay(t *testing.T) {
+	recorder := NewRecorder(NewMemFilesystem())
+
+	if err := replayWorkload(recorder, "hello"); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	saved := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer saved.Unlink()
+
+	if err := recorder.Save(saved); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	replayer, err := LoadRecording(saved)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if err = replayWorkload(replayer, "hello"); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if err = replayer.Verify(); err != nil {
+		t.Errorf(err.Error())
+	}
+
+	// writing different data does not match the recording
+	replayer = NewReplayer(recorder.Ops())
+
+	if err = replayWorkload(replayer, "goodbye"); !errors.Is(err, ErrReplayMismatch) || !errors.Is(replayer.Verify(), ErrReplayMismatch) {
+		t.Errorf("Expected a mismatch, received %v", err)
+	}
+
+	// stopping early leaves operations unperformed
+	replayer = NewReplayer(recorder.Ops())
+	PathOn(replayer, "project").Mkdir()
+
+	if err = replayer.Verify(); !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("Expected unperformed operations to be reported, received %v", err)
+	}
+}
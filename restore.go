@@ -0,0 +1,197 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+)
+
+// ConflictPolicy decides what Restore does when something already exists where a file is to be restored.
+type ConflictPolicy int
+
+const (
+	// ConflictOverwrite replaces what is in the destination.
+	ConflictOverwrite ConflictPolicy = iota
+
+	// ConflictSkip keeps what is in the destination.
+	ConflictSkip
+
+	// ConflictRename keeps what is in the destination and restores next to it with a numeric suffix (eg. "name-1.ext").
+	ConflictRename
+
+	// ConflictNewerWins replaces what is in the destination only if the restored file was modified more recently.
+	ConflictNewerWins
+)
+
+func (c ConflictPolicy) String() string {
+	switch c {
+	case ConflictOverwrite:
+		return "overwrite"
+	case ConflictSkip:
+		return "skip"
+	case ConflictRename:
+		return "rename"
+	case ConflictNewerWins:
+		return "newer-wins"
+	}
+
+	return fmt.Sprintf("ConflictPolicy(%d)", int(c))
+}
+
+// RestoreReport describes what Restore did.  All Paths are within the destination.
+type RestoreReport struct {
+	// Restored lists the files restored where nothing existed before, including those within newly restored directories.
+	Restored []Path
+
+	// Overwritten lists the files that replaced what was in the destination.
+	Overwritten []Path
+
+	// Renamed lists the files restored under a new name because of a conflict.
+	Renamed []RenamedFile
+
+	// Skipped lists the conflicting files (or directories) that were left as they were.
+	Skipped []Path
+
+	// Unchanged counts the files that already matched, by size and modification time, and so were left alone.
+	Unchanged int
+
+	// Bytes is the total size of the files that were written.
+	Bytes int64
+}
+
+// RenamedFile describes a file that Restore wrote under a different name.
+type RenamedFile struct {
+	Path Path // where it would have been restored
+	As   Path // where it was restored
+}
+
+// Restore copies the directory snapshot (such as one made by BackupSnapshot or Checkpoint) into dest, which may already have contents.  Existing directories are merged, files that already match by size and modification time are left alone, and any other conflict is resolved by the policy.  Permissions, modification times, and symbolic links are restored.  Replaced files are removed before being restored rather than written over, so restoring never modifies files that are hard linked elsewhere, such as within other snapshots.
+func Restore(snapshot, dest Path, policy ConflictPolicy) (RestoreReport, error) {
+	var report RestoreReport
+
+	if !snapshot.IsDir() {
+		return report, fmt.Errorf("Restore only works on directories: %s", snapshot)
+	}
+
+	if policy < ConflictOverwrite || policy > ConflictNewerWins {
+		return report, fmt.Errorf("Unknown conflict policy %s", policy)
+	}
+
+	if err := os.MkdirAll(string(dest), 0755); err != nil {
+		return report, err
+	}
+
+	o := newCopyOptions([]CopyOption{PreserveMode(), PreserveTimes()})
+	return report, o.restoreDir(snapshot, dest, policy, &report)
+}
+
+func (o copyOptions) restoreDir(src, dst Path, policy ConflictPolicy, report *RestoreReport) error {
+	entries, err := os.ReadDir(string(src))
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := Path(entry.Name())
+		child := src.JoinPath(name)
+		info, err := os.Lstat(string(child))
+
+		if err != nil {
+			return err
+		}
+
+		if err = o.restore(child, dst.JoinPath(name), info, policy, report); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restore restores src at dst, resolving any conflict with what is already there.
+func (o copyOptions) restore(src, dst Path, info os.FileInfo, policy ConflictPolicy, report *RestoreReport) error {
+	existing, err := os.Lstat(string(dst))
+
+	if os.IsNotExist(err) {
+		if !info.IsDir() {
+			report.Restored = append(report.Restored, dst)
+		}
+
+		return o.restoreNew(src, dst, info, report)
+	} else if err != nil {
+		return err
+	}
+
+	if info.IsDir() && existing.IsDir() {
+		return o.restoreDir(src, dst, policy, report)
+	}
+
+	if info.Mode() == existing.Mode() && !info.IsDir() && info.Size() == existing.Size() && info.ModTime().Equal(existing.ModTime()) {
+		report.Unchanged++
+		return nil
+	}
+
+	switch policy {
+	case ConflictSkip:
+		report.Skipped = append(report.Skipped, dst)
+		return nil
+	case ConflictNewerWins:
+		if !info.ModTime().After(existing.ModTime()) {
+			report.Skipped = append(report.Skipped, dst)
+			return nil
+		}
+	case ConflictRename:
+		renamed := uniquePath(dst)
+		report.Renamed = append(report.Renamed, RenamedFile{Path: dst, As: renamed})
+		return o.restoreNew(src, renamed, info, report)
+	}
+
+	if err = os.RemoveAll(string(dst)); err != nil {
+		return err
+	}
+
+	report.Overwritten = append(report.Overwritten, dst)
+	return o.restoreNew(src, dst, info, report)
+}
+
+// restoreNew restores src at dst, where nothing exists.
+func (o copyOptions) restoreNew(src, dst Path, info os.FileInfo, report *RestoreReport) error {
+	if !info.IsDir() {
+		if info.Mode().IsRegular() {
+			report.Bytes += info.Size()
+		}
+
+		return o.copy(src, dst, info, nil)
+	}
+
+	// the owner needs full access to fill the directory; its real permissions are applied afterwards
+	if err := os.Mkdir(string(dst), info.Mode().Perm()|0700); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(string(src))
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := Path(entry.Name())
+		child := src.JoinPath(name)
+		childInfo, err := os.Lstat(string(child))
+
+		if err != nil {
+			return err
+		}
+
+		if !childInfo.IsDir() {
+			report.Restored = append(report.Restored, dst.JoinPath(name))
+		}
+
+		if err = o.restoreNew(child, dst.JoinPath(name), childInfo, report); err != nil {
+			return err
+		}
+	}
+
+	return o.applyMetadata(src, dst, info)
+}
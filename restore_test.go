@@ -0,0 +1,84 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRestore(t *testing.T) {
+	policies := map[ConflictPolicy]struct {
+		conflict string // content of conflict.txt afterwards
+		older    string // content of older.txt afterwards
+		renamed  int
+		skipped  int
+	}{
+		ConflictOverwrite: {"snapshot", "snapshot", 0, 0},
+		ConflictSkip:      {"local", "local", 0, 2},
+		ConflictRename:    {"local", "local", 2, 0},
+		ConflictNewerWins: {"snapshot", "local", 0, 1},
+	}
+
+	for policy, expected := range policies {
+		dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+		defer dir.RmdirRecursive()
+		snapshot := dir.JoinPath("snapshot")
+		dest := dir.JoinPath("dest")
+
+		err := CreateTree(snapshot, TreeSpec{
+			{Path: "new.txt", Content: "new"},
+			{Path: "same.txt", Content: "same"},
+			{Path: "conflict.txt", Content: "snapshot"},
+			{Path: "older.txt", Content: "snapshot"},
+			{Path: "sub/deep/file.txt", Content: "deep", Perms: 0600},
+		})
+
+		if err == nil {
+			err = CreateTree(dest, TreeSpec{
+				{Path: "conflict.txt", Content: "local"},
+				{Path: "older.txt", Content: "local"},
+				{Path: "extra.txt", Content: "extra"},
+				{Path: "sub", Dir: true},
+			})
+		}
+
+		if err == nil {
+			err = snapshot.JoinPath("same.txt").Copy(dest.JoinPath("same.txt"), PreserveMode(), PreserveTimes())
+		}
+
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		old := time.Now().Add(-time.Hour)
+		os.Chtimes(string(dest.JoinPath("conflict.txt")), old, old)
+		os.Chtimes(string(snapshot.JoinPath("older.txt")), old, old)
+
+		report, err := Restore(snapshot, dest, policy)
+
+		if err != nil {
+			t.Fatalf("%s: %s", policy, err)
+		}
+
+		if len(report.Restored) != 2 || report.Unchanged != 1 || len(report.Renamed) != expected.renamed || len(report.Skipped) != expected.skipped {
+			t.Errorf("%s: unexpected report %+v", policy, report)
+		}
+
+		for name, content := range map[Path]string{"conflict.txt": expected.conflict, "older.txt": expected.older, "extra.txt": "extra", "new.txt": "new", "sub/deep/file.txt": "deep"} {
+			if data, _ := dest.JoinPath(name).ReadBytes(); string(data) != content {
+				t.Errorf("%s: expected %s to contain %q but found %q", policy, name, content, data)
+			}
+		}
+
+		if policy == ConflictRename {
+			if data, _ := dest.JoinPath("conflict-1.txt").ReadBytes(); string(data) != "snapshot" {
+				t.Errorf("Expected the renamed copy to be restored but found %q", data)
+			}
+		}
+
+		if perms, _ := dest.JoinPath("sub", "deep", "file.txt").Permissions(); perms != 0600 {
+			t.Errorf("%s: expected permissions to be restored but found %v", policy, perms)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package pathlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WalkCheckpointInterval is how often WalkResumable persists its progress.
+var WalkCheckpointInterval = 5 * time.Second
+
+type walkCheckpoint struct {
+	Root Path `json:"root"`
+	Last Path `json:"last"`
+}
+
+// WalkResumable walks the tree at the Path like filepath.Walk, periodically saving its progress to the checkpoint file.  If the walk is interrupted (by a crash, or by fn returning an error), calling WalkResumable again with the same checkpoint resumes after the last Path that was saved instead of starting from scratch, so fn may see a few Paths twice but never skips any.  The checkpoint is removed once the walk completes.
+func (p Path) WalkResumable(checkpoint Path, fn func(Path, os.FileInfo, error) error) error {
+	state := walkCheckpoint{Root: p}
+
+	if checkpoint.Exists() {
+		contents, err := checkpoint.ReadBytes()
+
+		if err != nil {
+			return err
+		}
+
+		if err = json.Unmarshal(contents, &state); err != nil {
+			return fmt.Errorf("Corrupt walk checkpoint %s: %w", checkpoint, err)
+		}
+
+		if state.Root != p {
+			return fmt.Errorf("Walk checkpoint %s belongs to %s, not %s", checkpoint, state.Root, p)
+		}
+	}
+
+	resumeAfter := state.Last
+	lastSaved := time.Now()
+
+	err := filepath.Walk(string(p), func(path string, info os.FileInfo, err error) error {
+		current := Path(path)
+
+		if len(resumeAfter) > 0 && compareWalkOrder(current, resumeAfter) <= 0 {
+			// skip whole directories that were finished before the checkpoint
+			if info != nil && info.IsDir() && !resumeAfter.within(current) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if err := fn(current, info, err); err != nil {
+			return err
+		}
+
+		state.Last = current
+
+		if time.Since(lastSaved) >= WalkCheckpointInterval {
+			lastSaved = time.Now()
+			return saveWalkCheckpoint(checkpoint, state)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if len(state.Last) > 0 {
+			saveWalkCheckpoint(checkpoint, state)
+		}
+
+		return err
+	}
+
+	if checkpoint.Exists() {
+		return checkpoint.Unlink()
+	}
+
+	return nil
+}
+
+func saveWalkCheckpoint(checkpoint Path, state walkCheckpoint) error {
+	encoded, err := json.Marshal(state)
+
+	if err != nil {
+		return err
+	}
+
+	return checkpoint.writeBytesAtomic(encoded, 0644)
+}
+
+// compareWalkOrder compares Paths in the order filepath.Walk visits them, which sorts names within each directory rather than comparing whole path strings.
+func compareWalkOrder(a, b Path) int {
+	aParts := strings.Split(filepath.Clean(string(a)), string(filepath.Separator))
+	bParts := strings.Split(filepath.Clean(string(b)), string(filepath.Separator))
+
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if c := strings.Compare(aParts[i], bParts[i]); c != 0 {
+			return c
+		}
+	}
+
+	return len(aParts) - len(bParts)
+}
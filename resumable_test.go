@@ -0,0 +1,76 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestWalkResumable(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	root := dir.JoinPath("tree")
+	checkpoint := dir.JoinPath("walk.checkpoint")
+
+	err := CreateTree(root, TreeSpec{
+		{Path: "a/1"}, {Path: "a/2"}, {Path: "a-b/3"}, {Path: "b/4"}, {Path: "b/c/5"}, {Path: "d"},
+	})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	saved := WalkCheckpointInterval
+	WalkCheckpointInterval = 0
+	defer func() { WalkCheckpointInterval = saved }()
+
+	var first []Path
+	interrupt := fmt.Errorf("interrupted")
+
+	err = root.WalkResumable(checkpoint, func(p Path, info os.FileInfo, err error) error {
+		if p == root.JoinPath("b") {
+			return interrupt
+		}
+
+		first = append(first, p)
+		return nil
+	})
+
+	if err != interrupt {
+		t.Errorf("Expected the interruption error, received %v", err)
+	}
+
+	if !checkpoint.Exists() {
+		t.Errorf("Checkpoint was not saved")
+	}
+
+	var second []Path
+
+	err = root.WalkResumable(checkpoint, func(p Path, info os.FileInfo, err error) error {
+		second = append(second, p)
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if len(first) != 6 || len(second) != 5 || second[0] != root.JoinPath("b") {
+		t.Errorf("Walk did not resume correctly: %v then %v", first, second)
+	}
+
+	if checkpoint.Exists() {
+		t.Errorf("Checkpoint should be removed after the walk completes")
+	}
+}
+
+func TestCompareWalkOrder(t *testing.T) {
+	if compareWalkOrder("a/b", "a-c") >= 0 {
+		t.Errorf("a/b is walked before a-c")
+	}
+
+	if compareWalkOrder("a", "a/b") >= 0 || compareWalkOrder("a/b", "a/b") != 0 {
+		t.Errorf("Parents are walked before their children")
+	}
+}
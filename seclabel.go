@@ -0,0 +1,8 @@
+package pathlib
+
+import (
+	"errors"
+)
+
+// ErrNoSecurityLabel is returned by SecurityLabel when the Path has no label.
+var ErrNoSecurityLabel = errors.New("no security label")
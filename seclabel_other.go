@@ -0,0 +1,22 @@
+//go:build !linux
+
+package pathlib
+
+import (
+	"fmt"
+)
+
+// SecurityLabelsSupported returns false, since SELinux labels only exist on Linux.
+func SecurityLabelsSupported() bool {
+	return false
+}
+
+// SecurityLabel is not supported on this platform.
+func (p Path) SecurityLabel() (string, error) {
+	return "", fmt.Errorf("Security labels are not supported on this platform: %s", p)
+}
+
+// SetSecurityLabel is not supported on this platform.
+func (p Path) SetSecurityLabel(label string) error {
+	return fmt.Errorf("Security labels are not supported on this platform: %s", p)
+}
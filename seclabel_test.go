@@ -0,0 +1,32 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSecurityLabel(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := p.Touch(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	label := "system_u:object_r:tmp_t:s0"
+
+	if err := p.SetSecurityLabel(label); err != nil {
+		t.Skip("Cannot set security labels here: " + err.Error())
+	}
+
+	received, err := p.SecurityLabel()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if received != label {
+		t.Errorf("Expected label %q, received %q", label, received)
+	}
+}
@@ -0,0 +1,22 @@
+package pathlib
+
+import (
+	"net/http"
+)
+
+// ServeFile replies to the request with the contents of requestPath within root.  The requestPath is joined with SecureJoin so that it cannot escape root, the content type is detected from the extension or contents, and conditional and Range requests are supported (see ServeWithCaching).  Directories and missing files result in a 404.
+func ServeFile(w http.ResponseWriter, r *http.Request, root Path, requestPath string) {
+	p, err := root.SecureJoin(requestPath)
+
+	if err != nil {
+		http.Error(w, "403 Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if !p.IsFile() {
+		http.NotFound(w, r)
+		return
+	}
+
+	p.ServeWithCaching(w, r)
+}
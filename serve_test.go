@@ -0,0 +1,107 @@
+package pathlib
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func serveFileTest(root Path, requestPath string, rangeHeader string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/"+requestPath, nil)
+
+	if len(rangeHeader) > 0 {
+		r.Header.Set("Range", rangeHeader)
+	}
+
+	ServeFile(w, r, root, requestPath)
+	return w
+}
+
+func TestServeFile(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := root.Mkdir()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer root.RmdirRecursive()
+
+	err = root.JoinPath("index.html").WriteBytes([]byte("<html>hello</html>"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	w := serveFileTest(root, "index.html", "")
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200, received %d", w.Code)
+	}
+
+	if w.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+		t.Errorf("Incorrect content type: %s", w.Header().Get("Content-Type"))
+	}
+
+	w = serveFileTest(root, "index.html", "bytes=6-10")
+
+	if w.Code != http.StatusPartialContent || w.Body.String() != "hello" {
+		t.Errorf("Range request failed: %d %s", w.Code, w.Body.String())
+	}
+
+	w = serveFileTest(root, "missing.html", "")
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, received %d", w.Code)
+	}
+
+	w = serveFileTest(root, "../../etc/passwd", "")
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for path outside root, received %d", w.Code)
+	}
+}
+
+func TestSecureJoin(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := root.Mkdir()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer root.RmdirRecursive()
+
+	tests := map[string]Path{
+		"foo/bar":          root.JoinPath("foo/bar"),
+		"../../etc/passwd": root.JoinPath("etc/passwd"),
+		"/foo/../../bar":   root.JoinPath("bar"),
+	}
+
+	for untrusted, target := range tests {
+		joined, err := root.SecureJoin(untrusted)
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+
+		if joined != target {
+			t.Errorf("SecureJoin failed: %s != %s", joined, target)
+		}
+	}
+
+	err = os.Symlink("/etc", string(root.JoinPath("escape")))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	_, err = root.SecureJoin("escape/passwd")
+
+	if err == nil {
+		t.Errorf("SecureJoin should fail for symbolic links leading outside the root")
+	}
+}
@@ -0,0 +1,74 @@
+package pathlib
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// MetadataExt is the extension of the sidecar files used by WriteMetadata and ReadMetadata, so the metadata of "photo.jpg" is kept in "photo.jpg.meta.json".
+const MetadataExt = ".meta.json"
+
+// Sidecar returns the Path of the sidecar file with the extension that accompanies the Path (eg. "photo.jpg" and ".xmp" give "photo.jpg.xmp").
+func (p Path) Sidecar(ext string) Path {
+	return Path(string(p) + ext)
+}
+
+// SidecarOwner returns the Path that the sidecar Path with the extension accompanies, the reverse of Sidecar, and whether the Path has the extension at all.
+func (p Path) SidecarOwner(ext string) (Path, bool) {
+	if len(ext) == 0 || len(p) <= len(ext) || !strings.HasSuffix(string(p), ext) {
+		return p, false
+	}
+
+	return Path(strings.TrimSuffix(string(p), ext)), true
+}
+
+// WriteMetadata atomically writes v, encoded as indented JSON, to the metadata sidecar of the Path (see MetadataExt).
+func (p Path) WriteMetadata(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return p.Sidecar(MetadataExt).writeBytesAtomic(append(data, '\n'), 0644)
+}
+
+// ReadMetadata decodes the JSON metadata sidecar of the Path into v.
+func (p Path) ReadMetadata(v any) error {
+	data, err := p.Sidecar(MetadataExt).ReadBytes()
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// WriteBytesWithMetadata atomically writes the data to the Path along with v to its metadata sidecar, as a pair.  The sidecar is written first, so anyone who sees the new data also sees its metadata.  If writing the data fails, the previous sidecar is put back (or removed if there was none), so the pair is left as it was.
+func (p Path) WriteBytesWithMetadata(data []byte, v any) error {
+	sidecar := p.Sidecar(MetadataExt)
+	previous, readErr := sidecar.ReadBytes()
+
+	if readErr != nil && !os.IsNotExist(readErr) {
+		return readErr
+	}
+
+	if err := p.WriteMetadata(v); err != nil {
+		return err
+	}
+
+	err := p.writeBytesAtomic(data, 0644)
+
+	if err == nil {
+		return nil
+	}
+
+	if os.IsNotExist(readErr) {
+		os.Remove(string(sidecar))
+	} else {
+		sidecar.writeBytesAtomic(previous, 0644)
+	}
+
+	return err
+}
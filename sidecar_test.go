@@ -0,0 +1,89 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSidecar(t *testing.T) {
+	tests := map[Path]Path{
+		"photo.jpg":      "photo.jpg.xmp",
+		"/a/b/notes":     "/a/b/notes.xmp",
+		"archive.tar.gz": "archive.tar.gz.xmp",
+	}
+
+	for p, expected := range tests {
+		if sidecar := p.Sidecar(".xmp"); sidecar != expected {
+			t.Errorf("Expected sidecar of %s to be %s but was %s", p, expected, sidecar)
+		}
+
+		if owner, ok := expected.SidecarOwner(".xmp"); !ok || owner != p {
+			t.Errorf("Expected owner of %s to be %s but was %s (%v)", expected, p, owner, ok)
+		}
+	}
+
+	if _, ok := Path("photo.jpg").SidecarOwner(".xmp"); ok {
+		t.Errorf("Expected photo.jpg not to be a sidecar")
+	}
+
+	if _, ok := Path(".xmp").SidecarOwner(".xmp"); ok {
+		t.Errorf("Expected a bare extension not to be a sidecar")
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	if err := dir.Mkdir(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	type meta struct {
+		Camera string   `json:"camera"`
+		Tags   []string `json:"tags"`
+	}
+
+	photo := dir.JoinPath("photo.jpg")
+
+	if err := photo.WriteBytesWithMetadata([]byte("jpeg"), meta{Camera: "X100", Tags: []string{"cat"}}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if !dir.JoinPath("photo.jpg.meta.json").IsFile() {
+		t.Errorf("Expected the metadata sidecar to be written")
+	}
+
+	var read meta
+
+	if err := photo.ReadMetadata(&read); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if read.Camera != "X100" || len(read.Tags) != 1 || read.Tags[0] != "cat" {
+		t.Errorf("Unexpected metadata %+v", read)
+	}
+
+	if data, _ := photo.ReadBytes(); string(data) != "jpeg" {
+		t.Errorf("Unexpected data %q", data)
+	}
+
+	// the data cannot replace a directory, so the new sidecar is rolled back
+	blocked := dir.JoinPath("blocked.jpg")
+
+	if err := CreateTree(blocked, TreeSpec{{Path: "file", Content: "x"}}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if err := blocked.WriteBytesWithMetadata([]byte("jpeg"), meta{}); err == nil {
+		t.Errorf("Expected an error writing over a directory")
+	}
+
+	if blocked.Sidecar(MetadataExt).Exists() {
+		t.Errorf("Expected no sidecar to be left behind")
+	}
+
+	if err := photo.ReadMetadata(&read); err != nil || read.Camera != "X100" {
+		t.Errorf("Expected the original metadata to be intact")
+	}
+}
@@ -0,0 +1,236 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultSpoolAttempts is the MaxAttempts of a new SpoolQueue.
+const DefaultSpoolAttempts = 5
+
+// ErrSpoolEmpty is returned by Claim when there is nothing to claim.
+var ErrSpoolEmpty = errors.New("spool is empty")
+
+// spoolCounter distinguishes the items enqueued by this process within the same nanosecond.
+var spoolCounter atomic.Uint64
+
+// SpoolQueue is a queue of files in a directory, in the style of maildir, which any number of processes sharing the filesystem can enqueue to and consume from.  Items are written in "tmp" and renamed into "new" once complete, so consumers never see partial items; a consumer claims an item by renaming it into "cur", which only one consumer can do; and items that fail too often are moved to "dead" for inspection.  Create one with Spool.
+type SpoolQueue struct {
+	dir Path
+
+	// MaxAttempts is how many times an item may be claimed before Retry moves it to "dead" instead of back into the queue.  Zero allows any number.
+	MaxAttempts int
+}
+
+// SpoolItem is an item claimed from a SpoolQueue.  The consumer must finish with Done, Retry, or DeadLetter.
+type SpoolItem struct {
+	// Path is where the item is while it is claimed.
+	Path Path
+
+	// Attempts counts the claims of the item, including this one.
+	Attempts int
+
+	spool *SpoolQueue
+	name  string
+}
+
+// Spool returns the SpoolQueue in the directory Path, creating its "tmp", "new", "cur", and "dead" subdirectories if needed.
+func Spool(dir Path) (*SpoolQueue, error) {
+	s := &SpoolQueue{dir: dir, MaxAttempts: DefaultSpoolAttempts}
+
+	for _, sub := range []Path{"tmp", "new", "cur", "dead"} {
+		if err := os.MkdirAll(string(dir.JoinPath(sub)), 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Dir returns the directory the SpoolQueue is in.
+func (s *SpoolQueue) Dir() Path {
+	return s.dir
+}
+
+// Enqueue adds an item with the data to the queue, returning its name.  The data is synced before the item becomes visible, so consumers never see it partially written, even after a crash.
+func (s *SpoolQueue) Enqueue(data []byte) (string, error) {
+	host, _ := os.Hostname()
+	host = strings.NewReplacer("/", "_", `\`, "_", ":", "_", "+", "_").Replace(host)
+	name := fmt.Sprintf("%d.%d_%d.%s", time.Now().UnixNano(), os.Getpid(), spoolCounter.Add(1), host)
+	tmp := s.dir.JoinPath("tmp", Path(name))
+
+	f, err := os.OpenFile(string(tmp), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+
+	if err != nil {
+		return "", err
+	}
+
+	_, err = f.Write(data)
+
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(string(tmp), string(s.dir.JoinPath("new", Path(name))))
+	}
+
+	if err != nil {
+		os.Remove(string(tmp))
+		return "", err
+	}
+
+	return name, nil
+}
+
+// Claim claims the oldest item in the queue, moving it into "cur" so no other consumer can claim it.  If the queue is empty, an error wrapping ErrSpoolEmpty is returned.
+func (s *SpoolQueue) Claim() (*SpoolItem, error) {
+	entries, err := os.ReadDir(string(s.dir.JoinPath("new")))
+
+	if err != nil {
+		return nil, err
+	}
+
+	// names start with the time they were enqueued, so they sort oldest first
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, entry := range entries {
+		name, attempts := parseSpoolName(entry.Name())
+		claimed := s.dir.JoinPath("cur", Path(spoolName(name, attempts+1)))
+
+		// another consumer may have claimed it first
+		if err = os.Rename(string(s.dir.JoinPath("new", Path(entry.Name()))), string(claimed)); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		// the claim time, so Recover can tell abandoned claims
+		now := time.Now()
+		os.Chtimes(string(claimed), now, now)
+
+		return &SpoolItem{Path: claimed, Attempts: attempts + 1, spool: s, name: name}, nil
+	}
+
+	return nil, fmt.Errorf("Cannot claim from %s: %w", s.dir, ErrSpoolEmpty)
+}
+
+// Len returns the number of items waiting to be claimed.
+func (s *SpoolQueue) Len() (int, error) {
+	entries, err := os.ReadDir(string(s.dir.JoinPath("new")))
+	return len(entries), err
+}
+
+// Dead returns the items moved to "dead".
+func (s *SpoolQueue) Dead() ([]Path, error) {
+	entries, err := os.ReadDir(string(s.dir.JoinPath("dead")))
+
+	if err != nil {
+		return nil, err
+	}
+
+	dead := make([]Path, len(entries))
+
+	for i, entry := range entries {
+		dead[i] = s.dir.JoinPath("dead", Path(entry.Name()))
+	}
+
+	return dead, nil
+}
+
+// Recover returns items claimed longer than staleness ago to the queue (or to "dead", as Retry does), for consumers that crashed without finishing them, and removes temporary files as old as that from interrupted Enqueues.  It returns how many items it recovered.  Staleness must be longer than any consumer takes to process an item.
+func (s *SpoolQueue) Recover(staleness time.Duration) (int, error) {
+	recovered := 0
+
+	for _, sub := range []Path{"cur", "tmp"} {
+		entries, err := os.ReadDir(string(s.dir.JoinPath(sub)))
+
+		if err != nil {
+			return recovered, err
+		}
+
+		for _, entry := range entries {
+			info, err := entry.Info()
+
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return recovered, err
+			}
+
+			if time.Since(info.ModTime()) <= staleness {
+				continue
+			}
+
+			p := s.dir.JoinPath(sub, Path(entry.Name()))
+
+			if sub == "tmp" {
+				err = os.Remove(string(p))
+			} else {
+				name, attempts := parseSpoolName(entry.Name())
+				err = (&SpoolItem{Path: p, Attempts: attempts, spool: s, name: name}).Retry()
+			}
+
+			if os.IsNotExist(err) {
+				continue // finished meanwhile
+			} else if err != nil {
+				return recovered, err
+			}
+
+			if sub == "cur" {
+				recovered++
+			}
+		}
+	}
+
+	return recovered, nil
+}
+
+// Done removes the item, which was processed successfully.
+func (i *SpoolItem) Done() error {
+	return os.Remove(string(i.Path))
+}
+
+// Retry returns the item to the queue to be claimed again, or moves it to "dead" if it has already been claimed MaxAttempts times.
+func (i *SpoolItem) Retry() error {
+	if i.spool.MaxAttempts > 0 && i.Attempts >= i.spool.MaxAttempts {
+		return i.DeadLetter()
+	}
+
+	return os.Rename(string(i.Path), string(i.spool.dir.JoinPath("new", Path(spoolName(i.name, i.Attempts)))))
+}
+
+// DeadLetter moves the item to "dead" without retrying it, such as when it can never succeed.
+func (i *SpoolItem) DeadLetter() error {
+	return os.Rename(string(i.Path), string(i.spool.dir.JoinPath("dead", Path(spoolName(i.name, i.Attempts)))))
+}
+
+// spoolName appends the number of attempts to the name of an item, if it has been claimed before.
+func spoolName(name string, attempts int) string {
+	if attempts == 0 {
+		return name
+	}
+
+	return name + "+" + strconv.Itoa(attempts)
+}
+
+// parseSpoolName splits the name of an item from the number of attempts appended by spoolName.
+func parseSpoolName(name string) (string, int) {
+	if i := strings.LastIndexByte(name, '+'); i >= 0 {
+		if attempts, err := strconv.Atoi(name[i+1:]); err == nil {
+			return name[:i], attempts
+		}
+	}
+
+	return name, 0
+}
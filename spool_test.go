@@ -0,0 +1,156 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSpool(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	s, err := Spool(dir)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for _, data := range []string{"first", "second"} {
+		if _, err = s.Enqueue([]byte(data)); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	item, err := s.Claim()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if data, _ := item.Path.ReadBytes(); string(data) != "first" || item.Attempts != 1 {
+		t.Errorf("Expected the first item on its first attempt, received %q on attempt %d", data, item.Attempts)
+	}
+
+	if err = item.Done(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// the second item fails every attempt, and ends up dead
+	s.MaxAttempts = 2
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		item, err = s.Claim()
+
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		if data, _ := item.Path.ReadBytes(); string(data) != "second" || item.Attempts != attempt {
+			t.Errorf("Expected the second item on attempt %d, received %q on attempt %d", attempt, data, item.Attempts)
+		}
+
+		if err = item.Retry(); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	if _, err = s.Claim(); !errors.Is(err, ErrSpoolEmpty) {
+		t.Errorf("Expected ErrSpoolEmpty, received %v", err)
+	}
+
+	if dead, _ := s.Dead(); len(dead) != 1 {
+		t.Errorf("Expected one dead item, received %v", dead)
+	}
+}
+
+func TestSpoolConcurrentClaims(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	s, err := Spool(dir)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for i := 0; i < 50; i++ {
+		if _, err = s.Enqueue([]byte(fmt.Sprint(i))); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := map[string]int{}
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				item, err := s.Claim()
+
+				if err != nil {
+					return
+				}
+
+				data, _ := item.Path.ReadBytes()
+				mu.Lock()
+				seen[string(data)]++
+				mu.Unlock()
+				item.Done()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(seen) != 50 {
+		t.Errorf("Expected all 50 items to be claimed, %d were", len(seen))
+	}
+
+	for data, count := range seen {
+		if count != 1 {
+			t.Errorf("Item %s was claimed %d times", data, count)
+		}
+	}
+}
+
+func TestSpoolRecover(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	s, err := Spool(dir)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	s.Enqueue([]byte("abandoned"))
+	item, err := s.Claim()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if recovered, _ := s.Recover(time.Hour); recovered != 0 {
+		t.Errorf("A fresh claim should not be recovered")
+	}
+
+	past := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(string(item.Path), past, past)
+
+	if recovered, err := s.Recover(time.Hour); err != nil || recovered != 1 {
+		t.Fatalf("Expected the abandoned claim to be recovered, received %d, %v", recovered, err)
+	}
+
+	if item, err = s.Claim(); err != nil || item.Attempts != 2 {
+		t.Errorf("Expected the recovered item on its second attempt, received %+v, %v", item, err)
+	}
+}
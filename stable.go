@@ -0,0 +1,59 @@
+package pathlib
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// WaitUntilStable waits until the file Path has been fully written, as when it is being uploaded or copied in: until its size and modification time have not changed for the quiet period, and no other process holds it locked or open.  Locks and open files are only checked where IsLocked and OpenedBy are supported, and OpenedBy may not see processes of other users.  It returns ctx's error if ctx is done first, and an error if the file cannot be stat'd, such as when it is removed while waiting.
+func (p Path) WaitUntilStable(ctx context.Context, quiet time.Duration) error {
+	ticker := time.NewTicker(max(quiet/4, 10*time.Millisecond))
+	defer ticker.Stop()
+
+	var previous os.FileInfo
+	var since time.Time
+
+	for {
+		current, err := os.Stat(string(p))
+
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		if previous == nil || current.Size() != previous.Size() || !current.ModTime().Equal(previous.ModTime()) {
+			previous, since = current, now
+		} else if now.Sub(since) >= quiet {
+			if !p.inUse() {
+				return nil
+			}
+
+			since = now // wait a full quiet period after it is released
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// inUse returns true if another process is detected holding the file Path locked or open.
+func (p Path) inUse() bool {
+	if locked, err := p.IsLocked(); err == nil && locked {
+		return true
+	}
+
+	processes, _ := p.OpenedBy()
+
+	for _, process := range processes {
+		if process.PID != os.Getpid() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,97 @@
+package pathlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestWaitUntilStable(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	f, err := os.Create(string(p))
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	done := make(chan time.Time)
+
+	go func() {
+		for i := 0; i < 10; i++ {
+			f.Write([]byte("chunk"))
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		f.Close()
+		done <- time.Now()
+	}()
+
+	if err = p.WaitUntilStable(context.Background(), 50*time.Millisecond); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	finished := <-done
+
+	if time.Now().Before(finished) {
+		t.Errorf("WaitUntilStable returned while the file was still being written")
+	}
+
+	if stat, _ := os.Stat(string(p)); stat.Size() != 50 {
+		t.Errorf("Expected the whole file, received %d bytes", stat.Size())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err = p.WaitUntilStable(ctx, time.Hour); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected WaitUntilStable to stop when the context is done, received %v", err)
+	}
+}
+
+func TestWaitUntilStableOpenElsewhere(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Open files are only detected without lsof on Linux")
+	}
+
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	if err := p.WriteBytes([]byte("held")); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	f, err := os.Open(string(p))
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// another process holds the file open until it exits
+	holder := exec.Command("sleep", "0.2")
+	holder.Stdin = f
+
+	if err = holder.Start(); err != nil {
+		t.Skip("Cannot start sleep: " + err.Error())
+	}
+
+	f.Close()
+	start := time.Now()
+
+	if err = p.WaitUntilStable(context.Background(), 20*time.Millisecond); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	holder.Wait()
+
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("WaitUntilStable returned after %s, while another process had the file open", elapsed)
+	}
+}
@@ -0,0 +1,68 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// StaleHandleError is returned by RetryStale when an operation keeps failing with a stale file handle (ESTALE), which on NFS usually means the export was remounted or the file was replaced on the server.  Callers can match it with errors.As to trigger remount logic.
+type StaleHandleError struct {
+	Path Path
+	Err  error
+}
+
+func (e *StaleHandleError) Error() string {
+	return fmt.Sprintf("Stale file handle for %s: %s", e.Path, e.Err)
+}
+
+func (e *StaleHandleError) Unwrap() error {
+	return e.Err
+}
+
+// IsStaleHandle reports whether err came from a stale file handle (ESTALE), as NFS returns when a file or directory it had cached has gone away on the server.
+func IsStaleHandle(err error) bool {
+	var stale *StaleHandleError
+	return errors.As(err, &stale) || isStaleErrno(err)
+}
+
+// RetryStale runs fn on the Path, and if it fails with a stale file handle, re-resolves the Path and tries again, up to attempts times in total.  Re-resolving looks up each directory from the root down, which makes NFS clients revalidate their cached handles.  Errors other than ESTALE are returned immediately; if every attempt is stale, a *StaleHandleError is returned.
+func (p Path) RetryStale(attempts int, fn func(Path) error) error {
+	var err error
+
+	for attempt := 0; attempt < max(attempts, 1); attempt++ {
+		if attempt > 0 {
+			p.revalidate()
+		}
+
+		if err = fn(p); !isStaleErrno(err) {
+			return err
+		}
+	}
+
+	return &StaleHandleError{Path: p, Err: err}
+}
+
+// revalidate stats each ancestor of the Path from the root down, so each component is looked up afresh.
+func (p Path) revalidate() {
+	abs, err := filepath.Abs(string(p))
+
+	if err != nil {
+		return
+	}
+
+	var ancestors []string
+
+	for dir := abs; ; dir = filepath.Dir(dir) {
+		ancestors = append(ancestors, dir)
+
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		os.Stat(ancestors[i])
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !plan9
+
+package pathlib
+
+import (
+	"errors"
+	"syscall"
+)
+
+// errStale is the error of an operation on a stale file handle, such as a file removed on an NFS server.
+var errStale error = syscall.ESTALE
+
+func isStaleErrno(err error) bool {
+	return errors.Is(err, errStale)
+}
@@ -0,0 +1,13 @@
+package pathlib
+
+import (
+	"errors"
+	"syscall"
+)
+
+// errStale stands in for ESTALE, which Plan 9 does not define.
+var errStale error = syscall.NewError("stale NFS file handle")
+
+func isStaleErrno(err error) bool {
+	return errors.Is(err, errStale)
+}
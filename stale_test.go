@@ -0,0 +1,50 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestRetryStale(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	calls := 0
+
+	err := p.RetryStale(3, func(Path) error {
+		calls++
+
+		if calls < 3 {
+			return &os.PathError{Op: "open", Path: string(p), Err: errStale}
+		}
+
+		return nil
+	})
+
+	if err != nil || calls != 3 {
+		t.Errorf("Expected success on the third call, received %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = p.RetryStale(2, func(Path) error {
+		calls++
+		return &os.PathError{Op: "open", Path: string(p), Err: errStale}
+	})
+
+	var stale *StaleHandleError
+
+	if !errors.As(err, &stale) || stale.Path != p || calls != 2 || !IsStaleHandle(err) || !errors.Is(err, errStale) {
+		t.Errorf("Expected a StaleHandleError after 2 calls, received %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = p.RetryStale(3, func(p Path) error {
+		calls++
+		_, err := p.ReadBytes()
+		return err
+	})
+
+	if !os.IsNotExist(err) || IsStaleHandle(err) || calls != 1 {
+		t.Errorf("Expected a single not-exist error, received %v after %d calls", err, calls)
+	}
+}
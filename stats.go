@@ -0,0 +1,118 @@
+package pathlib
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds of the BackendStats latency histogram.  The final bucket counts everything slower than the last bound.
+var LatencyBuckets = [...]time.Duration{100 * time.Microsecond, time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond, time.Second}
+
+// BackendStats counts the operations performed through FSPaths on a single Filesystem backend.
+type BackendStats struct {
+	Ops          int64
+	Errors       int64
+	BytesRead    int64
+	BytesWritten int64
+	TotalLatency time.Duration
+
+	// Latency counts operations by duration, using the bounds in LatencyBuckets.
+	Latency [len(LatencyBuckets) + 1]int64
+}
+
+// MeanLatency returns the average duration of an operation.
+func (s BackendStats) MeanLatency() time.Duration {
+	if s.Ops == 0 {
+		return 0
+	}
+
+	return s.TotalLatency / time.Duration(s.Ops)
+}
+
+type backendCounters struct {
+	ops, errors, bytesRead, bytesWritten, totalLatency atomic.Int64
+	latency                                            [len(LatencyBuckets) + 1]atomic.Int64
+}
+
+var backendStats sync.Map // backend name to *backendCounters
+
+// Stats returns the counters for each backend used through FSPaths, keyed by backend name: "os" for OS, "mem" for MemFilesystems, "fs" for read-only io/fs.FS adapters, "faulty" and "policy" for FaultyFilesystems and PolicyFilesystems, and the Go type for other Filesystems.  Plain Paths bypass the Filesystem layer and are not counted.
+func Stats() map[string]BackendStats {
+	stats := make(map[string]BackendStats)
+
+	backendStats.Range(func(name, value any) bool {
+		counters := value.(*backendCounters)
+		s := BackendStats{
+			Ops:          counters.ops.Load(),
+			Errors:       counters.errors.Load(),
+			BytesRead:    counters.bytesRead.Load(),
+			BytesWritten: counters.bytesWritten.Load(),
+			TotalLatency: time.Duration(counters.totalLatency.Load()),
+		}
+
+		for i := range s.Latency {
+			s.Latency[i] = counters.latency[i].Load()
+		}
+
+		stats[name.(string)] = s
+		return true
+	})
+
+	return stats
+}
+
+// ResetStats clears the counters of every backend.
+func ResetStats() {
+	backendStats.Clear()
+}
+
+// backendName returns the name a Filesystem's counters are kept under.
+func backendName(fsys Filesystem) string {
+	switch fsys.(type) {
+	case osFilesystem, readOnlyOS, policyOS:
+		return "os"
+	case *MemFilesystem:
+		return "mem"
+	case readOnlyFS:
+		return "fs"
+	case *FaultyFilesystem:
+		return "faulty"
+	case *PolicyFilesystem:
+		return "policy"
+	}
+
+	return fmt.Sprintf("%T", fsys)
+}
+
+// record counts an operation on the Filesystem that started at start.
+func record(fsys Filesystem, start time.Time, read, written int, err error) {
+	value, ok := backendStats.Load(backendName(fsys))
+
+	if !ok {
+		value, _ = backendStats.LoadOrStore(backendName(fsys), new(backendCounters))
+	}
+
+	counters := value.(*backendCounters)
+	elapsed := time.Since(start)
+	counters.ops.Add(1)
+	counters.bytesRead.Add(int64(read))
+	counters.bytesWritten.Add(int64(written))
+	counters.totalLatency.Add(int64(elapsed))
+
+	if err != nil {
+		counters.errors.Add(1)
+	}
+
+	bucket := len(LatencyBuckets)
+
+	for i, bound := range LatencyBuckets {
+		if elapsed <= bound {
+			bucket = i
+			break
+		}
+	}
+
+	counters.latency[bucket].Add(1)
+}
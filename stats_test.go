@@ -0,0 +1,46 @@
+package pathlib
+
+import (
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	ResetStats()
+	mem := NewMemFilesystem()
+	p := PathOn(mem, "file.txt")
+
+	if err := p.WriteBytes([]byte("12345")); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	p.ReadBytes()
+	PathOn(mem, "missing").ReadBytes()
+	PathOn(OS, "/etc/passwd").Exists()
+
+	stats := Stats()
+	memStats := stats["mem"]
+
+	if memStats.Ops != 3 || memStats.Errors != 1 || memStats.BytesRead != 5 || memStats.BytesWritten != 5 {
+		t.Errorf("Unexpected mem stats %+v", memStats)
+	}
+
+	var bucketed int64
+
+	for _, count := range memStats.Latency {
+		bucketed += count
+	}
+
+	if bucketed != memStats.Ops || memStats.MeanLatency() <= 0 {
+		t.Errorf("Expected every operation in the latency histogram: %+v", memStats)
+	}
+
+	if stats["os"].Ops != 1 {
+		t.Errorf("Unexpected os stats %+v", stats["os"])
+	}
+
+	ResetStats()
+
+	if len(Stats()) != 0 {
+		t.Errorf("Expected ResetStats to clear the counters")
+	}
+}
@@ -0,0 +1,78 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+)
+
+// IsBrokenSymlink returns true if the Path is a symbolic link whose target does not exist.  Exists, IsFile, and IsDir all return false for such links.
+func (p Path) IsBrokenSymlink() bool {
+	info, err := os.Lstat(string(p))
+
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return false
+	}
+
+	_, err = os.Stat(string(p))
+	return os.IsNotExist(err)
+}
+
+// FindBrokenSymlinks returns the broken symbolic links within the directory Path, and within its subdirectories if recursive is true.  Links are not followed while searching.
+func (p Path) FindBrokenSymlinks(recursive bool) ([]Path, error) {
+	if !p.IsDir() {
+		return nil, fmt.Errorf("FindBrokenSymlinks only works on directories: %s", p)
+	}
+
+	var broken []Path
+
+	if !recursive {
+		entries, err := os.ReadDir(string(p))
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if child := p.JoinPath(Path(entry.Name())); entry.Type()&os.ModeSymlink != 0 && child.IsBrokenSymlink() {
+				broken = append(broken, child)
+			}
+		}
+
+		return broken, nil
+	}
+
+	err := p.Walk(func(child Path, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 && child.IsBrokenSymlink() {
+			broken = append(broken, child)
+		}
+
+		return nil
+	})
+
+	return broken, err
+}
+
+// RemoveBrokenSymlinks deletes the broken symbolic links that FindBrokenSymlinks finds, returning the Paths that were removed.
+func (p Path) RemoveBrokenSymlinks(recursive bool) ([]Path, error) {
+	broken, err := p.FindBrokenSymlinks(recursive)
+
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make([]Path, 0, len(broken))
+
+	for _, link := range broken {
+		if err = os.Remove(string(link)); err != nil {
+			return removed, err
+		}
+
+		removed = append(removed, link)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,49 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBrokenSymlinks(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	err := CreateTree(root, TreeSpec{
+		{Path: "file"}, {Path: "good", Symlink: "file"}, {Path: "bad", Symlink: "missing"},
+		{Path: "sub/deep-bad", Symlink: "../nowhere"}, {Path: "sub/deep-good", Symlink: "../file"},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer root.RmdirRecursive()
+
+	if !root.JoinPath("bad").IsBrokenSymlink() || root.JoinPath("good").IsBrokenSymlink() || root.JoinPath("file").IsBrokenSymlink() {
+		t.Errorf("IsBrokenSymlink gave the wrong answer")
+	}
+
+	if root.JoinPath("bad").Exists() {
+		t.Errorf("A broken link should not exist")
+	}
+
+	broken, err := root.FindBrokenSymlinks(false)
+
+	if err != nil || fmt.Sprint(broken) != fmt.Sprint([]Path{root.JoinPath("bad")}) {
+		t.Errorf("Unexpected non-recursive result %v (%v)", broken, err)
+	}
+
+	removed, err := root.RemoveBrokenSymlinks(true)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if fmt.Sprint(removed) != fmt.Sprint([]Path{root.JoinPath("bad"), root.JoinPath("sub", "deep-bad")}) {
+		t.Errorf("Unexpected removals %v", removed)
+	}
+
+	if broken, _ = root.FindBrokenSymlinks(true); len(broken) != 0 || !root.JoinPath("sub", "deep-good").IsFile() {
+		t.Errorf("Broken links remain (%v) or good links were removed", broken)
+	}
+}
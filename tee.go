@@ -0,0 +1,207 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// TeePolicy decides what happens when writing to one destination of a Tee fails.
+type TeePolicy int
+
+const (
+	// TeeRequired fails the whole Tee when the destination fails, so nothing is written anywhere.  It is the default.
+	TeeRequired TeePolicy = iota
+
+	// TeeBestEffort drops the destination when it fails, and carries on writing to the others.  The failure is reported by Failed.
+	TeeBestEffort
+)
+
+// Tee streams the same data to several Paths at once, for replicating artifacts as they are written.  Each destination is written to a temporary file next to it and renamed into place on Close, so a destination either receives the complete data or is left untouched.
+type Tee struct {
+	dests    []teeDest
+	opened   bool
+	err      error
+	failures map[Path]error
+}
+
+type teeDest struct {
+	path   Path
+	policy TeePolicy
+	tmp    *os.File
+}
+
+// TeeWriter returns a Tee writing to all the destinations, each with the TeeRequired policy.  Nothing is created until the first Write or Close.
+func TeeWriter(dests ...Path) *Tee {
+	t := &Tee{failures: make(map[Path]error)}
+
+	for _, dest := range dests {
+		t.dests = append(t.dests, teeDest{path: dest})
+	}
+
+	return t
+}
+
+// SetPolicy changes the policy of a destination, and must be called before the first Write.
+func (t *Tee) SetPolicy(dest Path, policy TeePolicy) *Tee {
+	for i := range t.dests {
+		if t.dests[i].path == dest {
+			t.dests[i].policy = policy
+		}
+	}
+
+	return t
+}
+
+// Failed returns the best-effort destinations that were dropped, with the error that caused each.
+func (t *Tee) Failed() map[Path]error {
+	return t.failures
+}
+
+// fail handles an error on the destination, returning the error that stops the Tee, if any.
+func (t *Tee) fail(i int, err error) error {
+	dest := &t.dests[i]
+
+	if dest.tmp != nil {
+		dest.tmp.Close()
+		os.Remove(dest.tmp.Name())
+		dest.tmp = nil
+	}
+
+	if dest.policy == TeeBestEffort {
+		t.failures[dest.path] = err
+		return nil
+	}
+
+	t.err = fmt.Errorf("Cannot write to %s: %w", dest.path, err)
+	t.abort()
+	return t.err
+}
+
+// abort removes the temporary files of every destination.
+func (t *Tee) abort() {
+	for i := range t.dests {
+		if tmp := t.dests[i].tmp; tmp != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+			t.dests[i].tmp = nil
+		}
+	}
+}
+
+func (t *Tee) open() error {
+	t.opened = true
+
+	for i := range t.dests {
+		dest := &t.dests[i]
+		tmp, err := ioutil.TempFile(string(dest.path.Parent()), "."+dest.path.Name()+".tmp")
+
+		if err != nil {
+			if err = t.fail(i, err); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		dest.tmp = tmp
+	}
+
+	return nil
+}
+
+// Write writes the data to every remaining destination.  It fails if a required destination fails, or if every destination has been dropped.
+func (t *Tee) Write(data []byte) (int, error) {
+	if t.err != nil {
+		return 0, t.err
+	}
+
+	if !t.opened {
+		if err := t.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	written := false
+
+	for i := range t.dests {
+		if t.dests[i].tmp == nil {
+			continue
+		}
+
+		if _, err := t.dests[i].tmp.Write(data); err != nil {
+			if err = t.fail(i, err); err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		written = true
+	}
+
+	if !written {
+		t.err = errors.New("Every Tee destination has failed")
+		return 0, t.err
+	}
+
+	return len(data), nil
+}
+
+// Close flushes each destination and renames it into place.  If a required destination fails to flush, none of the destinations are renamed into place and the error is returned.
+func (t *Tee) Close() error {
+	if t.err != nil {
+		return t.err
+	}
+
+	if !t.opened {
+		if err := t.open(); err != nil {
+			return err
+		}
+	}
+
+	for i := range t.dests {
+		tmp := t.dests[i].tmp
+
+		if tmp == nil {
+			continue
+		}
+
+		err := tmp.Sync()
+
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err == nil {
+			err = os.Chmod(tmp.Name(), 0644)
+		}
+
+		if err != nil {
+			if err = t.fail(i, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range t.dests {
+		if tmp := t.dests[i].tmp; tmp != nil {
+			if err := os.Rename(tmp.Name(), string(t.dests[i].path)); err != nil {
+				if err = t.fail(i, err); err != nil {
+					return err
+				}
+			}
+
+			t.dests[i].tmp = nil
+		}
+	}
+
+	if len(t.dests) > 0 && len(t.failures) == len(t.dests) {
+		t.err = errors.New("Every Tee destination has failed")
+		return t.err
+	}
+
+	t.err = os.ErrClosed
+	return nil
+}
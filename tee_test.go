@@ -0,0 +1,56 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTeeWriter(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer root.RmdirRecursive()
+
+	if err := CreateTree(root, TreeSpec{{Path: "a", Dir: true}, {Path: "b", Dir: true}}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	a, b, missing := root.JoinPath("a", "out"), root.JoinPath("b", "out"), root.JoinPath("missing", "out")
+	tee := TeeWriter(a, b, missing).SetPolicy(missing, TeeBestEffort)
+
+	for _, chunk := range []string{"replicated ", "artifact"} {
+		if _, err := tee.Write([]byte(chunk)); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	if a.Exists() {
+		t.Errorf("Destinations should not appear before Close")
+	}
+
+	if err := tee.Close(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for _, dest := range []Path{a, b} {
+		if data, _ := dest.ReadBytes(); string(data) != "replicated artifact" {
+			t.Errorf("Unexpected contents in %s: %q", dest, data)
+		}
+	}
+
+	if _, failed := tee.Failed()[missing]; !failed || len(tee.Failed()) != 1 {
+		t.Errorf("Expected only %s to have failed: %v", missing, tee.Failed())
+	}
+
+	required := TeeWriter(root.JoinPath("a", "other"), missing)
+
+	if _, err := required.Write([]byte("data")); err == nil {
+		t.Errorf("Expected a required destination to fail the write")
+	}
+
+	if required.Close() == nil || root.JoinPath("a", "other").Exists() {
+		t.Errorf("A failed Tee should not write any destination")
+	}
+
+	if entries, _ := root.JoinPath("a").Glob(".*"); len(entries) != 0 {
+		t.Errorf("Temporary files were left behind: %v", entries)
+	}
+}
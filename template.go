@@ -0,0 +1,125 @@
+package pathlib
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// TemplateExt is the extension identifying template files for RenderTreeTemplates.
+const TemplateExt = ".tmpl"
+
+// TemplateOptions controls how templates are parsed and executed.
+type TemplateOptions struct {
+	// HTML uses html/template, with its contextual escaping, instead of text/template.
+	HTML bool
+
+	// Funcs are made available to the template.
+	Funcs map[string]any
+}
+
+// RenderTemplate executes the text/template at the Path with data and writes the result to dest.
+func (p Path) RenderTemplate(dest Path, data any) error {
+	return p.RenderTemplateWithOptions(dest, data, TemplateOptions{})
+}
+
+// RenderTemplateWithOptions executes the template at the Path with data and writes the result to dest, which is given the permissions of the template.
+func (p Path) RenderTemplateWithOptions(dest Path, data any, opts TemplateOptions) error {
+	contents, err := p.ReadBytes()
+
+	if err != nil {
+		return err
+	}
+
+	perms, err := p.Permissions()
+
+	if err != nil {
+		return err
+	}
+
+	var rendered bytes.Buffer
+
+	if opts.HTML {
+		tmpl, err := htmltemplate.New(p.Name()).Funcs(opts.Funcs).Parse(string(contents))
+
+		if err != nil {
+			return err
+		}
+
+		err = tmpl.Execute(&rendered, data)
+
+		if err != nil {
+			return err
+		}
+	} else {
+		tmpl, err := template.New(p.Name()).Funcs(opts.Funcs).Parse(string(contents))
+
+		if err != nil {
+			return err
+		}
+
+		err = tmpl.Execute(&rendered, data)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return dest.writeBytesAtomic(rendered.Bytes(), perms)
+}
+
+// RenderTreeTemplates copies the directory tree at the Path to dest.  Files ending in TemplateExt are rendered with data and written without the extension (eg. main.go.tmpl becomes main.go); all other files are copied as-is.  Permissions are preserved.
+func (p Path) RenderTreeTemplates(dest Path, data any, opts TemplateOptions) error {
+	return filepath.Walk(string(p), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(string(p), path)
+
+		if err != nil {
+			return err
+		}
+
+		target := dest.JoinPath(Path(rel))
+
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(string(target), info.Mode().Perm())
+		case strings.HasSuffix(path, TemplateExt):
+			target = Path(strings.TrimSuffix(string(target), TemplateExt))
+			return Path(path).RenderTemplateWithOptions(target, data, opts)
+		default:
+			return copyFile(Path(path), target, info.Mode().Perm())
+		}
+	})
+}
+
+// copyFile copies the contents of the file src to dst, creating or truncating dst with perms.
+func copyFile(src, dst Path, perms os.FileMode) error {
+	in, err := os.Open(string(src))
+
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	out, err := os.OpenFile(string(dst), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perms)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,112 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	err := dir.Mkdir()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	tmpl := dir.JoinPath("page.html.tmpl")
+	err = tmpl.WriteBytes([]byte("<p>{{.}}</p>"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	err = tmpl.RenderTemplate(dir.JoinPath("text.html"), "<b>")
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	err = tmpl.RenderTemplateWithOptions(dir.JoinPath("escaped.html"), "<b>", TemplateOptions{HTML: true})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	tests := map[Path]string{
+		dir.JoinPath("text.html"):    "<p><b></p>",
+		dir.JoinPath("escaped.html"): "<p>&lt;b&gt;</p>",
+	}
+
+	for p, target := range tests {
+		contents, err := p.ReadBytes()
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+
+		if string(contents) != target {
+			t.Errorf("%s rendered %q, expected %q", p, contents, target)
+		}
+	}
+}
+
+func TestRenderTreeTemplates(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	src := dir.JoinPath("src")
+	dst := dir.JoinPath("dst")
+	err := src.JoinPath("cmd").Mkdir()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer dir.RmdirRecursive()
+
+	err = src.JoinPath("cmd", "main.go.tmpl").WriteBytes([]byte("package {{.Package}}\n"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	script := src.JoinPath("run.sh")
+	err = script.WriteBytes([]byte("#!/bin/sh\n"))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	err = os.Chmod(string(script), 0755)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	err = src.RenderTreeTemplates(dst, map[string]string{"Package": "main"}, TemplateOptions{})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	contents, err := dst.JoinPath("cmd", "main.go").ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if string(contents) != "package main\n" {
+		t.Errorf("Template rendered incorrectly: %q", contents)
+	}
+
+	perms, err := dst.JoinPath("run.sh").Permissions()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if perms != 0755 {
+		t.Errorf("Permissions were not preserved: %s", perms)
+	}
+}
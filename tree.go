@@ -0,0 +1,92 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TreeSpec declares a directory structure for CreateTree.  Entries are created in order.
+type TreeSpec []TreeEntry
+
+// TreeEntry declares a single directory, file, or symbolic link within a TreeSpec.  Path is relative to the root of the tree; a trailing slash also marks the entry as a directory.
+type TreeEntry struct {
+	Path    string      `json:"path" yaml:"path"`
+	Dir     bool        `json:"dir,omitempty" yaml:"dir,omitempty"`
+	Content string      `json:"content,omitempty" yaml:"content,omitempty"`
+	Perms   os.FileMode `json:"perms,omitempty" yaml:"perms,omitempty"`
+	Symlink string      `json:"symlink,omitempty" yaml:"symlink,omitempty"`
+}
+
+// LoadTreeSpec reads a TreeSpec from the Path using LoadConfig, so any registered config format (eg. JSON or YAML) can be used.
+func (p Path) LoadTreeSpec() (TreeSpec, error) {
+	var spec TreeSpec
+	err := p.LoadConfig(&spec)
+	return spec, err
+}
+
+// CreateTree creates the directories, files, and symbolic links declared in the spec beneath root, which is created if needed.  Parent directories are created automatically, directories default to 0755 and files to 0644 permissions, and entries may not escape root.
+func CreateTree(root Path, spec TreeSpec) error {
+	if !root.Exists() {
+		if err := root.Mkdir(); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range spec {
+		target, err := root.SecureJoin(entry.Path)
+
+		if err != nil {
+			return err
+		}
+
+		if target == root {
+			return fmt.Errorf("Tree entry %q does not name anything within %s", entry.Path, root)
+		}
+
+		perms := entry.Perms
+
+		if entry.Dir || strings.HasSuffix(entry.Path, "/") {
+			if perms == 0 {
+				perms = 0755
+			}
+
+			if err := os.MkdirAll(string(target), perms); err != nil {
+				return err
+			}
+
+			if err := os.Chmod(string(target), perms); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := os.MkdirAll(string(target.Parent()), 0755); err != nil {
+			return err
+		}
+
+		if len(entry.Symlink) > 0 {
+			if err := os.Symlink(entry.Symlink, string(target)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if perms == 0 {
+			perms = 0644
+		}
+
+		if err := target.writeBytesAtomic([]byte(entry.Content), perms); err != nil {
+			return err
+		}
+
+		// writeBytesAtomic keeps the permissions of an existing file, but the spec's should win
+		if err := os.Chmod(string(target), perms); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
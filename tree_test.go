@@ -0,0 +1,106 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestCreateTree(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer root.RmdirRecursive()
+
+	spec := TreeSpec{
+		{Path: "cmd/app/main.go", Content: "package main\n"},
+		{Path: "bin/", Perms: 0700},
+		{Path: "bin/run", Content: "#!/bin/sh\n", Perms: 0755},
+		{Path: "latest", Symlink: "bin/run"},
+	}
+
+	err := CreateTree(root, spec)
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	contents, err := root.JoinPath("cmd/app/main.go").ReadBytes()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if string(contents) != "package main\n" {
+		t.Errorf("File content incorrect: %q", contents)
+	}
+
+	perms := map[Path]os.FileMode{
+		root.JoinPath("bin"):             0700,
+		root.JoinPath("bin/run"):         0755,
+		root.JoinPath("cmd/app/main.go"): 0644,
+	}
+
+	for p, target := range perms {
+		actual, err := p.Permissions()
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+
+		if actual != target {
+			t.Errorf("%s has permissions %s, expected %s", p, actual, target)
+		}
+	}
+
+	link, err := os.Readlink(string(root.JoinPath("latest")))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if link != "bin/run" {
+		t.Errorf("Symlink points to %s", link)
+	}
+
+	// creating over an existing tree applies the spec's permissions to the files already there
+	err = CreateTree(root, TreeSpec{{Path: "bin/run", Content: "#!/bin/sh\n", Perms: 0700}})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if actual, _ := root.JoinPath("bin/run").Permissions(); actual != 0700 {
+		t.Errorf("bin/run has permissions %s after recreating, expected %s", actual, os.FileMode(0700))
+	}
+}
+
+func TestLoadTreeSpec(t *testing.T) {
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s.json", randomString(20)))
+	err := p.WriteBytes([]byte(`[{"path": "a/", "dir": true}, {"path": "a/b.txt", "content": "b"}]`))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	defer p.Unlink()
+
+	spec, err := p.LoadTreeSpec()
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if len(spec) != 2 || !spec[0].Dir || spec[1].Content != "b" {
+		t.Errorf("Spec loaded incorrectly: %+v", spec)
+	}
+}
+
+func TestCreateTreeEscape(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer root.RmdirRecursive()
+
+	err := CreateTree(root, TreeSpec{{Path: "../"}})
+
+	if err == nil {
+		t.Errorf("CreateTree should reject entries that do not name anything within the root")
+	}
+}
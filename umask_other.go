@@ -0,0 +1,17 @@
+//go:build !unix
+
+package pathlib
+
+import (
+	"os"
+)
+
+// Umask returns 0, since this platform has no file mode creation mask.
+func Umask() os.FileMode {
+	return 0
+}
+
+// WithUmask runs fn and returns its error.  This platform has no umask, so mask is ignored.
+func WithUmask(mask os.FileMode, fn func() error) error {
+	return fn()
+}
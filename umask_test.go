@@ -0,0 +1,45 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestUmask(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no umask")
+	}
+
+	original := Umask()
+	p := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	err := WithUmask(0077, func() error {
+		if mask := Umask(); mask != 0077 {
+			t.Errorf("Expected umask 0077 inside WithUmask, received %o", mask)
+		}
+
+		return p.Mkdir()
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer p.RmdirRecursive()
+
+	info, err := os.Stat(string(p))
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("Expected permissions 0700, received %o", info.Mode().Perm())
+	}
+
+	if mask := Umask(); mask != original {
+		t.Errorf("Umask was not restored: %o != %o", mask, original)
+	}
+}
@@ -0,0 +1,66 @@
+//go:build unix
+
+package pathlib
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+var (
+	// umaskLock serializes changes to the process-wide umask made through this package.
+	umaskLock sync.Mutex
+
+	// umaskOverride holds the mask set by WithUmask while fn runs, or -1.
+	umaskOverride atomic.Int32
+)
+
+func init() {
+	umaskOverride.Store(-1)
+}
+
+// Umask returns the current file mode creation mask of the process, which removes permission bits from files and directories as they are created.
+func Umask() os.FileMode {
+	// Linux reports the umask without having to change it
+	if status, err := os.ReadFile("/proc/self/status"); err == nil {
+		for _, line := range strings.Split(string(status), "\n") {
+			if value, ok := strings.CutPrefix(line, "Umask:"); ok {
+				if mask, err := strconv.ParseUint(strings.TrimSpace(value), 8, 32); err == nil {
+					return os.FileMode(mask)
+				}
+			}
+		}
+	}
+
+	// elsewhere it has to be changed and restored, which must not race with WithUmask
+	if mask := umaskOverride.Load(); mask >= 0 {
+		return os.FileMode(mask)
+	}
+
+	umaskLock.Lock()
+	defer umaskLock.Unlock()
+
+	mask := syscall.Umask(0)
+	syscall.Umask(mask)
+	return os.FileMode(mask)
+}
+
+// WithUmask runs fn with the umask set to mask, restoring the previous umask afterwards (even if fn panics), and returns the error from fn.  The umask is shared by every goroutine in the process, so files created concurrently elsewhere are affected too; calls to WithUmask itself are serialized.
+func WithUmask(mask os.FileMode, fn func() error) error {
+	umaskLock.Lock()
+	defer umaskLock.Unlock()
+
+	previous := syscall.Umask(int(mask.Perm()))
+	umaskOverride.Store(int32(mask.Perm()))
+
+	defer func() {
+		umaskOverride.Store(-1)
+		syscall.Umask(previous)
+	}()
+
+	return fn()
+}
@@ -0,0 +1,69 @@
+package pathlib
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxNameBytes is the longest name, in bytes, that FromUntrustedName returns, which is the limit of most filesystems.
+const MaxNameBytes = 255
+
+// windowsReservedNames cannot be used as file names on Windows, even with an extension.
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true, "CONIN$": true, "CONOUT$": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// FromUntrustedName turns a filename from an untrusted source, such as an email attachment, HTTP upload, or archive entry, into a Path within dir that is safe to create on any platform.  Directory components are stripped, so the name cannot escape dir; invalid UTF-8, control characters, invisible formatting characters (such as the right-to-left override used to disguise extensions), and characters reserved on Windows are replaced with "_"; leading and trailing dots and spaces are trimmed; Windows device names such as "CON" get a "_" prefix; and the name is shortened to MaxNameBytes, keeping its extension.  The name is not Unicode-normalized, since that needs tables beyond the standard library; names that differ only in normalization (such as "é" composed or decomposed) stay distinct, which matters on filesystems such as APFS that treat them as the same.  If the name is already taken in dir, a numeric suffix is added (eg. "name-1.ext").  Since the Path may be taken between this check and its use, create it with os.O_EXCL.  An error is returned if nothing usable remains of the name.
+func FromUntrustedName(dir Path, name string) (Path, error) {
+	clean := strings.Map(func(r rune) rune {
+		if r == utf8.RuneError || unicode.Is(unicode.Cf, r) {
+			return '_'
+		}
+
+		return r
+	}, strings.ToValidUTF8(name, "_"))
+
+	clean = sanitizeFilename(clean)
+
+	if len(clean) == 0 {
+		return "", fmt.Errorf("Untrusted filename %q has no usable name", name)
+	}
+
+	ext := filepath.Ext(clean)
+	base := strings.TrimSuffix(clean, ext)
+
+	// leave room for the suffix uniquePath may add
+	limit := MaxNameBytes - len("-999")
+
+	if len(ext) > limit/2 {
+		ext = ""
+		base = clean
+	}
+
+	// the prefix is added after shortening, so shortening cannot remove it
+	short := strings.TrimRight(truncateUTF8(base, limit-len(ext)), ". ")
+
+	if windowsReservedNames[strings.ToUpper(strings.TrimRight(strings.SplitN(short, ".", 2)[0], " "))] {
+		short = "_" + strings.TrimRight(truncateUTF8(base, limit-len(ext)-1), ". ")
+	}
+
+	return uniquePath(dir.JoinPath(Path(short + ext))), nil
+}
+
+// truncateUTF8 shortens the string to at most n bytes without splitting a character.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
@@ -0,0 +1,53 @@
+package pathlib
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFromUntrustedName(t *testing.T) {
+	dir := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer dir.RmdirRecursive()
+
+	if err := CreateTree(dir, TreeSpec{{Path: "taken.txt", Content: ""}}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := map[string]string{
+		"report.pdf":                      "report.pdf",
+		"../../etc/passwd":                "passwd",
+		`C:\Users\me\a.txt`:               "a.txt",
+		"invoice\u202Efdp.exe":            "invoice_fdp.exe",
+		"bad\xffutf8.txt":                 "bad_utf8.txt",
+		"CON.txt":                         "_CON.txt",
+		"lpt1":                            "_lpt1",
+		"nul.tar.gz":                      "_nul.tar.gz",
+		"console.txt":                     "console.txt",
+		"taken.txt":                       "taken-1.txt",
+		" trailing dots... ":              "trailing dots",
+		"zero\u200Bwidth.txt":             "zero_width.txt",
+		strings.Repeat("é", 200) + ".jpg": strings.Repeat("é", 123) + ".jpg",
+		"CON." + strings.Repeat("x", 300): "_CON." + strings.Repeat("x", 246),
+	}
+
+	for name, expected := range tests {
+		p, err := FromUntrustedName(dir, name)
+
+		if err != nil {
+			t.Errorf("%q: %s", name, err)
+		} else if p != dir.JoinPath(Path(expected)) {
+			t.Errorf("Expected %q to become %q but was %q", name, expected, p.Name())
+		}
+
+		if len(p.Name()) > MaxNameBytes {
+			t.Errorf("Expected %q to be at most %d bytes but was %d", name, MaxNameBytes, len(p.Name()))
+		}
+	}
+
+	for _, name := range []string{"", "..", "/", " . "} {
+		if p, err := FromUntrustedName(dir, name); err == nil {
+			t.Errorf("Expected an error for %q but got %s", name, p)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package pathlib
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// SchemeOpener returns the Filesystem and the name within it for a URI with a registered scheme.
+type SchemeOpener func(u *url.URL) (Filesystem, string, error)
+
+var (
+	schemesLock sync.RWMutex
+	schemes     = map[string]SchemeOpener{
+		"file": openFileURI,
+		"mem":  openMemURI,
+	}
+
+	memFilesystemsLock sync.Mutex
+	memFilesystems     = make(map[string]*MemFilesystem)
+)
+
+// RegisterScheme makes Open dispatch URIs with the scheme (eg. "s3", "gs", or "sftp") to the opener, which is how remote backends plug in.  Registering a scheme again replaces its opener, and registering nil removes it.
+func RegisterScheme(scheme string, opener SchemeOpener) {
+	schemesLock.Lock()
+	defer schemesLock.Unlock()
+
+	if opener == nil {
+		delete(schemes, strings.ToLower(scheme))
+		return
+	}
+
+	schemes[strings.ToLower(scheme)] = opener
+}
+
+// Open returns the FSPath named by the URI, bound to the backend for its scheme, so one code path can handle every storage target.  "file://" URIs and plain paths use the OS Filesystem.  "mem://name/path" uses an in-memory Filesystem shared by every URI with the same name, which is handy in tests.  Other schemes, such as "s3://", must be registered with RegisterScheme first.
+func Open(uri string) (FSPath, error) {
+	u, err := url.Parse(uri)
+
+	// plain paths, including Windows ones like C:\dir, which parse with a one-letter scheme
+	if err != nil || len(u.Scheme) <= 1 {
+		return PathOn(OS, uri), nil
+	}
+
+	schemesLock.RLock()
+	opener, ok := schemes[strings.ToLower(u.Scheme)]
+	schemesLock.RUnlock()
+
+	if !ok {
+		return FSPath{}, fmt.Errorf("No backend is registered for scheme %q: %s", u.Scheme, uri)
+	}
+
+	fsys, name, err := opener(u)
+
+	if err != nil {
+		return FSPath{}, err
+	}
+
+	return PathOn(fsys, name), nil
+}
+
+func openFileURI(u *url.URL) (Filesystem, string, error) {
+	if u.Host != "" && u.Host != "localhost" {
+		return nil, "", fmt.Errorf("Cannot open file URI on remote host %s", u.Host)
+	}
+
+	name := u.Path
+
+	// file:///C:/dir names a Windows drive
+	if len(name) >= 3 && name[0] == '/' && name[2] == ':' {
+		name = name[1:]
+	}
+
+	return OS, filepath.FromSlash(name), nil
+}
+
+func openMemURI(u *url.URL) (Filesystem, string, error) {
+	memFilesystemsLock.Lock()
+	defer memFilesystemsLock.Unlock()
+
+	mem, ok := memFilesystems[u.Host]
+
+	if !ok {
+		mem = NewMemFilesystem()
+		memFilesystems[u.Host] = mem
+	}
+
+	return mem, u.Path, nil
+}
@@ -0,0 +1,56 @@
+package pathlib
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+func TestOpenURI(t *testing.T) {
+	local, err := Open("file:///etc/passwd")
+
+	if err != nil || local.Filesystem() != OS || local.Path() != "/etc/passwd" || !local.IsFile() {
+		t.Errorf("Unexpected FSPath %s: %v", local, err)
+	}
+
+	if plain, err := Open("/etc/passwd"); err != nil || plain != local {
+		t.Errorf("Expected a plain path to match the file URI, received %s: %v", plain, err)
+	}
+
+	name := randomString(20)
+	written, err := Open(fmt.Sprintf("mem://%s/dir/file.txt", name))
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	written.Parent().Mkdir()
+	written.WriteBytes([]byte("in memory"))
+
+	// the same name shares a Filesystem, while other names are separate
+	read, _ := Open(fmt.Sprintf("mem://%s/dir/file.txt", name))
+
+	if contents, err := read.ReadBytes(); err != nil || string(contents) != "in memory" {
+		t.Errorf("Expected to read back the memory file, received %q: %v", contents, err)
+	}
+
+	if other, _ := Open("mem://other-" + name + "/dir/file.txt"); other.Exists() {
+		t.Errorf("Differently named memory filesystems should be separate")
+	}
+
+	if _, err = Open("bogus://bucket/key"); err == nil {
+		t.Errorf("Expected an error for an unregistered scheme")
+	}
+
+	mem := NewMemFilesystem()
+	RegisterScheme("bogus", func(u *url.URL) (Filesystem, string, error) {
+		return mem, u.Host + u.Path, nil
+	})
+
+	defer RegisterScheme("bogus", nil)
+	bucket, err := Open("bogus://bucket/key")
+
+	if err != nil || bucket.Filesystem() != mem || bucket.String() != "bucket/key" {
+		t.Errorf("Unexpected FSPath %s: %v", bucket, err)
+	}
+}
@@ -0,0 +1,90 @@
+package pathlib
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+	"unicode/utf16"
+)
+
+// ErrUSNCheckpointInvalid is returned by USNChanges when the checkpoint can no longer be used, because the journal was recreated or has wrapped past it.  The caller must fall back to a full scan and take a new checkpoint.
+var ErrUSNCheckpointInvalid = errors.New("USN checkpoint is no longer valid")
+
+// USNCheckpoint records a position within an NTFS volume's update sequence number (USN) change journal.  The zero value asks USNChanges for the current position without returning any changes.
+type USNCheckpoint struct {
+	JournalID uint64 `json:"journal_id"`
+	USN       int64  `json:"usn"`
+}
+
+// USNChange is a single entry from the USN change journal.  The journal records names and NTFS file reference numbers rather than full paths, so ParentID can be used to relate changes to directories.
+type USNChange struct {
+	FileID   uint64
+	ParentID uint64
+	Name     string
+	Reason   uint32
+	Time     time.Time
+	IsDir    bool
+}
+
+// fileAttributeDirectory is FILE_ATTRIBUTE_DIRECTORY.
+const fileAttributeDirectory = 0x10
+
+// USN change journal reason flags (see the Windows USN_RECORD documentation).
+const (
+	USNReasonDataOverwrite  = 0x00000001
+	USNReasonDataExtend     = 0x00000002
+	USNReasonDataTruncation = 0x00000004
+	USNReasonFileCreate     = 0x00000100
+	USNReasonFileDelete     = 0x00000200
+	USNReasonRenameOldName  = 0x00001000
+	USNReasonRenameNewName  = 0x00002000
+	USNReasonBasicInfo      = 0x00008000
+	USNReasonClose          = 0x80000000
+)
+
+// parseUSNRecords decodes the USN_RECORD_V2 entries in the buffer.
+func parseUSNRecords(buf []byte) []USNChange {
+	var changes []USNChange
+
+	for len(buf) >= 60 {
+		length := int(binary.LittleEndian.Uint32(buf[0:4]))
+
+		if length < 60 || length > len(buf) {
+			break
+		}
+
+		record := buf[:length]
+		buf = buf[length:]
+
+		if binary.LittleEndian.Uint16(record[4:6]) != 2 {
+			continue // only version 2 records are understood
+		}
+
+		nameLength := int(binary.LittleEndian.Uint16(record[56:58]))
+		nameOffset := int(binary.LittleEndian.Uint16(record[58:60]))
+
+		if nameOffset+nameLength > length {
+			continue
+		}
+
+		name := make([]uint16, nameLength/2)
+
+		for i := range name {
+			name[i] = binary.LittleEndian.Uint16(record[nameOffset+2*i:])
+		}
+
+		// FILETIME counts 100ns intervals since 1601
+		filetime := int64(binary.LittleEndian.Uint64(record[32:40]))
+
+		changes = append(changes, USNChange{
+			FileID:   binary.LittleEndian.Uint64(record[8:16]),
+			ParentID: binary.LittleEndian.Uint64(record[16:24]),
+			Name:     string(utf16.Decode(name)),
+			Reason:   binary.LittleEndian.Uint32(record[40:44]),
+			Time:     time.Unix(0, (filetime-116444736000000000)*100),
+			IsDir:    binary.LittleEndian.Uint32(record[52:56])&fileAttributeDirectory != 0,
+		})
+	}
+
+	return changes
+}
@@ -0,0 +1,12 @@
+//go:build !windows
+
+package pathlib
+
+import (
+	"fmt"
+)
+
+// USNChanges is only supported on Windows, where NTFS volumes keep a USN change journal.
+func (p Path) USNChanges(since USNCheckpoint) ([]USNChange, USNCheckpoint, error) {
+	return nil, since, fmt.Errorf("USN change journals are only supported on Windows: %s", p)
+}
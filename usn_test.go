@@ -0,0 +1,42 @@
+package pathlib
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+func TestParseUSNRecords(t *testing.T) {
+	name := utf16.Encode([]rune("report.txt"))
+	record := make([]byte, 64+2*len(name)-4)
+	binary.LittleEndian.PutUint32(record[0:4], uint32(len(record)))
+	binary.LittleEndian.PutUint16(record[4:6], 2)
+	binary.LittleEndian.PutUint64(record[8:16], 42)
+	binary.LittleEndian.PutUint64(record[16:24], 5)
+	binary.LittleEndian.PutUint64(record[32:40], 116444736000000000+10000000) // one second after the Unix epoch
+	binary.LittleEndian.PutUint32(record[40:44], USNReasonFileCreate|USNReasonClose)
+	binary.LittleEndian.PutUint16(record[56:58], uint16(2*len(name)))
+	binary.LittleEndian.PutUint16(record[58:60], 60)
+
+	for i, c := range name {
+		binary.LittleEndian.PutUint16(record[60+2*i:], c)
+	}
+
+	changes := parseUSNRecords(append(record, record...))
+
+	if len(changes) != 2 {
+		t.Errorf("Expected 2 changes, received %d", len(changes))
+		return
+	}
+
+	change := changes[0]
+
+	if change.FileID != 42 || change.ParentID != 5 || change.Name != "report.txt" || change.IsDir {
+		t.Errorf("Record parsed incorrectly: %+v", change)
+	}
+
+	if change.Reason&USNReasonFileCreate == 0 || !change.Time.Equal(time.Unix(1, 0)) {
+		t.Errorf("Record parsed incorrectly: %+v", change)
+	}
+}
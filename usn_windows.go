@@ -0,0 +1,103 @@
+package pathlib
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	fsctlQueryUSNJournal = 0x000900f4
+	fsctlReadUSNJournal  = 0x000900bb
+	usnBufferSize        = 64 * 1024
+)
+
+// usnJournalData mirrors USN_JOURNAL_DATA_V0.
+type usnJournalData struct {
+	UsnJournalID    uint64
+	FirstUsn        int64
+	NextUsn         int64
+	LowestValidUsn  int64
+	MaxUsn          int64
+	MaximumSize     uint64
+	AllocationDelta uint64
+}
+
+// readUSNJournalData mirrors READ_USN_JOURNAL_DATA_V0.
+type readUSNJournalData struct {
+	StartUsn          int64
+	ReasonMask        uint32
+	ReturnOnlyOnClose uint32
+	Timeout           uint64
+	BytesToWaitFor    uint64
+	UsnJournalID      uint64
+}
+
+// USNChanges returns the changes recorded in the NTFS change journal of the volume holding the Path since the checkpoint, along with a new checkpoint to pass next time.  This allows fast incremental scans of huge volumes without walking them.  Reading the journal requires administrator privileges.
+func (p Path) USNChanges(since USNCheckpoint) ([]USNChange, USNCheckpoint, error) {
+	absPath, err := filepath.Abs(string(p))
+
+	if err != nil {
+		return nil, since, err
+	}
+
+	volume, err := syscall.UTF16PtrFromString(`\\.\` + filepath.VolumeName(absPath))
+
+	if err != nil {
+		return nil, since, err
+	}
+
+	handle, err := syscall.CreateFile(volume, syscall.GENERIC_READ, syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE, nil, syscall.OPEN_EXISTING, 0, 0)
+
+	if err != nil {
+		return nil, since, err
+	}
+
+	defer syscall.CloseHandle(handle)
+
+	var journal usnJournalData
+	var returned uint32
+	err = syscall.DeviceIoControl(handle, fsctlQueryUSNJournal, nil, 0, (*byte)(unsafe.Pointer(&journal)), uint32(unsafe.Sizeof(journal)), &returned, nil)
+
+	if err != nil {
+		return nil, since, err
+	}
+
+	current := USNCheckpoint{JournalID: journal.UsnJournalID, USN: journal.NextUsn}
+
+	if since.JournalID == 0 {
+		return nil, current, nil
+	}
+
+	if since.JournalID != journal.UsnJournalID || since.USN < journal.LowestValidUsn {
+		return nil, since, ErrUSNCheckpointInvalid
+	}
+
+	var changes []USNChange
+	buf := make([]byte, usnBufferSize)
+	read := readUSNJournalData{StartUsn: since.USN, ReasonMask: 0xffffffff, UsnJournalID: journal.UsnJournalID}
+
+	for read.StartUsn < journal.NextUsn {
+		err = syscall.DeviceIoControl(handle, fsctlReadUSNJournal, (*byte)(unsafe.Pointer(&read)), uint32(unsafe.Sizeof(read)), &buf[0], uint32(len(buf)), &returned, nil)
+
+		if err != nil {
+			return nil, since, err
+		}
+
+		if returned < 8 {
+			break
+		}
+
+		next := int64(binary.LittleEndian.Uint64(buf[0:8]))
+		changes = append(changes, parseUSNRecords(buf[8:returned])...)
+
+		if next == read.StartUsn {
+			break
+		}
+
+		read.StartUsn = next
+	}
+
+	return changes, USNCheckpoint{JournalID: journal.UsnJournalID, USN: read.StartUsn}, nil
+}
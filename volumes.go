@@ -0,0 +1,107 @@
+package pathlib
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// VolumeOp describes the kind of change reported by WatchVolumes.
+type VolumeOp int
+
+const (
+	// VolumeMounted is reported when a volume appears, such as a USB drive being mounted.
+	VolumeMounted VolumeOp = iota + 1
+
+	// VolumeUnmounted is reported when a volume goes away.
+	VolumeUnmounted
+)
+
+func (op VolumeOp) String() string {
+	switch op {
+	case VolumeMounted:
+		return "mounted"
+	case VolumeUnmounted:
+		return "unmounted"
+	}
+
+	return "unknown"
+}
+
+// VolumeEvent is a change reported by WatchVolumes.
+type VolumeEvent struct {
+	Op         VolumeOp
+	MountPoint Path
+	Device     Path
+}
+
+// WatchVolumes reports volumes being mounted and unmounted, by comparing the mount table every interval.  On Linux only mounts of block devices are reported, so virtual filesystems such as tmpfs are ignored; on Windows, drive letters are reported.  Volumes that are already mounted when watching starts are not reported.  The channel is closed when ctx is done.
+func WatchVolumes(ctx context.Context, interval time.Duration) (<-chan VolumeEvent, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("WatchVolumes interval must be positive, not %s", interval)
+	}
+
+	previous, err := mountedVolumes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan VolumeEvent)
+
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			current, err := mountedVolumes()
+
+			if err != nil {
+				continue
+			}
+
+			for _, event := range diffVolumes(previous, current) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			previous = current
+		}
+	}()
+
+	return events, nil
+}
+
+// diffVolumes compares two mount tables, which map mount points to devices, returning the unmounts and then the mounts, each sorted by mount point.  A mount point whose device changed is reported as both.
+func diffVolumes(previous, current map[Path]Path) []VolumeEvent {
+	var unmounted, mounted []VolumeEvent
+
+	for mountPoint, device := range previous {
+		if current[mountPoint] != device {
+			unmounted = append(unmounted, VolumeEvent{Op: VolumeUnmounted, MountPoint: mountPoint, Device: device})
+		}
+	}
+
+	for mountPoint, device := range current {
+		if previous[mountPoint] != device {
+			mounted = append(mounted, VolumeEvent{Op: VolumeMounted, MountPoint: mountPoint, Device: device})
+		}
+	}
+
+	for _, events := range [][]VolumeEvent{unmounted, mounted} {
+		sort.Slice(events, func(i, j int) bool { return events[i].MountPoint < events[j].MountPoint })
+	}
+
+	return append(unmounted, mounted...)
+}
@@ -0,0 +1,61 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// sysDevBlockRoot links device numbers to their sysfs directories; it is a variable so tests can point it at a fixture.
+var sysDevBlockRoot = Path("/sys/dev/block")
+
+// IsRemovable returns true if the Path resides on removable media, such as a USB drive or SD card.  Devices attached over USB count as removable even if they report fixed media, as many USB disks do.  Paths on virtual or network filesystems, which have no backing block device, are not removable.
+func (p Path) IsRemovable() (bool, error) {
+	info, err := os.Stat(string(p))
+
+	if err != nil {
+		return false, err
+	}
+
+	dev := uint64(info.Sys().(*syscall.Stat_t).Dev)
+	major := (dev>>8)&0xfff | (dev>>32)&^0xfff
+	minor := dev&0xff | (dev>>12)&^0xff
+	sys, err := filepath.EvalSymlinks(string(sysDevBlockRoot.JoinPath(Path(fmt.Sprintf("%d:%d", major, minor)))))
+
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	disk := Path(sys)
+
+	if disk.JoinPath("partition").Exists() {
+		disk = disk.Parent()
+	}
+
+	return sysfsString(disk.JoinPath("removable")) == "1" || strings.Contains(sys, "/usb"), nil
+}
+
+// mountedVolumes maps the mount points of block devices to their devices.
+func mountedVolumes() (map[Path]Path, error) {
+	mounts, err := readMountInfo(mountInfoPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make(map[Path]Path)
+
+	for _, mount := range mounts {
+		if strings.HasPrefix(mount.source, "/dev/") {
+			volumes[mount.mountPoint] = Path(mount.source)
+		}
+	}
+
+	return volumes, nil
+}
@@ -0,0 +1,95 @@
+package pathlib
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestIsRemovable(t *testing.T) {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer root.RmdirRecursive()
+
+	err := CreateTree(root, TreeSpec{
+		{Path: "devices/usb1/block/sdb/removable", Content: "0\n"},
+		{Path: "devices/usb1/block/sdb/sdb1/partition", Content: "1\n"},
+		{Path: "devices/pci/block/sda/removable", Content: "0\n"},
+		{Path: "devices/pci/block/sr0/removable", Content: "1\n"},
+		{Path: "file", Content: "on some device"},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	info, _ := os.Stat(string(root.JoinPath("file")))
+	dev := uint64(info.Sys().(*syscall.Stat_t).Dev)
+	devNum := fmt.Sprintf("%d:%d", (dev>>8)&0xfff|(dev>>32)&^0xfff, dev&0xff|(dev>>12)&^0xff)
+	tests := map[string]bool{
+		"devices/usb1/block/sdb/sdb1": true,
+		"devices/pci/block/sda":       false,
+		"devices/pci/block/sr0":       true,
+	}
+
+	defer func(original Path) { sysDevBlockRoot = original }(sysDevBlockRoot)
+	sysDevBlockRoot = root.JoinPath("dev")
+
+	for target, expected := range tests {
+		sysDevBlockRoot.RmdirRecursive()
+		sysDevBlockRoot.Mkdir()
+
+		if err = os.Symlink(string(root.JoinPath(Path(target))), string(sysDevBlockRoot.JoinPath(Path(devNum)))); err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		removable, err := root.JoinPath("file").IsRemovable()
+
+		if err != nil || removable != expected {
+			t.Errorf("%s: expected %v, received %v: %v", target, expected, removable, err)
+		}
+	}
+
+	sysDevBlockRoot.RmdirRecursive()
+	sysDevBlockRoot.Mkdir()
+
+	if removable, err := root.JoinPath("file").IsRemovable(); err != nil || removable {
+		t.Errorf("Expected a Path without a block device not to be removable: %v", err)
+	}
+}
+
+func TestWatchVolumes(t *testing.T) {
+	fixture := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+	defer fixture.Unlink()
+
+	err := fixture.WriteBytes([]byte("23 28 0:22 / /proc rw - proc proc rw\n30 1 253:1 / / rw - ext4 /dev/vda1 rw\n"))
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	defer func(original Path) { mountInfoPath = original }(mountInfoPath)
+	mountInfoPath = fixture
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err = WatchVolumes(ctx, 0); err == nil {
+		t.Errorf("Expected an error for a zero interval")
+	}
+
+	events, err := WatchVolumes(ctx, 10*time.Millisecond)
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	fixture.writeBytesAtomic([]byte("30 1 253:1 / / rw - ext4 /dev/vda1 rw\n41 30 8:17 / /media/USB\\040Stick rw,nosuid - vfat /dev/sdb1 rw\n42 30 0:40 / /run/user tmpfs rw - tmpfs tmpfs rw\n"), 0644)
+	event := <-events
+
+	if event.Op != VolumeMounted || event.MountPoint != "/media/USB Stick" || event.Device != "/dev/sdb1" {
+		t.Errorf("Unexpected event %+v", event)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !linux && !windows
+
+package pathlib
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// IsRemovable is only supported on Linux and Windows.
+func (p Path) IsRemovable() (bool, error) {
+	return false, fmt.Errorf("IsRemovable is not supported on %s: %s", runtime.GOOS, p)
+}
+
+func mountedVolumes() (map[Path]Path, error) {
+	return nil, fmt.Errorf("WatchVolumes is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,22 @@
+package pathlib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffVolumes(t *testing.T) {
+	previous := map[Path]Path{"/": "/dev/vda1", "/media/usb": "/dev/sdb1", "/mnt": "/dev/sdc1"}
+	current := map[Path]Path{"/": "/dev/vda1", "/media/sd": "/dev/mmcblk0p1", "/mnt": "/dev/sdd1"}
+	events := diffVolumes(previous, current)
+
+	target := "[{unmounted /media/usb /dev/sdb1} {unmounted /mnt /dev/sdc1} {mounted /media/sd /dev/mmcblk0p1} {mounted /mnt /dev/sdd1}]"
+
+	if fmt.Sprint(events) != target {
+		t.Errorf("Unexpected events %v", events)
+	}
+
+	if len(diffVolumes(current, current)) != 0 {
+		t.Errorf("Expected no events for an unchanged mount table")
+	}
+}
@@ -0,0 +1,43 @@
+package pathlib
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var procGetVolumePathNameW = kernel32.NewProc("GetVolumePathNameW")
+
+// IsRemovable returns true if the Path resides on a removable drive, such as a USB drive, SD card, or optical disc.
+func (p Path) IsRemovable() (bool, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(string(p))
+
+	if err != nil {
+		return false, err
+	}
+
+	root := make([]uint16, syscall.MAX_PATH)
+
+	if ok, _, err := procGetVolumePathNameW.Call(uintptr(unsafe.Pointer(pathPtr)), uintptr(unsafe.Pointer(&root[0])), uintptr(len(root))); ok == 0 {
+		return false, err
+	}
+
+	driveType, _, _ := procGetDriveTypeW.Call(uintptr(unsafe.Pointer(&root[0])))
+	return driveType == driveRemovable || driveType == driveCDROM, nil
+}
+
+// mountedVolumes maps drive roots to their device paths.
+func mountedVolumes() (map[Path]Path, error) {
+	devices, err := BlockDevices()
+
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make(map[Path]Path, len(devices))
+
+	for _, device := range devices {
+		volumes[device.MountPoints[0]] = device.Path
+	}
+
+	return volumes, nil
+}
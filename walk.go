@@ -0,0 +1,204 @@
+package pathlib
+
+import (
+	"fmt"
+	"iter"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Walk walks the tree rooted at the Path like filepath.Walk, calling fn for each file or directory in lexical order, including the Path itself.  fn may return filepath.SkipDir to skip a directory.
+func (p Path) Walk(fn func(Path, os.FileInfo, error) error) error {
+	return filepath.Walk(string(p), func(path string, info os.FileInfo, err error) error {
+		return fn(Path(path), info, err)
+	})
+}
+
+// WalkOption configures Iter.
+type WalkOption func(*walkOptions)
+
+type walkOptions struct {
+	maxDepth       int
+	skipHidden     bool
+	followSymlinks bool
+}
+
+// MaxDepth limits Iter to Paths at most depth levels below the root, so MaxDepth(1) lists only the directory's own entries.  Zero means no limit.
+func MaxDepth(depth int) WalkOption {
+	return func(o *walkOptions) {
+		o.maxDepth = depth
+	}
+}
+
+// SkipHidden makes Iter skip files and directories whose names start with a dot.
+func SkipHidden() WalkOption {
+	return func(o *walkOptions) {
+		o.skipHidden = true
+	}
+}
+
+// FollowSymlinks makes Iter descend into symlinked directories.  Symlinks that lead back to a directory being walked are not followed again.
+func FollowSymlinks() WalkOption {
+	return func(o *walkOptions) {
+		o.followSymlinks = true
+	}
+}
+
+// Iter returns an iterator over every Path below the directory Path (not including the Path itself), in lexical order.  Unlike Walk, it can be ranged over and stopped at any point, and nothing is buffered beyond the directory being read.  Errors reading a directory are yielded along with its Path, and iteration continues with the next entry.
+func (p Path) Iter(opts ...WalkOption) iter.Seq2[Path, error] {
+	var o walkOptions
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(yield func(Path, error) bool) {
+		info, err := os.Stat(string(p))
+
+		if err != nil {
+			yield(p, err)
+			return
+		}
+
+		if !info.IsDir() {
+			yield(p, fmt.Errorf("Iter only works on directories: %s", p))
+			return
+		}
+
+		iterDir(p, 1, o, []os.FileInfo{info}, yield)
+	}
+}
+
+// iterDir yields the entries of dir and recurses into its subdirectories, returning false once the consumer stops.  ancestors holds the directories being walked, to detect symlink loops.
+func iterDir(dir Path, depth int, o walkOptions, ancestors []os.FileInfo, yield func(Path, error) bool) bool {
+	entries, err := os.ReadDir(string(dir))
+
+	if err != nil {
+		return yield(dir, err)
+	}
+
+	for _, entry := range entries {
+		if o.skipHidden && strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		child := dir.JoinPath(Path(entry.Name()))
+
+		if !yield(child, nil) {
+			return false
+		}
+
+		if o.maxDepth > 0 && depth >= o.maxDepth {
+			continue
+		}
+
+		descend := entry.IsDir()
+		isLink := entry.Type()&os.ModeSymlink != 0
+
+		if !descend && !(isLink && o.followSymlinks) {
+			continue
+		}
+
+		if o.followSymlinks {
+			info, err := os.Stat(string(child))
+
+			if err != nil || !info.IsDir() || isAncestor(ancestors, info) {
+				continue
+			}
+
+			if !iterDir(child, depth+1, o, append(ancestors, info), yield) {
+				return false
+			}
+		} else if !iterDir(child, depth+1, o, ancestors, yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isAncestor(ancestors []os.FileInfo, info os.FileInfo) bool {
+	for _, ancestor := range ancestors {
+		if os.SameFile(ancestor, info) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RGlob returns the Paths below the directory that match the pattern, searching recursively.  Within the pattern, "**" matches any number of directories (including none), and the other components match single names as in Glob.  The pattern is matched anywhere in the tree, so RGlob("*.log") finds every .log file.  For very large trees, filter Iter instead to avoid building the whole list.
+func (p Path) RGlob(pattern string) ([]Path, error) {
+	if !p.IsDir() {
+		return nil, fmt.Errorf("RGlob only works on directories: %s", p)
+	}
+
+	patternParts := strings.Split(path.Clean("**/"+filepath.ToSlash(pattern)), "/")
+
+	for _, part := range patternParts {
+		if _, err := filepath.Match(part, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	var matches []Path
+
+	for match, err := range p.Iter() {
+		if err != nil {
+			if match == p {
+				return nil, err
+			}
+
+			continue // unreadable directories are skipped, as in Glob
+		}
+
+		relative, err := filepath.Rel(string(p), string(match))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if matchGlobParts(patternParts, strings.Split(filepath.ToSlash(relative), "/")) {
+			matches = append(matches, match)
+		}
+	}
+
+	return matches, nil
+}
+
+// matchGlobParts reports whether the path components match the pattern components, where a "**" component matches zero or more path components.
+func matchGlobParts(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for len(pattern) > 0 && pattern[0] == "**" {
+				pattern = pattern[1:]
+			}
+
+			if len(pattern) == 0 {
+				return true
+			}
+
+			for i := range parts {
+				if matchGlobParts(pattern, parts[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(parts) == 0 {
+			return false
+		}
+
+		if matched, _ := filepath.Match(pattern[0], parts[0]); !matched {
+			return false
+		}
+
+		pattern, parts = pattern[1:], parts[1:]
+	}
+
+	return len(parts) == 0
+}
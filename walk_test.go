@@ -0,0 +1,154 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func walkTestTree(t *testing.T) Path {
+	root := Path(fmt.Sprintf("/tmp/pathlib-%s", randomString(20)))
+
+	err := CreateTree(root, TreeSpec{
+		{Path: "a.log"}, {Path: "b/c.log"}, {Path: "b/d/e.log"}, {Path: "b/d/f.txt"},
+		{Path: ".hidden/g.log"}, {Path: "loop", Symlink: "b"},
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	return root
+}
+
+func TestWalk(t *testing.T) {
+	root := walkTestTree(t)
+	defer root.RmdirRecursive()
+
+	var seen []Path
+
+	err := root.Walk(func(p Path, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if p.Name() == ".hidden" {
+			return filepath.SkipDir
+		}
+
+		seen = append(seen, p)
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	if len(seen) != 8 || seen[0] != root {
+		t.Errorf("Unexpected walk: %v", seen)
+	}
+}
+
+func TestIter(t *testing.T) {
+	root := walkTestTree(t)
+	defer root.RmdirRecursive()
+
+	count := func(opts ...WalkOption) int {
+		n := 0
+
+		for _, err := range root.Iter(opts...) {
+			if err != nil {
+				t.Errorf(err.Error())
+			}
+
+			n++
+		}
+
+		return n
+	}
+
+	tests := map[string]struct {
+		opts     []WalkOption
+		expected int
+	}{
+		"all":              {nil, 9},
+		"depth":            {[]WalkOption{MaxDepth(1)}, 4},
+		"hidden":           {[]WalkOption{SkipHidden()}, 7},
+		"symlinks":         {[]WalkOption{FollowSymlinks()}, 13},
+		"symlinks, depth":  {[]WalkOption{FollowSymlinks(), MaxDepth(2)}, 9},
+		"symlinks, hidden": {[]WalkOption{FollowSymlinks(), SkipHidden()}, 11},
+	}
+
+	for name, test := range tests {
+		if n := count(test.opts...); n != test.expected {
+			t.Errorf("%s: expected %d Paths, received %d", name, test.expected, n)
+		}
+	}
+
+	for p := range root.Iter() {
+		if p != root.JoinPath(".hidden") {
+			t.Errorf("Expected iteration to start at .hidden, received %s", p)
+		}
+
+		break
+	}
+}
+
+func TestIterSymlinkLoop(t *testing.T) {
+	root := walkTestTree(t)
+	defer root.RmdirRecursive()
+
+	err := os.Symlink("..", string(root.JoinPath("b", "up")))
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	n := 0
+
+	for _, err := range root.Iter(FollowSymlinks()) {
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+
+		n++
+	}
+
+	if n != 15 {
+		t.Errorf("Expected 15 Paths, received %d", n)
+	}
+}
+
+func TestRGlob(t *testing.T) {
+	root := walkTestTree(t)
+	defer root.RmdirRecursive()
+
+	tests := map[string][]Path{
+		"*.log":       {root.JoinPath(".hidden", "g.log"), root.JoinPath("a.log"), root.JoinPath("b", "c.log"), root.JoinPath("b", "d", "e.log")},
+		"b/**/*.log":  {root.JoinPath("b", "c.log"), root.JoinPath("b", "d", "e.log")},
+		"d/*":         {root.JoinPath("b", "d", "e.log"), root.JoinPath("b", "d", "f.txt")},
+		"**/b/d/f.*":  {root.JoinPath("b", "d", "f.txt")},
+		"nothing.txt": nil,
+	}
+
+	for pattern, expected := range tests {
+		matches, err := root.RGlob(pattern)
+
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+
+		if fmt.Sprint(matches) != fmt.Sprint(expected) {
+			t.Errorf("%s: expected %v, received %v", pattern, expected, matches)
+		}
+	}
+
+	if _, err := root.RGlob("[a"); err == nil {
+		t.Errorf("Expected an error for a malformed pattern")
+	}
+
+	if _, err := root.JoinPath("a.log").RGlob("*"); err == nil {
+		t.Errorf("Expected an error for RGlob on a file")
+	}
+}
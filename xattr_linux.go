@@ -0,0 +1,84 @@
+package pathlib
+
+import (
+	"iter"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// copyXattrs copies the extended attributes of the regular file or directory src to dst, yielding each attribute that could not be copied.  An error listing the attributes is yielded with an empty name.
+func copyXattrs(src, dst Path) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		names, err := listXattrs(src)
+
+		if err != nil {
+			if err != syscall.ENOTSUP {
+				yield("", &os.PathError{Op: "listxattr", Path: string(src), Err: err})
+			}
+
+			return
+		}
+
+		for _, name := range names {
+			value, err := getXattr(src, name)
+
+			if err == nil {
+				err = syscall.Setxattr(string(dst), name, value, 0)
+			}
+
+			if err != nil && !yield(name, &os.PathError{Op: "setxattr", Path: string(dst), Err: err}) {
+				return
+			}
+		}
+	}
+}
+
+func listXattrs(p Path) ([]string, error) {
+	size, err := syscall.Listxattr(string(p), nil)
+
+	if err != nil || size == 0 {
+		return nil, err
+	}
+
+	buf := make([]byte, size)
+
+	if size, err = syscall.Listxattr(string(p), buf); err != nil {
+		return nil, err
+	}
+
+	return strings.FieldsFunc(string(buf[:size]), func(r rune) bool { return r == 0 }), nil
+}
+
+func getXattr(p Path, name string) ([]byte, error) {
+	size, err := syscall.Getxattr(string(p), name, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, size)
+
+	if size, err = syscall.Getxattr(string(p), name, buf); err != nil {
+		return nil, err
+	}
+
+	return buf[:size], nil
+}
+
+func setXattr(p Path, name string, value []byte) error {
+	if err := syscall.Setxattr(string(p), name, value, 0); err != nil {
+		return &os.PathError{Op: "setxattr", Path: string(p), Err: err}
+	}
+
+	return nil
+}
+
+// removeXattr removes the attribute, succeeding if it was not set.
+func removeXattr(p Path, name string) error {
+	if err := syscall.Removexattr(string(p), name); err != nil && err != syscall.ENODATA && err != syscall.ENOTSUP {
+		return &os.PathError{Op: "removexattr", Path: string(p), Err: err}
+	}
+
+	return nil
+}
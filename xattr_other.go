@@ -0,0 +1,30 @@
+//go:build !linux
+
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+)
+
+// copyXattrs yields a single error, since extended attributes are only copied on Linux.
+func copyXattrs(src, dst Path) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		yield("", fmt.Errorf("Copying extended attributes is not supported on this platform: %s", src))
+	}
+}
+
+// getXattr, setXattr, and removeXattr fail with errors.ErrUnsupported, since extended attributes are only supported on Linux.
+func getXattr(p Path, name string) ([]byte, error) {
+	return nil, &os.PathError{Op: "getxattr", Path: string(p), Err: errors.ErrUnsupported}
+}
+
+func setXattr(p Path, name string, value []byte) error {
+	return &os.PathError{Op: "setxattr", Path: string(p), Err: errors.ErrUnsupported}
+}
+
+func removeXattr(p Path, name string) error {
+	return nil
+}